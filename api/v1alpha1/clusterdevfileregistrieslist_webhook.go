@@ -17,76 +17,21 @@
 package v1alpha1
 
 import (
-	"context"
-	"fmt"
-
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
-	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // log is for logging in this package.
 var clusterdevfileregistrieslistlog = logf.Log.WithName("clusterdevfileregistrieslist-resource")
 
-func (r *ClusterDevfileRegistriesList) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	kubeClient = mgr.GetClient()
-	return ctrl.NewWebhookManagedBy(mgr).
-		For(r).
-		Complete()
-}
+// The validating webhook of the ClusterDevfileRegistriesList, which needs to read other resources, is implemented and
+// registered with this defaulting webhook in the pkg/webhooks package.
 
 //+kubebuilder:webhook:path=/mutate-registry-devfile-io-v1alpha1-clusterdevfileregistrieslist,mutating=true,failurePolicy=fail,sideEffects=None,groups=registry.devfile.io,resources=clusterdevfileregistrieslists,verbs=create;update,versions=v1alpha1,name=mclusterdevfileregistrieslist.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Defaulter = &ClusterDevfileRegistriesList{}
 
-const multiCRError = "a ClusterDevfileRegistriesList instance already exists, only one instance can exist in a cluster"
-
 // Default implements webhook.Defaulter so a webhook will be registered for the type
 func (r *ClusterDevfileRegistriesList) Default() {
 	clusterdevfileregistrieslistlog.Info("default", "name", r.Name)
 }
-
-//+kubebuilder:webhook:path=/validate-registry-devfile-io-v1alpha1-clusterdevfileregistrieslist,mutating=false,failurePolicy=fail,sideEffects=None,groups=registry.devfile.io,resources=clusterdevfileregistrieslists,verbs=create;update,versions=v1alpha1,name=vclusterdevfileregistrieslist.kb.io,admissionReviewVersions=v1
-
-var _ webhook.Validator = &ClusterDevfileRegistriesList{}
-
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *ClusterDevfileRegistriesList) ValidateCreate() (admission.Warnings, error) {
-	clusterdevfileregistrieslistlog.Info("validate create", "name", r.Name)
-	//limit CR creation to one per cluster
-	clusterDevfileRegistriesList := &ClusterDevfileRegistriesListList{}
-	listOpts := []client.ListOption{
-		client.InNamespace(corev1.NamespaceAll),
-	}
-
-	if err := kubeClient.List(context.TODO(), clusterDevfileRegistriesList, listOpts...); err != nil {
-		return nil, fmt.Errorf("error listing clusterDevfileRegistriesList custom resources: %v", err)
-	}
-
-	if len(clusterDevfileRegistriesList.Items) == 1 {
-		return nil, fmt.Errorf(multiCRError)
-	}
-
-	if err := validateURLs(r.Spec.DevfileRegistries); err != nil {
-		return nil, err
-	}
-
-	return nil, IsNamespaceValid(r.Namespace)
-}
-
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *ClusterDevfileRegistriesList) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
-	clusterdevfileregistrieslistlog.Info("validate update", "name", r.Name)
-	//re-validate the entire list to ensure existing URLs have not gone stale
-	return nil, validateURLs(r.Spec.DevfileRegistries)
-}
-
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *ClusterDevfileRegistriesList) ValidateDelete() (admission.Warnings, error) {
-	clusterdevfileregistrieslistlog.Info("validate delete", "name", r.Name)
-	return nil, nil
-}
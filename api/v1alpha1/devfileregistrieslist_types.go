@@ -56,6 +56,11 @@ type DevfileRegistriesListStatus struct {
 	// Conditions shows the state of this CR's devfile registry list.  If registries are no longer reachable, they will be listed here
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// UnreachableRegistries are the URLs of the devfile registries of the list that could not be reached by the last validation
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	UnreachableRegistries []string `json:"unreachableRegistries,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,370 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/devfile/registry-operator/api/v1beta1"
+)
+
+// DeprecatedImagesAnnotation stores the deprecated v1alpha1 image fields on a v1beta1 DevfileRegistry,
+// so converting it back to v1alpha1 gives the original object.
+const DeprecatedImagesAnnotation = "registry.devfile.io/v1alpha1-deprecated-images"
+
+// deprecatedImage is the value of one deprecated image field
+type deprecatedImage struct {
+	Image string `json:"image"`
+	// Inherited is true when the matching container block had no image and took this one during conversion
+	Inherited bool `json:"inherited,omitempty"`
+}
+
+// deprecatedImages is the content of DeprecatedImagesAnnotation
+type deprecatedImages struct {
+	DevfileIndexImage   *deprecatedImage `json:"devfileIndexImage,omitempty"`
+	OciRegistryImage    *deprecatedImage `json:"ociRegistryImage,omitempty"`
+	RegistryViewerImage *deprecatedImage `json:"registryViewerImage,omitempty"`
+}
+
+var _ conversion.Convertible = &DevfileRegistry{}
+
+// ConvertTo converts this DevfileRegistry to the hub version (v1beta1). The deprecated image fields are
+// folded into the container blocks, following the same precedence as the operator.
+func (src *DevfileRegistry) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.DevfileRegistry)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", dstRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = v1beta1.DevfileRegistrySpec{
+		DevfileIndex:        v1beta1.DevfileRegistrySpecContainer(in.Spec.DevfileIndex),
+		OciRegistry:         convertOCIRegistryToHub(in.Spec.OciRegistry),
+		RegistryViewer:      v1beta1.DevfileRegistrySpecContainer(in.Spec.RegistryViewer),
+		Replicas:            in.Spec.Replicas,
+		Scheduling:          v1beta1.DevfileRegistrySpecScheduling(in.Spec.Scheduling),
+		PodTemplateOverride: in.Spec.PodTemplateOverride,
+		CommonLabels:        in.Spec.CommonLabels,
+		CommonAnnotations:   in.Spec.CommonAnnotations,
+		Annotations:         v1beta1.DevfileRegistrySpecAnnotations(in.Spec.Annotations),
+		Source:              convertSourceToHub(in.Spec.Source),
+		Storage:             convertStorageToHub(in.Spec.Storage),
+		TLS:                 v1beta1.DevfileRegistrySpecTLS(in.Spec.TLS),
+		K8s:                 v1beta1.DevfileRegistrySpecK8sOnly(in.Spec.K8s),
+		Exposure:            convertExposureToHub(in.Spec.Exposure),
+		Monitoring:          v1beta1.DevfileRegistrySpecMonitoring(in.Spec.Monitoring),
+		Telemetry:           v1beta1.DevfileRegistrySpecTelemetry(in.Spec.Telemetry),
+		Headless:            in.Spec.Headless,
+		HostnameOverride:    in.Spec.HostnameOverride,
+		NameOverride:        in.Spec.NameOverride,
+		FullnameOverride:    in.Spec.FullnameOverride,
+	}
+	dst.Status = convertStatusToHub(in.Status)
+
+	images := deprecatedImages{
+		DevfileIndexImage:   foldDeprecatedImage(&dst.Spec.DevfileIndex.Image, in.Spec.DevfileIndexImage),
+		OciRegistryImage:    foldDeprecatedImage(&dst.Spec.OciRegistry.Image, in.Spec.OciRegistryImage),
+		RegistryViewerImage: foldDeprecatedImage(&dst.Spec.RegistryViewer.Image, in.Spec.RegistryViewerImage),
+	}
+	delete(dst.Annotations, DeprecatedImagesAnnotation)
+	if images != (deprecatedImages{}) {
+		data, err := json.Marshal(images)
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[DeprecatedImagesAnnotation] = string(data)
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version, restoring the deprecated
+// image fields recorded by ConvertTo.
+func (dst *DevfileRegistry) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.DevfileRegistry)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", srcRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = DevfileRegistrySpec{
+		DevfileIndex:        DevfileRegistrySpecContainer(in.Spec.DevfileIndex),
+		OciRegistry:         convertOCIRegistryFromHub(in.Spec.OciRegistry),
+		RegistryViewer:      DevfileRegistrySpecContainer(in.Spec.RegistryViewer),
+		Replicas:            in.Spec.Replicas,
+		Scheduling:          DevfileRegistrySpecScheduling(in.Spec.Scheduling),
+		PodTemplateOverride: in.Spec.PodTemplateOverride,
+		CommonLabels:        in.Spec.CommonLabels,
+		CommonAnnotations:   in.Spec.CommonAnnotations,
+		Annotations:         DevfileRegistrySpecAnnotations(in.Spec.Annotations),
+		Source:              convertSourceFromHub(in.Spec.Source),
+		Storage:             convertStorageFromHub(in.Spec.Storage),
+		TLS:                 DevfileRegistrySpecTLS(in.Spec.TLS),
+		K8s:                 DevfileRegistrySpecK8sOnly(in.Spec.K8s),
+		Exposure:            convertExposureFromHub(in.Spec.Exposure),
+		Monitoring:          DevfileRegistrySpecMonitoring(in.Spec.Monitoring),
+		Telemetry:           DevfileRegistrySpecTelemetry(in.Spec.Telemetry),
+		Headless:            in.Spec.Headless,
+		HostnameOverride:    in.Spec.HostnameOverride,
+		NameOverride:        in.Spec.NameOverride,
+		FullnameOverride:    in.Spec.FullnameOverride,
+	}
+	dst.Status = convertStatusFromHub(in.Status)
+
+	data, found := dst.Annotations[DeprecatedImagesAnnotation]
+	if !found {
+		return nil
+	}
+	delete(dst.Annotations, DeprecatedImagesAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	images := deprecatedImages{}
+	if err := json.Unmarshal([]byte(data), &images); err != nil {
+		return fmt.Errorf("failed to parse annotation %s: %w", DeprecatedImagesAnnotation, err)
+	}
+	dst.Spec.DevfileIndexImage = restoreDeprecatedImage(&dst.Spec.DevfileIndex.Image, images.DevfileIndexImage)
+	dst.Spec.OciRegistryImage = restoreDeprecatedImage(&dst.Spec.OciRegistry.Image, images.OciRegistryImage)
+	dst.Spec.RegistryViewerImage = restoreDeprecatedImage(&dst.Spec.RegistryViewer.Image, images.RegistryViewerImage)
+
+	return nil
+}
+
+// foldDeprecatedImage sets the container block image from the deprecated field if the block has none,
+// and returns what needs to be recorded to undo it. Returns nil if the deprecated field is unset.
+func foldDeprecatedImage(blockImage *string, image string) *deprecatedImage {
+	if image == "" {
+		return nil
+	}
+	recorded := &deprecatedImage{Image: image}
+	if *blockImage == "" {
+		*blockImage = image
+		recorded.Inherited = true
+	}
+	return recorded
+}
+
+// restoreDeprecatedImage returns the value of the deprecated field recorded by foldDeprecatedImage,
+// and clears the container block image if it was only inherited from it and has not been changed since.
+func restoreDeprecatedImage(blockImage *string, recorded *deprecatedImage) string {
+	if recorded == nil {
+		return ""
+	}
+	if recorded.Inherited && *blockImage == recorded.Image {
+		*blockImage = ""
+	}
+	return recorded.Image
+}
+
+// convertOCIRegistryToHub converts the OCI registry block to the hub version (v1beta1)
+func convertOCIRegistryToHub(in DevfileRegistrySpecOCIRegistry) v1beta1.DevfileRegistrySpecOCIRegistry {
+	out := v1beta1.DevfileRegistrySpecOCIRegistry{
+		DevfileRegistrySpecContainer: v1beta1.DevfileRegistrySpecContainer(in.DevfileRegistrySpecContainer),
+		Config:                       in.Config,
+	}
+	if in.Storage.S3 != nil {
+		s3 := v1beta1.DevfileRegistrySpecS3Storage(*in.Storage.S3)
+		out.Storage.S3 = &s3
+	}
+	if in.Auth != nil {
+		auth := v1beta1.DevfileRegistrySpecOCIAuth(*in.Auth)
+		out.Auth = &auth
+	}
+	if in.GarbageCollection != nil {
+		garbageCollection := v1beta1.DevfileRegistrySpecGarbageCollection(*in.GarbageCollection)
+		out.GarbageCollection = &garbageCollection
+	}
+	return out
+}
+
+// convertOCIRegistryFromHub converts the OCI registry block from the hub version (v1beta1)
+func convertOCIRegistryFromHub(in v1beta1.DevfileRegistrySpecOCIRegistry) DevfileRegistrySpecOCIRegistry {
+	out := DevfileRegistrySpecOCIRegistry{
+		DevfileRegistrySpecContainer: DevfileRegistrySpecContainer(in.DevfileRegistrySpecContainer),
+		Config:                       in.Config,
+	}
+	if in.Storage.S3 != nil {
+		s3 := DevfileRegistrySpecS3Storage(*in.Storage.S3)
+		out.Storage.S3 = &s3
+	}
+	if in.Auth != nil {
+		auth := DevfileRegistrySpecOCIAuth(*in.Auth)
+		out.Auth = &auth
+	}
+	if in.GarbageCollection != nil {
+		garbageCollection := DevfileRegistrySpecGarbageCollection(*in.GarbageCollection)
+		out.GarbageCollection = &garbageCollection
+	}
+	return out
+}
+
+// convertStorageToHub converts the storage block to the hub version (v1beta1)
+func convertStorageToHub(in DevfileRegistrySpecStorage) v1beta1.DevfileRegistrySpecStorage {
+	out := v1beta1.DevfileRegistrySpecStorage{
+		Enabled:            in.Enabled,
+		RegistryVolumeSize: in.RegistryVolumeSize,
+		StorageClassName:   in.StorageClassName,
+		AccessModes:        in.AccessModes,
+	}
+	if in.Backup != nil {
+		backup := v1beta1.DevfileRegistrySpecStorageBackup(*in.Backup)
+		out.Backup = &backup
+	}
+	if in.RestoreFrom != nil {
+		restoreFrom := v1beta1.DevfileRegistrySpecStorageRestore(*in.RestoreFrom)
+		out.RestoreFrom = &restoreFrom
+	}
+	return out
+}
+
+// convertStorageFromHub converts the storage block from the hub version (v1beta1)
+func convertStorageFromHub(in v1beta1.DevfileRegistrySpecStorage) DevfileRegistrySpecStorage {
+	out := DevfileRegistrySpecStorage{
+		Enabled:            in.Enabled,
+		RegistryVolumeSize: in.RegistryVolumeSize,
+		StorageClassName:   in.StorageClassName,
+		AccessModes:        in.AccessModes,
+	}
+	if in.Backup != nil {
+		backup := DevfileRegistrySpecStorageBackup(*in.Backup)
+		out.Backup = &backup
+	}
+	if in.RestoreFrom != nil {
+		restoreFrom := DevfileRegistrySpecStorageRestore(*in.RestoreFrom)
+		out.RestoreFrom = &restoreFrom
+	}
+	return out
+}
+
+// convertSourceToHub converts the source block to the hub version (v1beta1)
+func convertSourceToHub(in DevfileRegistrySpecSource) v1beta1.DevfileRegistrySpecSource {
+	out := v1beta1.DevfileRegistrySpecSource{}
+	if in.Git != nil {
+		git := v1beta1.DevfileRegistrySpecGitSource(*in.Git)
+		out.Git = &git
+	}
+	if in.Mirror != nil {
+		mirror := v1beta1.DevfileRegistrySpecMirrorSource(*in.Mirror)
+		out.Mirror = &mirror
+	}
+	return out
+}
+
+// convertSourceFromHub converts the source block from the hub version (v1beta1)
+func convertSourceFromHub(in v1beta1.DevfileRegistrySpecSource) DevfileRegistrySpecSource {
+	out := DevfileRegistrySpecSource{}
+	if in.Git != nil {
+		git := DevfileRegistrySpecGitSource(*in.Git)
+		out.Git = &git
+	}
+	if in.Mirror != nil {
+		mirror := DevfileRegistrySpecMirrorSource(*in.Mirror)
+		out.Mirror = &mirror
+	}
+	return out
+}
+
+// convertExposureToHub converts the exposure block to the hub version (v1beta1)
+func convertExposureToHub(in DevfileRegistrySpecExposure) v1beta1.DevfileRegistrySpecExposure {
+	out := v1beta1.DevfileRegistrySpecExposure{
+		Type:        in.Type,
+		OCIRegistry: v1beta1.DevfileRegistrySpecOCIRegistryExposure(in.OCIRegistry),
+	}
+	if in.Gateway != nil {
+		gateway := v1beta1.DevfileRegistrySpecGateway(*in.Gateway)
+		out.Gateway = &gateway
+	}
+	return out
+}
+
+// convertExposureFromHub converts the exposure block from the hub version (v1beta1)
+func convertExposureFromHub(in v1beta1.DevfileRegistrySpecExposure) DevfileRegistrySpecExposure {
+	out := DevfileRegistrySpecExposure{
+		Type:        in.Type,
+		OCIRegistry: DevfileRegistrySpecOCIRegistryExposure(in.OCIRegistry),
+	}
+	if in.Gateway != nil {
+		gateway := DevfileRegistrySpecGateway(*in.Gateway)
+		out.Gateway = &gateway
+	}
+	return out
+}
+
+// convertStatusToHub converts the status to the hub version (v1beta1)
+func convertStatusToHub(in DevfileRegistryStatus) v1beta1.DevfileRegistryStatus {
+	out := v1beta1.DevfileRegistryStatus{
+		URL:                         in.URL,
+		OCIRegistryURL:              in.OCIRegistryURL,
+		RegistryViewerURL:           in.RegistryViewerURL,
+		Replicas:                    in.Replicas,
+		ReadyReplicas:               in.ReadyReplicas,
+		OCIRegistryAuthSecret:       in.OCIRegistryAuthSecret,
+		LastBackupTime:              in.LastBackupTime,
+		LastBackupSnapshot:          in.LastBackupSnapshot,
+		LastGarbageCollectionTime:   in.LastGarbageCollectionTime,
+		LastGarbageCollectionResult: in.LastGarbageCollectionResult,
+		SourceCommit:                in.SourceCommit,
+		LastSourceCheckTime:         in.LastSourceCheckTime,
+		LastMirrorSyncTime:          in.LastMirrorSyncTime,
+		MirroredStacks:              in.MirroredStacks,
+		LabelSelector:               in.LabelSelector,
+		ObservedGeneration:          in.ObservedGeneration,
+		ExposureType:                in.ExposureType,
+		Conditions:                  in.Conditions,
+	}
+	for _, component := range in.Components {
+		out.Components = append(out.Components, v1beta1.DevfileRegistryComponentStatus(component))
+	}
+	return out
+}
+
+// convertStatusFromHub converts the status from the hub version (v1beta1)
+func convertStatusFromHub(in v1beta1.DevfileRegistryStatus) DevfileRegistryStatus {
+	out := DevfileRegistryStatus{
+		URL:                         in.URL,
+		OCIRegistryURL:              in.OCIRegistryURL,
+		RegistryViewerURL:           in.RegistryViewerURL,
+		Replicas:                    in.Replicas,
+		ReadyReplicas:               in.ReadyReplicas,
+		OCIRegistryAuthSecret:       in.OCIRegistryAuthSecret,
+		LastBackupTime:              in.LastBackupTime,
+		LastBackupSnapshot:          in.LastBackupSnapshot,
+		LastGarbageCollectionTime:   in.LastGarbageCollectionTime,
+		LastGarbageCollectionResult: in.LastGarbageCollectionResult,
+		SourceCommit:                in.SourceCommit,
+		LastSourceCheckTime:         in.LastSourceCheckTime,
+		LastMirrorSyncTime:          in.LastMirrorSyncTime,
+		MirroredStacks:              in.MirroredStacks,
+		LabelSelector:               in.LabelSelector,
+		ObservedGeneration:          in.ObservedGeneration,
+		ExposureType:                in.ExposureType,
+		Conditions:                  in.Conditions,
+	}
+	for _, component := range in.Components {
+		out.Components = append(out.Components, DevfileRegistryComponentStatus(component))
+	}
+	return out
+}
@@ -0,0 +1,272 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devfile/registry-operator/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDevfileRegistryConversionRoundTrip(t *testing.T) {
+	tlsEnabled := false
+	deleteUntagged := true
+	storageEnabled := true
+	retention := int32(3)
+	headless := true
+	ociRegistryExposed := true
+
+	tests := []struct {
+		name string
+		cr   DevfileRegistry
+	}{
+		{
+			name: "Case 1: Container blocks only",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					DevfileIndex:   DevfileRegistrySpecContainer{Image: "quay.io/test/devfile-index:next", MemoryLimit: "512Mi"},
+					OciRegistry:    DevfileRegistrySpecOCIRegistry{DevfileRegistrySpecContainer: DevfileRegistrySpecContainer{ImagePullPolicy: "IfNotPresent"}},
+					RegistryViewer: DevfileRegistrySpecContainer{Image: "quay.io/test/registry-viewer:next"},
+					TLS:            DevfileRegistrySpecTLS{Enabled: &tlsEnabled},
+					K8s:            DevfileRegistrySpecK8sOnly{IngressDomain: "example.com"},
+					Headless:       &headless,
+				},
+				Status: DevfileRegistryStatus{URL: "http://devfile-registry-main.example.com"},
+			},
+		},
+		{
+			name: "Case 2: Deprecated image fields only",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main", Annotations: map[string]string{"foo": "bar"}},
+				Spec: DevfileRegistrySpec{
+					DevfileIndexImage:   "quay.io/test/devfile-index:old",
+					OciRegistryImage:    "quay.io/test/oci-registry:old",
+					RegistryViewerImage: "quay.io/test/registry-viewer:old",
+				},
+			},
+		},
+		{
+			name: "Case 3: Deprecated image fields alongside container blocks",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					DevfileIndex:      DevfileRegistrySpecContainer{Image: "quay.io/test/devfile-index:new"},
+					DevfileIndexImage: "quay.io/test/devfile-index:old",
+					OciRegistry:       DevfileRegistrySpecOCIRegistry{DevfileRegistrySpecContainer: DevfileRegistrySpecContainer{Image: "quay.io/test/oci-registry:same"}},
+					OciRegistryImage:  "quay.io/test/oci-registry:same",
+				},
+			},
+		},
+		{
+			name: "Case 4: OCI registry storage, authentication and garbage collection",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					OciRegistry: DevfileRegistrySpecOCIRegistry{
+						DevfileRegistrySpecContainer: DevfileRegistrySpecContainer{MemoryLimit: "512Mi"},
+						Storage: DevfileRegistrySpecOCIStorage{
+							S3: &DevfileRegistrySpecS3Storage{
+								Bucket:            "devfile-registry",
+								RegionEndpoint:    "http://minio.minio.svc:9000",
+								Secure:            &tlsEnabled,
+								CredentialsSecret: "minio-credentials",
+							},
+						},
+						Auth: &DevfileRegistrySpecOCIAuth{HtpasswdSecret: "my-htpasswd"},
+						GarbageCollection: &DevfileRegistrySpecGarbageCollection{
+							Schedule:       "0 3 * * *",
+							DeleteUntagged: &deleteUntagged,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Case 5: Storage backup and restore",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					Storage: DevfileRegistrySpecStorage{
+						Enabled:            &storageEnabled,
+						RegistryVolumeSize: "5Gi",
+						AccessModes:        []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+						Backup:             &DevfileRegistrySpecStorageBackup{Schedule: "0 3 * * *", Retention: &retention},
+						RestoreFrom:        &DevfileRegistrySpecStorageRestore{VolumeSnapshot: "devfileregistry-20240131-0300"},
+					},
+				},
+				Status: DevfileRegistryStatus{LastBackupSnapshot: "devfileregistry-20240131-0300"},
+			},
+		},
+		{
+			name: "Case 6: Git source",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					Source: DevfileRegistrySpecSource{
+						Git: &DevfileRegistrySpecGitSource{
+							URL:               "https://github.com/devfile/registry.git",
+							Revision:          "main",
+							Path:              "registry",
+							CredentialsSecret: "git-credentials",
+						},
+					},
+				},
+				Status: DevfileRegistryStatus{
+					SourceCommit:        "1111111111111111111111111111111111111111",
+					LastSourceCheckTime: &metav1.Time{Time: time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			name: "Case 7: Mirrored devfile registry",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					Source: DevfileRegistrySpecSource{
+						Mirror: &DevfileRegistrySpecMirrorSource{
+							URL:           "https://registry.devfile.io",
+							Interval:      &metav1.Duration{Duration: 30 * time.Minute},
+							SkipTLSVerify: true,
+						},
+					},
+				},
+				Status: DevfileRegistryStatus{
+					LastMirrorSyncTime: &metav1.Time{Time: time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)},
+					MirroredStacks:     12,
+				},
+			},
+		},
+		{
+			name: "Case 8: Gateway exposure",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					Exposure: DevfileRegistrySpecExposure{
+						Gateway: &DevfileRegistrySpecGateway{
+							Name:        "gateway",
+							Namespace:   "gateway-system",
+							SectionName: "https",
+							Hostname:    "registry.example.com",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Case 9: Exposure type",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					Exposure: DevfileRegistrySpecExposure{Type: "ClusterIP"},
+				},
+			},
+		},
+		{
+			name: "Case 10: Exposed OCI registry",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					Exposure: DevfileRegistrySpecExposure{
+						Type: "Ingress",
+						OCIRegistry: DevfileRegistrySpecOCIRegistryExposure{
+							Enabled:  &ociRegistryExposed,
+							Hostname: "oci.example.com",
+						},
+					},
+				},
+				Status: DevfileRegistryStatus{
+					URL:               "https://registry.example.com",
+					OCIRegistryURL:    "https://oci.example.com",
+					RegistryViewerURL: "https://registry.example.com/viewer",
+				},
+			},
+		},
+		{
+			name: "Case 11: Monitoring",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main"},
+				Spec: DevfileRegistrySpec{
+					Monitoring: DevfileRegistrySpecMonitoring{
+						Enabled:  &storageEnabled,
+						Interval: &metav1.Duration{Duration: time.Minute},
+						Labels:   map[string]string{"release": "prometheus"},
+					},
+				},
+			},
+		},
+		{
+			name: "Case 12: Rollout status",
+			cr: DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry", Namespace: "main", Generation: 3},
+				Status: DevfileRegistryStatus{
+					URL:                "https://registry.example.com",
+					Replicas:           2,
+					ReadyReplicas:      1,
+					ObservedGeneration: 3,
+					ExposureType:       "Ingress",
+					Components: []DevfileRegistryComponentStatus{
+						{Name: "devfile-index", Image: "quay.io/devfile/devfile-index:next", Ready: true},
+						{Name: "oci-registry", Image: "quay.io/devfile/oci-registry:next"},
+					},
+					Conditions: []metav1.Condition{
+						{Type: "Available", Status: metav1.ConditionTrue, Reason: "ReplicasAvailable"},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1beta1.DevfileRegistry{}
+			assert.NoError(t, tt.cr.DeepCopy().ConvertTo(hub))
+
+			got := &DevfileRegistry{}
+			assert.NoError(t, got.ConvertFrom(hub))
+			assert.Equal(t, tt.cr, *got)
+		})
+	}
+}
+
+func TestDevfileRegistryConvertTo(t *testing.T) {
+	cr := &DevfileRegistry{
+		Spec: DevfileRegistrySpec{
+			DevfileIndex:        DevfileRegistrySpecContainer{Image: "quay.io/test/devfile-index:new"},
+			DevfileIndexImage:   "quay.io/test/devfile-index:old",
+			RegistryViewerImage: "quay.io/test/registry-viewer:old",
+		},
+	}
+
+	hub := &v1beta1.DevfileRegistry{}
+	assert.NoError(t, cr.ConvertTo(hub))
+	assert.Equal(t, "quay.io/test/devfile-index:new", hub.Spec.DevfileIndex.Image)
+	assert.Equal(t, "", hub.Spec.OciRegistry.Image)
+	assert.Equal(t, "quay.io/test/registry-viewer:old", hub.Spec.RegistryViewer.Image)
+	assert.Contains(t, hub.Annotations, DeprecatedImagesAnnotation)
+
+	// An image changed through v1beta1 must win over the inherited deprecated value
+	hub.Spec.RegistryViewer.Image = "quay.io/test/registry-viewer:newer"
+	got := &DevfileRegistry{}
+	assert.NoError(t, got.ConvertFrom(hub))
+	assert.Equal(t, "quay.io/test/registry-viewer:newer", got.Spec.RegistryViewer.Image)
+	assert.Equal(t, "quay.io/test/registry-viewer:old", got.Spec.RegistryViewerImage)
+	assert.NotContains(t, got.Annotations, DeprecatedImagesAnnotation)
+}
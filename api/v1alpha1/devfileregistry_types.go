@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -34,7 +35,7 @@ type DevfileRegistrySpec struct {
 	// Sets the OCI registry container spec to be deployed on the Devfile Registry
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
-	OciRegistry DevfileRegistrySpecContainer `json:"ociRegistry,omitempty"`
+	OciRegistry DevfileRegistrySpecOCIRegistry `json:"ociRegistry,omitempty"`
 	// Sets the registry viewer container spec to be deployed on the Devfile Registry
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
@@ -58,12 +59,63 @@ type DevfileRegistrySpec struct {
 	// +deprecated
 	RegistryViewerImage string `json:"registryViewerImage,omitempty"`
 
+	// Sets the number of registry pods to run. Setting more than one replica deploys the registry in
+	// high-availability mode, with a PodDisruptionBudget and pod anti-affinity. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Sets the scheduling constraints of the registry pods
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Scheduling DevfileRegistrySpecScheduling `json:"scheduling,omitempty"`
+
+	// Overrides the pod template of the registry deployment. It is applied as a strategic merge patch on top of
+	// the pod template generated by the operator, and can be used to add environment variables, volumes, sidecar
+	// containers or annotations. The labels used to select the registry pods cannot be overridden.
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PodTemplateOverride *apiextensionsv1.JSON `json:"podTemplateOverride,omitempty"`
+
+	// Sets labels added to all the resources generated for the registry, including the registry pods.
+	// The labels used to select the registry pods cannot be changed and take precedence.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// Sets annotations added to all the resources generated for the registry, including the registry pods
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+
+	// Sets annotations added to specific resources generated for the registry, on top of the common annotations
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Annotations DevfileRegistrySpecAnnotations `json:"annotations,omitempty"`
+
+	// Sets where the devfile stacks served by the registry come from, instead of the devfile index image
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Source DevfileRegistrySpecSource `json:"source,omitempty"`
+
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Storage DevfileRegistrySpecStorage `json:"storage,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	TLS DevfileRegistrySpecTLS `json:"tls,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	K8s DevfileRegistrySpecK8sOnly `json:"k8s,omitempty"`
+	// Sets how the registry is exposed outside of the cluster
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Exposure DevfileRegistrySpecExposure `json:"exposure,omitempty"`
+	// Sets the monitoring of the registry metrics by Prometheus
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Monitoring DevfileRegistrySpecMonitoring `json:"monitoring,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Telemetry DevfileRegistrySpecTelemetry `json:"telemetry,omitempty"`
 	// Sets the registry server deployment to run under headless mode
@@ -94,10 +146,253 @@ type DevfileRegistrySpecContainer struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
-	// Sets the memory limit for the container
+	// Sets the memory limit for the container.
+	// Ignored if resources.limits.memory is set.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
 	MemoryLimit string `json:"memoryLimit,omitempty"`
+	// Sets the compute resource requests and limits for the container.
+	// Any request or limit left unset falls back to the operator default.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Overrides the liveness probe of the container. The probe checks the container itself unless a handler
+	// is set, unset fields keep their default value.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+	// Overrides the readiness probe of the container. The probe checks the container itself unless a handler
+	// is set, unset fields keep their default value.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+	// Overrides the startup probe of the container. The probe checks the container itself unless a handler
+	// is set, unset fields keep their default value.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+}
+
+// DevfileRegistrySpecOCIRegistry defines the desired state of the OCI registry container for the DevfileRegistry
+type DevfileRegistrySpecOCIRegistry struct {
+	DevfileRegistrySpecContainer `json:",inline"`
+
+	// Sets the storage backend of the OCI registry. Defaults to the filesystem of the registry volume.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Storage DevfileRegistrySpecOCIStorage `json:"storage,omitempty"`
+
+	// Selects a key of a config map in the registry namespace holding an OCI registry configuration. It is
+	// deep-merged over the configuration generated by the operator, a storage driver set in it replaces the
+	// generated one. The registry pods are rolled out when the merged configuration changes.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Config *corev1.ConfigMapKeySelector `json:"config,omitempty"`
+
+	// Enables htpasswd authentication on the OCI registry, for pulls as well as pushes
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Auth *DevfileRegistrySpecOCIAuth `json:"auth,omitempty"`
+
+	// Schedules the garbage collection of the OCI registry storage, removing the blobs no longer referenced by
+	// any manifest. Requires persistent storage or an S3-compatible bucket.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	GarbageCollection *DevfileRegistrySpecGarbageCollection `json:"garbageCollection,omitempty"`
+}
+
+// DevfileRegistrySpecOCIAuth defines the authentication of the OCI registry
+type DevfileRegistrySpecOCIAuth struct {
+	// Name of a secret in the registry namespace holding an htpasswd file with bcrypt passwords in its htpasswd key.
+	// The operator generates a secret with a random credential for the devfile index server, served along with this file.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	HtpasswdSecret string `json:"htpasswdSecret,omitempty"`
+}
+
+// DevfileRegistrySpecOCIStorage defines the storage backend of the OCI registry
+type DevfileRegistrySpecOCIStorage struct {
+	// Stores the OCI registry content in an S3-compatible bucket instead of the registry volume
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	S3 *DevfileRegistrySpecS3Storage `json:"s3,omitempty"`
+}
+
+// DevfileRegistrySpecS3Storage defines an S3-compatible bucket used as the OCI registry storage
+type DevfileRegistrySpecS3Storage struct {
+	// Name of the bucket storing the OCI registry content
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Bucket string `json:"bucket"`
+
+	// Region of the bucket. Defaults to us-east-1.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Endpoint of an S3-compatible service, such as MinIO or Ceph. Leave unset to use AWS S3.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	RegionEndpoint string `json:"regionEndpoint,omitempty"`
+
+	// Prefix of the OCI registry content within the bucket. Defaults to the root of the bucket.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	RootDirectory string `json:"rootDirectory,omitempty"`
+
+	// Instructs the OCI registry to use HTTPS to reach the bucket. Enabled by default.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Secure *bool `json:"secure,omitempty"`
+
+	// Name of a secret in the registry namespace holding the credentials of the bucket in the
+	// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY keys. If unset, the OCI registry falls back to
+	// the default AWS credential chain, such as a web identity token or an instance profile.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// DevfileRegistrySpecGarbageCollection defines the garbage collection of the OCI registry storage
+type DevfileRegistrySpecGarbageCollection struct {
+	// Schedule of the garbage collection, in the cron format
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Schedule string `json:"schedule"`
+
+	// Also removes the manifests that are not tagged, along with their blobs. Disabled by default.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	DeleteUntagged *bool `json:"deleteUntagged,omitempty"`
+}
+
+// DevfileRegistrySpecScheduling defines where the pods of the DevfileRegistry are scheduled
+type DevfileRegistrySpecScheduling struct {
+	// Selects the nodes the registry pods can be scheduled on
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Sets the tolerations of the registry pods
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Sets the affinity of the registry pods.
+	// If unset and more than one replica is deployed, the pods prefer to be spread across nodes.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Sets how the registry pods are spread across topology domains
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// Sets the priority class of the registry pods
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// DevfileRegistrySpecAnnotations defines the annotations of the resources generated for the DevfileRegistry.
+// They take precedence over the common annotations.
+type DevfileRegistrySpecAnnotations struct {
+	// Sets the annotations of the registry deployment
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Deployment map[string]string `json:"deployment,omitempty"`
+
+	// Sets the annotations of the registry pods
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Pod map[string]string `json:"pod,omitempty"`
+
+	// Sets the annotations of the registry service
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Service map[string]string `json:"service,omitempty"`
+
+	// Sets the annotations of the registry config map
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ConfigMap map[string]string `json:"configMap,omitempty"`
+
+	// Sets the annotations of the registry persistent volume claim
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PersistentVolumeClaim map[string]string `json:"persistentVolumeClaim,omitempty"`
+
+	// Sets the annotations of the registry ingress, on Kubernetes
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Ingress map[string]string `json:"ingress,omitempty"`
+
+	// Sets the annotations of the registry route, on OpenShift
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Route map[string]string `json:"route,omitempty"`
+}
+
+// DevfileRegistrySpecSource defines where the devfile stacks served by the DevfileRegistry come from,
+// only one of its fields can be set
+// +kubebuilder:validation:MaxProperties=1
+type DevfileRegistrySpecSource struct {
+	// Builds the registry index from a Git repository of stacks, laid out like the devfile/registry repository.
+	// The index is rebuilt when a new commit is pushed to the tracked revision.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Git *DevfileRegistrySpecGitSource `json:"git,omitempty"`
+
+	// Mirrors the stacks of an upstream devfile registry, such as https://registry.devfile.io, into the OCI
+	// registry of the DevfileRegistry, for clusters that cannot reach it
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Mirror *DevfileRegistrySpecMirrorSource `json:"mirror,omitempty"`
+}
+
+// DevfileRegistrySpecGitSource defines a Git repository of devfile stacks
+type DevfileRegistrySpecGitSource struct {
+	// URL of the Git repository, cloned over HTTP(S)
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	URL string `json:"url"`
+
+	// Branch, tag or commit of the Git repository to build the index from. Defaults to the default branch of the
+	// repository.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Path of the registry directory in the Git repository, holding the stacks directory and the optional
+	// extraDevfileEntries.yaml file. Defaults to the root of the repository.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Name of a secret in the namespace of the DevfileRegistry holding the credentials of the Git repository,
+	// in its username and password keys. The password can be an access token.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// DevfileRegistrySpecMirrorSource defines an upstream devfile registry mirrored by the DevfileRegistry
+type DevfileRegistrySpecMirrorSource struct {
+	// URL of the upstream devfile registry
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	URL string `json:"url"`
+
+	// Interval at which the stacks of the upstream devfile registry are synchronized. Defaults to 1h.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Skips the verification of the TLS certificate of the upstream devfile registry
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	SkipTLSVerify bool `json:"skipTLSVerify,omitempty"`
 }
 
 // DevfileRegistrySpecStorage defines the desired state of the storage for the DevfileRegistry
@@ -110,9 +405,74 @@ type DevfileRegistrySpecStorage struct {
 
 	// Configures the size of the devfile registry's persistent volume, if enabled.
 	// Defaults to 1Gi.
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
 	RegistryVolumeSize string `json:"registryVolumeSize,omitempty"`
+
+	// Sets the storage class of the devfile registry's persistent volume, if enabled.
+	// Defaults to the default storage class of the cluster. Only applied when the volume is created.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Sets the access modes of the devfile registry's persistent volume, if enabled.
+	// Defaults to ReadWriteOnce. ReadWriteMany allows running more than one replica with persistent storage.
+	// Only applied when the volume is created.
+	// +kubebuilder:validation:items:Enum=ReadWriteOnce;ReadWriteMany;ReadWriteOncePod
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// Schedules snapshots of the devfile registry's persistent volume, if enabled.
+	// Requires a CSI driver supporting volume snapshots.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Backup *DevfileRegistrySpecStorageBackup `json:"backup,omitempty"`
+
+	// Provisions the devfile registry's persistent volume, if enabled, from a volume snapshot or as a clone of
+	// another persistent volume claim. Changing it replaces the content of the existing volume.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	RestoreFrom *DevfileRegistrySpecStorageRestore `json:"restoreFrom,omitempty"`
+}
+
+// DevfileRegistrySpecStorageBackup defines the scheduled snapshots of the devfile registry's persistent volume
+type DevfileRegistrySpecStorageBackup struct {
+	// Schedule of the snapshots, in the cron format
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Schedule string `json:"schedule"`
+
+	// Number of snapshots to keep, the oldest ones are deleted first.
+	// Defaults to 7.
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Retention *int32 `json:"retention,omitempty"`
+
+	// Sets the volume snapshot class of the snapshots.
+	// Defaults to the default volume snapshot class of the CSI driver.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// DevfileRegistrySpecStorageRestore defines the source the devfile registry's persistent volume is provisioned from,
+// only one of its fields can be set
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type DevfileRegistrySpecStorageRestore struct {
+	// Name of a volume snapshot in the namespace of the DevfileRegistry
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	VolumeSnapshot string `json:"volumeSnapshot,omitempty"`
+
+	// Name of a persistent volume claim in the namespace of the DevfileRegistry, such as the one of another
+	// DevfileRegistry, to clone. It must have the same storage class.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
 }
 
 // DevfileRegistrySpecTLS defines the desired state for TLS in the DevfileRegistry
@@ -131,14 +491,94 @@ type DevfileRegistrySpecTLS struct {
 
 // DevfileRegistrySpecK8sOnly defines the desired state of the kubernetes-only fields of the DevfileRegistry
 type DevfileRegistrySpecK8sOnly struct {
-	// Ingress domain for a Kubernetes cluster. This MUST be explicitly specified on Kubernetes. There are no defaults
+	// Ingress domain for a Kubernetes cluster. Defaults to the ingress domain of the RegistryOperatorConfig, or the apps
+	// domain of the cluster on OpenShift. On Kubernetes, the registry is not deployed until an ingress domain is known.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	IngressDomain string `json:"ingressDomain,omitempty"`
-	// Ingress class for a Kubernetes cluster. Defaults to nginx.
+	// Ingress class for a Kubernetes cluster. Defaults to the ingress class of the RegistryOperatorConfig, the default
+	// IngressClass of the cluster, or nginx if there is none.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	IngressClass string `json:"ingressClass,omitempty"`
 }
 
+// DevfileRegistrySpecExposure defines how the DevfileRegistry is exposed outside of the cluster
+// +kubebuilder:validation:XValidation:rule="!has(self.type) || self.type != 'Gateway' || has(self.gateway)",message="a gateway must be set for the Gateway exposure type"
+// +kubebuilder:validation:XValidation:rule="!has(self.gateway) || !has(self.type) || self.type == 'Gateway'",message="a gateway can only be set for the Gateway exposure type"
+// +kubebuilder:validation:XValidation:rule="!has(self.ociRegistry) || !has(self.ociRegistry.enabled) || !self.ociRegistry.enabled || (!has(self.gateway) && (!has(self.type) || self.type in ['Route', 'Ingress']))",message="the OCI registry can only be exposed with the Route or Ingress exposure types"
+type DevfileRegistrySpecExposure struct {
+	// How the registry is exposed: with a Route, an Ingress, an HTTPRoute attached to a Gateway, a LoadBalancer or
+	// NodePort service, or only inside the cluster with a ClusterIP service. Defaults to Gateway if a gateway is set,
+	// Route on OpenShift without an ingress domain, and Ingress otherwise.
+	// +kubebuilder:validation:Enum=Route;Ingress;Gateway;LoadBalancer;NodePort;ClusterIP
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Exposes the registry with a Gateway API HTTPRoute attached to the given Gateway, instead of an Ingress or
+	// a Route. TLS is terminated by the Gateway listener, set tls.enabled to false for an HTTP listener.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Gateway *DevfileRegistrySpecGateway `json:"gateway,omitempty"`
+	// Exposes the API of the OCI registry outside of the cluster, to push and pull stacks with OCI clients
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	OCIRegistry DevfileRegistrySpecOCIRegistryExposure `json:"ociRegistry,omitempty"`
+}
+
+// DevfileRegistrySpecOCIRegistryExposure defines how the OCI registry of the DevfileRegistry is exposed
+type DevfileRegistrySpecOCIRegistryExposure struct {
+	// Exposes the /v2 API of the OCI registry on its own host, with a Route or an Ingress like the devfile index.
+	// Requires ociRegistry.auth to be set. Defaults to false, the OCI registry being only reachable inside of the
+	// cluster.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Hostname of the OCI registry. Defaults to the hostname of the registry with an -oci suffix in its ingress domain,
+	// or to the one generated by OpenShift for the Route without an ingress domain.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// DevfileRegistrySpecGateway defines the Gateway the HTTPRoute of the DevfileRegistry is attached to
+type DevfileRegistrySpecGateway struct {
+	// Name of the Gateway
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. Defaults to the namespace of the registry
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the Gateway listener the HTTPRoute is attached to. Defaults to every listener allowing the route
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+
+	// Hostname of the HTTPRoute. Defaults to the hostname of the registry in its ingress domain
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// DevfileRegistrySpecMonitoring defines how the metrics of the DevfileRegistry are scraped by Prometheus
+type DevfileRegistrySpecMonitoring struct {
+	// Generates a ServiceMonitor scraping the metrics of the devfile index and the OCI registry, when the Prometheus
+	// Operator is installed on the cluster. Defaults to false.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Interval at which the metrics are scraped. Defaults to the scrape interval of Prometheus.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Labels added to the ServiceMonitor, to match the serviceMonitorSelector of the Prometheus instance
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
 // Telemetry defines the desired state for telemetry in the DevfileRegistry
 type DevfileRegistrySpecTelemetry struct {
 	// The registry name (can be any string) that is used as identifier for devfile telemetry.
@@ -165,13 +605,119 @@ type DevfileRegistryStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	URL string `json:"url"`
 
+	// OCIRegistryURL is the URL of the OCI registry, exposed outside of the cluster if enabled, or the one of the
+	// registry service otherwise.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	OCIRegistryURL string `json:"ociRegistryURL,omitempty"`
+
+	// RegistryViewerURL is the URL of the registry viewer, served by the devfile index. Unset in headless mode.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	RegistryViewerURL string `json:"registryViewerURL,omitempty"`
+
+	// Replicas is the number of registry pods currently deployed.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of registry pods ready to serve requests.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// OCIRegistryAuthSecret is the name of the secret holding the credentials of the OCI registry, if authentication is enabled.
+	// The secret generated by the operator holds the credential in its username and password keys.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	OCIRegistryAuthSecret string `json:"ociRegistryAuthSecret,omitempty"`
+
+	// LastBackupTime is the time the last snapshot of the devfile registry's persistent volume was taken.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// LastBackupSnapshot is the name of the last snapshot of the devfile registry's persistent volume.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastBackupSnapshot string `json:"lastBackupSnapshot,omitempty"`
+
+	// LastGarbageCollectionTime is the time the last garbage collection of the OCI registry storage was started.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastGarbageCollectionTime *metav1.Time `json:"lastGarbageCollectionTime,omitempty"`
+
+	// LastGarbageCollectionResult is the result of the last garbage collection of the OCI registry storage,
+	// either Running, Succeeded or Failed.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastGarbageCollectionResult string `json:"lastGarbageCollectionResult,omitempty"`
+
+	// SourceCommit is the commit of the Git source the registry index is built from.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	SourceCommit string `json:"sourceCommit,omitempty"`
+
+	// LastSourceCheckTime is the time the tracked revision of the Git source was last resolved.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastSourceCheckTime *metav1.Time `json:"lastSourceCheckTime,omitempty"`
+
+	// LastMirrorSyncTime is the time the stacks of the mirrored devfile registry were last synchronized.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastMirrorSyncTime *metav1.Time `json:"lastMirrorSyncTime,omitempty"`
+
+	// MirroredStacks is the number of stacks of the mirrored devfile registry synchronized by the last synchronization.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	MirroredStacks int32 `json:"mirroredStacks,omitempty"`
+
+	// LabelSelector is the label selector of the registry pods, used by the scale subresource.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// ObservedGeneration is the generation of the DevfileRegistry last reconciled by the operator. A change of the spec
+	// has rolled out once it matches the generation of the DevfileRegistry and the Progressing condition is false.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ExposureType is the way the registry is exposed: Route, Ingress, Gateway, LoadBalancer, NodePort or ClusterIP.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	ExposureType string `json:"exposureType,omitempty"`
+
+	// Components is the status of each component of the registry: the devfile index, the OCI registry and, unless
+	// the registry is headless, the registry viewer.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Components []DevfileRegistryComponentStatus `json:"components,omitempty"`
+
 	// Conditions shows the state devfile registries.
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 }
 
+// DevfileRegistryComponentStatus is the status of a component of the registry, deployed as a container of the registry pods
+type DevfileRegistryComponentStatus struct {
+	// Name is the name of the component: devfile-index, oci-registry or registry-viewer.
+	Name string `json:"name"`
+
+	// Image is the image the component is deployed with, resolved from the spec, the RegistryOperatorConfig and the defaults.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Ready is true if the component is ready in at least one registry pod.
+	Ready bool `json:"ready"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1,devfileregistry-deployment}}
 
 // DevfileRegistry is a custom resource allows you to create and manage your own index server and registry viewer.
@@ -179,7 +725,10 @@ type DevfileRegistryStatus struct {
 // not using the default namespace.
 // +kubebuilder:resource:path=devfileregistries,shortName=devreg;dr
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.labelSelector
 // +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",description="The URL for the Devfile Registry"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas",description="The number of ready registry pods"
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type==\"Available\")].status",description="Whether the Devfile Registry is available"
 type DevfileRegistry struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
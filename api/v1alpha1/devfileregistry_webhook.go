@@ -17,11 +17,8 @@
 package v1alpha1
 
 import (
-	"k8s.io/apimachinery/pkg/runtime"
-	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
-	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // log is for logging in this package.
@@ -29,11 +26,8 @@ var (
 	devfileregistrylog = logf.Log.WithName("devfileregistry-resource")
 )
 
-func (r *DevfileRegistry) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
-		For(r).
-		Complete()
-}
+// The validating webhook of the DevfileRegistry, which needs to read other resources, is implemented and registered
+// with this defaulting webhook in the pkg/webhooks package.
 
 //+kubebuilder:webhook:path=/mutate-registry-devfile-io-v1alpha1-devfileregistry,mutating=true,failurePolicy=fail,sideEffects=None,groups=registry.devfile.io,resources=devfileregistries,verbs=create;update,versions=v1alpha1,name=mdevfileregistry.kb.io,admissionReviewVersions=v1
 
@@ -43,25 +37,3 @@ var _ webhook.Defaulter = &DevfileRegistry{}
 func (r *DevfileRegistry) Default() {
 	devfileregistrylog.Info("default", "name", r.Name)
 }
-
-//+kubebuilder:webhook:path=/validate-registry-devfile-io-v1alpha1-devfileregistry,mutating=false,failurePolicy=fail,sideEffects=None,groups=registry.devfile.io,resources=devfileregistries,verbs=create;update,versions=v1alpha1,name=vdevfileregistry.kb.io,admissionReviewVersions=v1
-
-var _ webhook.Validator = &DevfileRegistry{}
-
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *DevfileRegistry) ValidateCreate() (admission.Warnings, error) {
-	devfileregistrylog.Info("validate create", "name", r.Name)
-	return nil, IsNamespaceValid(r.Namespace)
-}
-
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *DevfileRegistry) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
-	devfileregistrylog.Info("validate update", "name", r.Name)
-	return nil, nil
-}
-
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *DevfileRegistry) ValidateDelete() (admission.Warnings, error) {
-	devfileregistrylog.Info("validate delete", "name", r.Name)
-	return nil, nil
-}
@@ -0,0 +1,118 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RegistryOperatorConfigName is the name of the RegistryOperatorConfig resource read by the operator
+const RegistryOperatorConfigName = "cluster"
+
+// RegistryOperatorConfigSpec defines the operator-wide defaults of the devfile registries. Every field falls back
+// to the built-in default of the operator when unset, and is overridden by the matching field of a DevfileRegistry.
+type RegistryOperatorConfigSpec struct {
+	// Default image and image pull policy of the devfile index container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	DevfileIndex RegistryOperatorConfigContainer `json:"devfileIndex,omitempty"`
+
+	// Default image and image pull policy of the OCI registry container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	OciRegistry RegistryOperatorConfigContainer `json:"ociRegistry,omitempty"`
+
+	// Default image and image pull policy of the registry viewer container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	RegistryViewer RegistryOperatorConfigContainer `json:"registryViewer,omitempty"`
+
+	// Default image and image pull policy of the init container building the registry index from a Git source
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IndexBuilder RegistryOperatorConfigContainer `json:"indexBuilder,omitempty"`
+
+	// Image prefixes rewritten in every image deployed by the operator, mapped to their replacements, e.g. to pull
+	// the images from a mirror. Merged over the IMAGE_PREFIX_REWRITES environment variable of the operator.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ImagePrefixRewrites map[string]string `json:"imagePrefixRewrites,omitempty"`
+
+	// Default ingress domain of the registries, so that they can be deployed on Kubernetes without setting one.
+	// On OpenShift, sets the domain of the registry routes. Defaults to the apps domain of the cluster on OpenShift.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IngressDomain string `json:"ingressDomain,omitempty"`
+
+	// Default ingress class of the registry ingresses. Defaults to the default IngressClass of the cluster, or nginx
+	// if there is none.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IngressClass string `json:"ingressClass,omitempty"`
+
+	// Whether TLS is enabled on the registry ingresses and routes by default. Defaults to true.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	TLSEnabled *bool `json:"tlsEnabled,omitempty"`
+
+	// Namespaces devfile registries and registries lists cannot be created in. Defaults to the default namespace,
+	// an empty list allows every namespace. Not omitted when empty to tell both cases apart.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ForbiddenNamespaces []string `json:"forbiddenNamespaces"`
+}
+
+// RegistryOperatorConfigContainer defines the default image of a registry container
+type RegistryOperatorConfigContainer struct {
+	// Default container image
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Default image pull policy of the container
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=regopconfig
+//+kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="the RegistryOperatorConfig must be named cluster"
+
+// RegistryOperatorConfig is a cluster-scoped custom resource where cluster admins can change the defaults of the
+// devfile registries deployed by the operator. The operator only reads the resource named cluster, and applies its
+// changes to the existing registries without a restart.
+type RegistryOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RegistryOperatorConfigSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RegistryOperatorConfigList contains a list of RegistryOperatorConfig
+type RegistryOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RegistryOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RegistryOperatorConfig{}, &RegistryOperatorConfigList{})
+}
@@ -17,21 +17,63 @@
 package v1alpha1
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
+	"strings"
 
 	indexSchema "github.com/devfile/registry-support/index/generator/schema"
 	registryLibrary "github.com/devfile/registry-support/registry-library/library"
 	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/registry-operator/pkg/util"
 )
 
 const (
-	dupRegName       = "duplicate registry name %s in registries list.  Ensure name is unique"
-	dupURLName       = "duplicate registry URL %s in registries list.  Ensure URL is unique"
-	InvalidRegistry  = "devfile %s Registry is either invalid or unavailable, unable to add to the DevfileRegistryService list. Ensure you provide a valid Devfile Registry URL"
-	InvalidNamespace = "the namespace 'default' is forbidden for the devfile registry deployment. Retry the deployment using a non-default namespace"
+	dupRegName          = "duplicate registry name %s in registries list.  Ensure name is unique"
+	dupURLName          = "duplicate registry URL %s in registries list.  Ensure URL is unique"
+	InvalidRegistry     = "devfile %s Registry is either invalid or unavailable, unable to add to the DevfileRegistryService list. Ensure you provide a valid Devfile Registry URL"
+	InvalidNamespace    = "the namespace 'default' is forbidden for the devfile registry deployment. Retry the deployment using a non-default namespace"
+	InvalidNamespaceFmt = "the namespace '%s' is forbidden for the devfile registry deployment. Retry the deployment using another namespace"
+	ReplicasLimited     = "%d replicas requested but persistent storage cannot be shared between nodes, only one replica will be deployed"
+	InvalidOverride     = "invalid podTemplateOverride, it must be a strategic merge patch of a pod template: %v"
+	InvalidConfig       = "invalid OCI registry config: %v"
+	MissingConfig       = "OCI registry config map %s or its key %s not found, the registry will not be deployed until it is created"
+	InvalidSchedule     = "invalid storage backup schedule: %v"
+	UnusedStorage       = "%s is set but persistent storage is not enabled, it is ignored"
+	GCWithoutStorage    = "garbage collection is scheduled but the OCI registry storage does not outlive the registry pods, enable persistent storage or an S3 bucket for it to run"
+	InvalidGitSource    = "invalid Git source URL %q, only http and https repositories are supported"
+	InvalidMirror       = "invalid mirrored devfile registry URL %q, only http and https registries are supported"
+	InvalidSize         = "invalid storage registryVolumeSize %q: %v"
+	UnauthenticatedOCI  = "exposure.ociRegistry.enabled requires ociRegistry.auth to be set, the OCI registry accepts pushes"
+	UnsharedMirror      = "%d replicas requested but the OCI registry storage is not shared between them, the mirrored stacks are only pushed to one of them, enable persistent storage or an S3 bucket for it"
 )
 
-func validateURLs(devfileRegistries []DevfileRegistryService) (errors error) {
+// DefaultForbiddenNamespaces are the namespaces devfile registries cannot be deployed in when the
+// RegistryOperatorConfig does not set any
+var DefaultForbiddenNamespaces = []string{"default"}
+
+// Settings of the OCI registry configuration the operator relies on, and which cannot be changed
+var registryConfigFixedSettings = map[string]string{
+	"version":         "0.1",
+	"http.addr":       ":5000",
+	"http.debug.addr": ":5001",
+}
+
+// ValidateURLs validates the devfile registries of a registry list: their names and URLs must be unique and the
+// registries reachable.
+func ValidateURLs(devfileRegistries []DevfileRegistryService) (errors error) {
 	processedName := make(map[string]bool)
 	processedURL := make(map[string]bool)
 	//validate URLs
@@ -90,11 +132,230 @@ func IsRegistryValid(skipTLSVerify bool, url string) error {
 }
 
 // IsNamespaceValid determines if given namespace for deployment
-// is valid.
-func IsNamespaceValid(namespace string) error {
-	if namespace == "default" {
-		return fmt.Errorf("%s", InvalidNamespace)
+// is valid, that is not forbidden by the RegistryOperatorConfig read with reader.
+func IsNamespaceValid(ctx context.Context, reader client.Reader, namespace string) error {
+	for _, forbidden := range getForbiddenNamespaces(ctx, reader) {
+		if namespace != forbidden {
+			continue
+		}
+		if namespace == "default" {
+			return fmt.Errorf("%s", InvalidNamespace)
+		}
+		return fmt.Errorf(InvalidNamespaceFmt, namespace)
 	}
 
 	return nil
 }
+
+// getForbiddenNamespaces returns the forbidden namespaces of the RegistryOperatorConfig, or the default ones if
+// it does not exist or cannot be read
+func getForbiddenNamespaces(ctx context.Context, reader client.Reader) []string {
+	if reader == nil {
+		return DefaultForbiddenNamespaces
+	}
+	operatorConfig := &RegistryOperatorConfig{}
+	err := reader.Get(ctx, types.NamespacedName{Name: RegistryOperatorConfigName}, operatorConfig)
+	if err != nil || operatorConfig.Spec.ForbiddenNamespaces == nil {
+		return DefaultForbiddenNamespaces
+	}
+	return operatorConfig.Spec.ForbiddenNamespaces
+}
+
+// ReplicasWarnings returns a warning if more than one replica is requested for a
+// devfile registry whose persistent storage cannot be shared between nodes, that is
+// without the ReadWriteMany access mode.
+func ReplicasWarnings(spec DevfileRegistrySpec) admission.Warnings {
+	if spec.Replicas == nil || *spec.Replicas <= 1 {
+		return nil
+	}
+	if spec.Storage.Enabled == nil || !*spec.Storage.Enabled {
+		return nil
+	}
+	for _, accessMode := range spec.Storage.AccessModes {
+		if accessMode == corev1.ReadWriteMany {
+			return nil
+		}
+	}
+	return admission.Warnings{fmt.Sprintf(ReplicasLimited, *spec.Replicas)}
+}
+
+// IsBackupScheduleValid determines if the schedule of the storage backups, if any,
+// is a valid cron schedule.
+func IsBackupScheduleValid(spec DevfileRegistrySpec) error {
+	if spec.Storage.Backup == nil {
+		return nil
+	}
+	if _, err := util.ParseSchedule(spec.Storage.Backup.Schedule); err != nil {
+		return fmt.Errorf(InvalidSchedule, err)
+	}
+	return nil
+}
+
+// IsVolumeSizeValid determines if the size of the persistent volume, if set, is a
+// valid quantity.
+func IsVolumeSizeValid(spec DevfileRegistrySpec) error {
+	if spec.Storage.RegistryVolumeSize == "" {
+		return nil
+	}
+	if _, err := resource.ParseQuantity(spec.Storage.RegistryVolumeSize); err != nil {
+		return fmt.Errorf(InvalidSize, spec.Storage.RegistryVolumeSize, err)
+	}
+	return nil
+}
+
+// IsGitSourceValid determines if the Git source of the registry index, if any, is a
+// repository the operator can clone, that is one served over HTTP(S).
+func IsGitSourceValid(spec DevfileRegistrySpec) error {
+	if spec.Source.Git == nil {
+		return nil
+	}
+	repoURL, err := url.Parse(spec.Source.Git.URL)
+	if err != nil || (repoURL.Scheme != "http" && repoURL.Scheme != "https") || repoURL.Host == "" {
+		return fmt.Errorf(InvalidGitSource, spec.Source.Git.URL)
+	}
+	return nil
+}
+
+// IsMirrorSourceValid determines if the mirrored devfile registry, if any, is a
+// devfile registry served over HTTP(S)
+func IsMirrorSourceValid(spec DevfileRegistrySpec) error {
+	if spec.Source.Mirror == nil {
+		return nil
+	}
+	registryURL, err := url.Parse(spec.Source.Mirror.URL)
+	if err != nil || (registryURL.Scheme != "http" && registryURL.Scheme != "https") || registryURL.Host == "" {
+		return fmt.Errorf(InvalidMirror, spec.Source.Mirror.URL)
+	}
+	return nil
+}
+
+// IsOCIRegistryExposureValid determines if the OCI registry can be exposed outside of the
+// cluster, which requires its authentication to be enabled.
+func IsOCIRegistryExposureValid(spec DevfileRegistrySpec) error {
+	enabled := spec.Exposure.OCIRegistry.Enabled
+	if enabled != nil && *enabled && spec.OciRegistry.Auth == nil {
+		return fmt.Errorf(UnauthenticatedOCI)
+	}
+	return nil
+}
+
+// StorageWarnings returns a warning for each setting of the persistent storage that is
+// ignored because persistent storage is not enabled.
+func StorageWarnings(spec DevfileRegistrySpec) admission.Warnings {
+	if spec.Storage.Enabled != nil && *spec.Storage.Enabled {
+		return nil
+	}
+	var warnings admission.Warnings
+	if spec.Storage.Backup != nil {
+		warnings = append(warnings, fmt.Sprintf(UnusedStorage, "storage.backup"))
+	}
+	if spec.Storage.RestoreFrom != nil {
+		warnings = append(warnings, fmt.Sprintf(UnusedStorage, "storage.restoreFrom"))
+	}
+	return warnings
+}
+
+// GarbageCollectionWarnings returns a warning if the garbage collection of the OCI
+// registry storage is scheduled without persistent storage or an S3 bucket, as the
+// garbage collection jobs cannot reach the empty volume of the registry pods.
+func GarbageCollectionWarnings(spec DevfileRegistrySpec) admission.Warnings {
+	if spec.OciRegistry.GarbageCollection == nil || spec.OciRegistry.Storage.S3 != nil {
+		return nil
+	}
+	if spec.Storage.Enabled != nil && *spec.Storage.Enabled {
+		return nil
+	}
+	return admission.Warnings{GCWithoutStorage}
+}
+
+// MirrorWarnings returns a warning if a devfile registry is mirrored by several replicas
+// which do not share their OCI registry storage, as the mirrored stacks are pushed through
+// the registry service to a single replica.
+func MirrorWarnings(spec DevfileRegistrySpec) admission.Warnings {
+	if spec.Source.Mirror == nil || spec.Replicas == nil || *spec.Replicas <= 1 || spec.OciRegistry.Storage.S3 != nil {
+		return nil
+	}
+	// With a persistent storage which cannot be shared, a single replica is deployed
+	if spec.Storage.Enabled != nil && *spec.Storage.Enabled {
+		return nil
+	}
+	return admission.Warnings{fmt.Sprintf(UnsharedMirror, *spec.Replicas)}
+}
+
+// IsPodTemplateOverrideValid determines if the given pod template override
+// can be applied as a strategic merge patch on a pod template.
+func IsPodTemplateOverrideValid(override *apiextensionsv1.JSON) error {
+	if override == nil || len(override.Raw) == 0 {
+		return nil
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch([]byte("{}"), override.Raw, corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf(InvalidOverride, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&corev1.PodTemplateSpec{}); err != nil {
+		return fmt.Errorf(InvalidOverride, err)
+	}
+
+	return nil
+}
+
+// IsRegistryConfigValid determines if the given OCI registry configuration is a YAML document
+// that keeps the settings the operator relies on and sets at most one storage driver.
+func IsRegistryConfigValid(config string) error {
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(config), &values); err != nil {
+		return fmt.Errorf(InvalidConfig, err)
+	}
+
+	var errors error
+	for path, expected := range registryConfigFixedSettings {
+		if value, found := getRegistryConfigValue(values, strings.Split(path, ".")); found && fmt.Sprint(value) != expected {
+			errors = multierror.Append(errors, fmt.Errorf(InvalidConfig, fmt.Sprintf("%s must be %s", path, expected)))
+		}
+	}
+	if storage, ok := values["storage"].(map[string]interface{}); ok {
+		if drivers := GetRegistryConfigStorageDrivers(storage); len(drivers) > 1 {
+			errors = multierror.Append(errors, fmt.Errorf(InvalidConfig, fmt.Sprintf("only one storage driver can be set, got %s", strings.Join(drivers, ", "))))
+		}
+	}
+	if auth, ok := values["auth"].(map[string]interface{}); ok && len(auth) > 1 {
+		methods := make([]string, 0, len(auth))
+		for method := range auth {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		errors = multierror.Append(errors, fmt.Errorf(InvalidConfig, fmt.Sprintf("only one auth method can be set, got %s", strings.Join(methods, ", "))))
+	}
+	return errors
+}
+
+// GetRegistryConfigStorageDrivers returns the sorted names of the storage drivers set in
+// the storage section of an OCI registry configuration
+func GetRegistryConfigStorageDrivers(storage map[string]interface{}) []string {
+	var drivers []string
+	for key := range storage {
+		switch key {
+		case "cache", "delete", "maintenance", "redirect", "tag":
+			// Storage parameters shared by all the drivers
+		default:
+			drivers = append(drivers, key)
+		}
+	}
+	sort.Strings(drivers)
+	return drivers
+}
+
+func getRegistryConfigValue(values map[string]interface{}, path []string) (interface{}, bool) {
+	value, found := values[path[0]]
+	if !found || len(path) == 1 {
+		return value, found
+	}
+	section, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return getRegistryConfigValue(section, path[1:])
+}
@@ -17,15 +17,26 @@
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/devfile/registry-operator/pkg/test"
 	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/stretchr/testify/assert"
 )
 
+const (
+	devfileStagingRegistryName = "StagingRegistry"
+	devfileStagingRegistryURL  = "https://registry.stage.devfile.io"
+)
+
 func TestDevfileRegistriesValidateURL(t *testing.T) {
 
 	testServer := test.GetNewUnstartedTestServer()
@@ -94,7 +105,7 @@ func TestDevfileRegistriesValidateURL(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateURLs(tt.devfileRegistries)
+			err := ValidateURLs(tt.devfileRegistries)
 			if merr, ok := err.(*multierror.Error); ok && tt.wantErr != nil {
 				assert.Equal(t, len(tt.wantErr), len(merr.Errors), fmt.Sprintf("Errors do not match = %v, want %v", err, tt.wantErr))
 				for _, testErr := range tt.wantErr {
@@ -108,10 +119,14 @@ func TestDevfileRegistriesValidateURL(t *testing.T) {
 }
 
 func TestIsNamespaceValid(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = AddToScheme(scheme)
+
 	tests := []struct {
-		name      string
-		namespace string
-		wantErr   string
+		name                string
+		namespace           string
+		forbiddenNamespaces []string
+		wantErr             string
 	}{
 		{
 			name:      "Registry deployment to non-default namespace",
@@ -122,12 +137,30 @@ func TestIsNamespaceValid(t *testing.T) {
 			namespace: "default",
 			wantErr:   InvalidNamespace,
 		},
+		{
+			name:                "Registry deployment to a namespace forbidden by the operator config",
+			namespace:           "kube-system",
+			forbiddenNamespaces: []string{"default", "kube-system"},
+			wantErr:             fmt.Sprintf(InvalidNamespaceFmt, "kube-system"),
+		},
+		{
+			name:                "Registry deployment to default namespace allowed by the operator config",
+			namespace:           "default",
+			forbiddenNamespaces: []string{},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := IsNamespaceValid(tt.namespace); err != nil && tt.wantErr != "" {
-				assert.Equal(t, len(tt.wantErr), len(err.Error()), fmt.Sprintf("Errors do not match = %v, want %v", err, tt.wantErr))
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.forbiddenNamespaces != nil {
+				builder.WithObjects(&RegistryOperatorConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: RegistryOperatorConfigName},
+					Spec:       RegistryOperatorConfigSpec{ForbiddenNamespaces: tt.forbiddenNamespaces},
+				})
+			}
+			if err := IsNamespaceValid(context.Background(), builder.Build(), tt.namespace); err != nil && tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, err.Error(), fmt.Sprintf("Errors do not match = %v, want %v", err, tt.wantErr))
 			} else if err != nil && tt.wantErr == "" {
 				assert.Fail(t, "Error should be nil")
 			} else if err == nil && tt.wantErr != "" {
@@ -136,3 +169,477 @@ func TestIsNamespaceValid(t *testing.T) {
 		})
 	}
 }
+
+func TestReplicasWarnings(t *testing.T) {
+	storageEnabled := true
+	one := int32(1)
+	three := int32(3)
+
+	tests := []struct {
+		name string
+		spec DevfileRegistrySpec
+		want int
+	}{
+		{
+			name: "Replicas not set",
+			spec: DevfileRegistrySpec{},
+			want: 0,
+		},
+		{
+			name: "Single replica with storage enabled",
+			spec: DevfileRegistrySpec{
+				Replicas: &one,
+				Storage:  DevfileRegistrySpecStorage{Enabled: &storageEnabled},
+			},
+			want: 0,
+		},
+		{
+			name: "Multiple replicas without storage",
+			spec: DevfileRegistrySpec{
+				Replicas: &three,
+			},
+			want: 0,
+		},
+		{
+			name: "Multiple replicas with storage enabled",
+			spec: DevfileRegistrySpec{
+				Replicas: &three,
+				Storage:  DevfileRegistrySpecStorage{Enabled: &storageEnabled},
+			},
+			want: 1,
+		},
+		{
+			name: "Multiple replicas with shareable storage enabled",
+			spec: DevfileRegistrySpec{
+				Replicas: &three,
+				Storage: DevfileRegistrySpecStorage{
+					Enabled:     &storageEnabled,
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				},
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, ReplicasWarnings(tt.spec), tt.want)
+		})
+	}
+}
+
+func TestIsBackupScheduleValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		backup  *DevfileRegistrySpecStorageBackup
+		wantErr bool
+	}{
+		{
+			name:   "Backup not scheduled",
+			backup: nil,
+		},
+		{
+			name:   "Valid schedule",
+			backup: &DevfileRegistrySpecStorageBackup{Schedule: "0 3 * * 1-5"},
+		},
+		{
+			name:    "Invalid schedule",
+			backup:  &DevfileRegistrySpecStorageBackup{Schedule: "every day"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsBackupScheduleValid(DevfileRegistrySpec{Storage: DevfileRegistrySpecStorage{Backup: tt.backup}})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsVolumeSizeValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    string
+		wantErr bool
+	}{
+		{
+			name: "Default size",
+			size: "",
+		},
+		{
+			name: "Valid size",
+			size: "5Gi",
+		},
+		{
+			name:    "Invalid size",
+			size:    "abc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsVolumeSizeValid(DevfileRegistrySpec{Storage: DevfileRegistrySpecStorage{RegistryVolumeSize: tt.size}})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsGitSourceValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		git     *DevfileRegistrySpecGitSource
+		wantErr bool
+	}{
+		{
+			name: "No Git source",
+			git:  nil,
+		},
+		{
+			name: "HTTPS repository",
+			git:  &DevfileRegistrySpecGitSource{URL: "https://github.com/devfile/registry.git"},
+		},
+		{
+			name:    "SSH repository",
+			git:     &DevfileRegistrySpecGitSource{URL: "git@github.com:devfile/registry.git"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing host",
+			git:     &DevfileRegistrySpecGitSource{URL: "https:///devfile/registry.git"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsGitSourceValid(DevfileRegistrySpec{Source: DevfileRegistrySpecSource{Git: tt.git}})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsMirrorSourceValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		mirror  *DevfileRegistrySpecMirrorSource
+		wantErr bool
+	}{
+		{
+			name:   "No mirrored registry",
+			mirror: nil,
+		},
+		{
+			name:   "HTTPS registry",
+			mirror: &DevfileRegistrySpecMirrorSource{URL: "https://registry.devfile.io"},
+		},
+		{
+			name:    "Registry without scheme",
+			mirror:  &DevfileRegistrySpecMirrorSource{URL: "registry.devfile.io"},
+			wantErr: true,
+		},
+		{
+			name:    "OCI registry",
+			mirror:  &DevfileRegistrySpecMirrorSource{URL: "oci://registry.devfile.io"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsMirrorSourceValid(DevfileRegistrySpec{Source: DevfileRegistrySpecSource{Mirror: tt.mirror}})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsOCIRegistryExposureValid(t *testing.T) {
+	enabled := true
+	tests := []struct {
+		name    string
+		enabled *bool
+		auth    *DevfileRegistrySpecOCIAuth
+		wantErr bool
+	}{
+		{
+			name: "OCI registry not exposed",
+		},
+		{
+			name:    "OCI registry exposed with authentication",
+			enabled: &enabled,
+			auth:    &DevfileRegistrySpecOCIAuth{},
+		},
+		{
+			name:    "OCI registry exposed without authentication",
+			enabled: &enabled,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsOCIRegistryExposureValid(DevfileRegistrySpec{
+				Exposure:    DevfileRegistrySpecExposure{OCIRegistry: DevfileRegistrySpecOCIRegistryExposure{Enabled: tt.enabled}},
+				OciRegistry: DevfileRegistrySpecOCIRegistry{Auth: tt.auth},
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStorageWarnings(t *testing.T) {
+	storageEnabled := true
+	backup := &DevfileRegistrySpecStorageBackup{Schedule: "0 3 * * *"}
+	restoreFrom := &DevfileRegistrySpecStorageRestore{VolumeSnapshot: "devfile-registry-20240131-0300"}
+
+	tests := []struct {
+		name    string
+		storage DevfileRegistrySpecStorage
+		want    int
+	}{
+		{
+			name:    "Storage not set",
+			storage: DevfileRegistrySpecStorage{},
+			want:    0,
+		},
+		{
+			name:    "Backup and restore without storage",
+			storage: DevfileRegistrySpecStorage{Backup: backup, RestoreFrom: restoreFrom},
+			want:    2,
+		},
+		{
+			name:    "Backup and restore with storage enabled",
+			storage: DevfileRegistrySpecStorage{Enabled: &storageEnabled, Backup: backup, RestoreFrom: restoreFrom},
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, StorageWarnings(DevfileRegistrySpec{Storage: tt.storage}), tt.want)
+		})
+	}
+}
+
+func TestGarbageCollectionWarnings(t *testing.T) {
+	storageEnabled := true
+	garbageCollection := &DevfileRegistrySpecGarbageCollection{Schedule: "0 3 * * *"}
+
+	tests := []struct {
+		name string
+		spec DevfileRegistrySpec
+		want int
+	}{
+		{
+			name: "Garbage collection not scheduled",
+			spec: DevfileRegistrySpec{},
+			want: 0,
+		},
+		{
+			name: "Garbage collection without storage",
+			spec: DevfileRegistrySpec{
+				OciRegistry: DevfileRegistrySpecOCIRegistry{GarbageCollection: garbageCollection},
+			},
+			want: 1,
+		},
+		{
+			name: "Garbage collection with storage enabled",
+			spec: DevfileRegistrySpec{
+				OciRegistry: DevfileRegistrySpecOCIRegistry{GarbageCollection: garbageCollection},
+				Storage:     DevfileRegistrySpecStorage{Enabled: &storageEnabled},
+			},
+			want: 0,
+		},
+		{
+			name: "Garbage collection with S3 storage",
+			spec: DevfileRegistrySpec{
+				OciRegistry: DevfileRegistrySpecOCIRegistry{
+					GarbageCollection: garbageCollection,
+					Storage: DevfileRegistrySpecOCIStorage{
+						S3: &DevfileRegistrySpecS3Storage{Bucket: "devfile-registry"},
+					},
+				},
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, GarbageCollectionWarnings(tt.spec), tt.want)
+		})
+	}
+}
+
+func TestMirrorWarnings(t *testing.T) {
+	storageEnabled := true
+	replicas := int32(3)
+	source := DevfileRegistrySpecSource{Mirror: &DevfileRegistrySpecMirrorSource{URL: "https://registry.devfile.io"}}
+
+	tests := []struct {
+		name string
+		spec DevfileRegistrySpec
+		want int
+	}{
+		{
+			name: "Mirror with a single replica",
+			spec: DevfileRegistrySpec{Source: source},
+			want: 0,
+		},
+		{
+			name: "Mirror with several replicas without storage",
+			spec: DevfileRegistrySpec{Source: source, Replicas: &replicas},
+			want: 1,
+		},
+		{
+			name: "Mirror with several replicas and storage enabled",
+			spec: DevfileRegistrySpec{Source: source, Replicas: &replicas, Storage: DevfileRegistrySpecStorage{Enabled: &storageEnabled}},
+			want: 0,
+		},
+		{
+			name: "Mirror with several replicas and S3 storage",
+			spec: DevfileRegistrySpec{
+				Source:   source,
+				Replicas: &replicas,
+				OciRegistry: DevfileRegistrySpecOCIRegistry{
+					Storage: DevfileRegistrySpecOCIStorage{
+						S3: &DevfileRegistrySpecS3Storage{Bucket: "devfile-registry"},
+					},
+				},
+			},
+			want: 0,
+		},
+		{
+			name: "Several replicas without mirror",
+			spec: DevfileRegistrySpec{Replicas: &replicas},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, MirrorWarnings(tt.spec), tt.want)
+		})
+	}
+}
+
+func TestIsPodTemplateOverrideValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		override *apiextensionsv1.JSON
+		wantErr  bool
+	}{
+		{
+			name:     "Override not set",
+			override: nil,
+		},
+		{
+			name: "Override adding an environment variable and a sidecar",
+			override: &apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"annotations":{"foo":"bar"}},"spec":{"containers":[` +
+				`{"name":"devfile-registry","env":[{"name":"FOO","value":"bar"}]},{"name":"sidecar","image":"busybox"}]}}`)},
+		},
+		{
+			name:     "Override with a strategic merge patch directive",
+			override: &apiextensionsv1.JSON{Raw: []byte(`{"spec":{"containers":[{"name":"oci-registry","$patch":"delete"}]}}`)},
+		},
+		{
+			name:     "Override with an unknown field",
+			override: &apiextensionsv1.JSON{Raw: []byte(`{"spec":{"nodeSelectr":{"foo":"bar"}}}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "Override with a field of the wrong type",
+			override: &apiextensionsv1.JSON{Raw: []byte(`{"spec":{"containers":{"name":"sidecar"}}}`)},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsPodTemplateOverrideValid(tt.override)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsRegistryConfigValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{
+			name:   "Empty config",
+			config: "",
+		},
+		{
+			name:   "Config enabling deletes and changing the log level",
+			config: "log:\n  level: debug\nstorage:\n  delete:\n    enabled: true\n",
+		},
+		{
+			name:   "Config keeping the OCI registry port",
+			config: "http:\n  addr: :5000\n  secret: changeme\n",
+		},
+		{
+			name:    "Config changing the OCI registry port",
+			config:  "http:\n  addr: :8000\n",
+			wantErr: true,
+		},
+		{
+			name:    "Config changing the metrics port",
+			config:  "http:\n  debug:\n    addr: :9000\n",
+			wantErr: true,
+		},
+		{
+			name:    "Config with two storage drivers",
+			config:  "storage:\n  filesystem: {}\n  s3: {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "Config with two auth methods",
+			config:  "auth:\n  htpasswd: {}\n  token: {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "Config that is not a mapping",
+			config:  "- foo\n- bar\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsRegistryConfigValid(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -24,6 +24,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -176,6 +178,11 @@ func (in *DevfileRegistriesListStatus) DeepCopyInto(out *DevfileRegistriesListSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UnreachableRegistries != nil {
+		in, out := &in.UnreachableRegistries, &out.UnreachableRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistriesListStatus.
@@ -215,6 +222,21 @@ func (in *DevfileRegistry) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistryComponentStatus) DeepCopyInto(out *DevfileRegistryComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistryComponentStatus.
+func (in *DevfileRegistryComponentStatus) DeepCopy() *DevfileRegistryComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistryComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevfileRegistryList) DeepCopyInto(out *DevfileRegistryList) {
 	*out = *in
@@ -265,12 +287,41 @@ func (in *DevfileRegistryService) DeepCopy() *DevfileRegistryService {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevfileRegistrySpec) DeepCopyInto(out *DevfileRegistrySpec) {
 	*out = *in
-	out.DevfileIndex = in.DevfileIndex
-	out.OciRegistry = in.OciRegistry
-	out.RegistryViewer = in.RegistryViewer
+	in.DevfileIndex.DeepCopyInto(&out.DevfileIndex)
+	in.OciRegistry.DeepCopyInto(&out.OciRegistry)
+	in.RegistryViewer.DeepCopyInto(&out.RegistryViewer)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Scheduling.DeepCopyInto(&out.Scheduling)
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Annotations.DeepCopyInto(&out.Annotations)
+	in.Source.DeepCopyInto(&out.Source)
 	in.Storage.DeepCopyInto(&out.Storage)
 	in.TLS.DeepCopyInto(&out.TLS)
 	out.K8s = in.K8s
+	in.Exposure.DeepCopyInto(&out.Exposure)
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	out.Telemetry = in.Telemetry
 	if in.Headless != nil {
 		in, out := &in.Headless, &out.Headless
@@ -289,9 +340,93 @@ func (in *DevfileRegistrySpec) DeepCopy() *DevfileRegistrySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecAnnotations) DeepCopyInto(out *DevfileRegistrySpecAnnotations) {
+	*out = *in
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecAnnotations.
+func (in *DevfileRegistrySpecAnnotations) DeepCopy() *DevfileRegistrySpecAnnotations {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecAnnotations)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevfileRegistrySpecContainer) DeepCopyInto(out *DevfileRegistrySpecContainer) {
 	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecContainer.
@@ -304,6 +439,77 @@ func (in *DevfileRegistrySpecContainer) DeepCopy() *DevfileRegistrySpecContainer
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecExposure) DeepCopyInto(out *DevfileRegistrySpecExposure) {
+	*out = *in
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(DevfileRegistrySpecGateway)
+		**out = **in
+	}
+	in.OCIRegistry.DeepCopyInto(&out.OCIRegistry)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecExposure.
+func (in *DevfileRegistrySpecExposure) DeepCopy() *DevfileRegistrySpecExposure {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecExposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecGarbageCollection) DeepCopyInto(out *DevfileRegistrySpecGarbageCollection) {
+	*out = *in
+	if in.DeleteUntagged != nil {
+		in, out := &in.DeleteUntagged, &out.DeleteUntagged
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecGarbageCollection.
+func (in *DevfileRegistrySpecGarbageCollection) DeepCopy() *DevfileRegistrySpecGarbageCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecGarbageCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecGateway) DeepCopyInto(out *DevfileRegistrySpecGateway) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecGateway.
+func (in *DevfileRegistrySpecGateway) DeepCopy() *DevfileRegistrySpecGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecGitSource) DeepCopyInto(out *DevfileRegistrySpecGitSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecGitSource.
+func (in *DevfileRegistrySpecGitSource) DeepCopy() *DevfileRegistrySpecGitSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecGitSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevfileRegistrySpecK8sOnly) DeepCopyInto(out *DevfileRegistrySpecK8sOnly) {
 	*out = *in
@@ -319,6 +525,231 @@ func (in *DevfileRegistrySpecK8sOnly) DeepCopy() *DevfileRegistrySpecK8sOnly {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecMirrorSource) DeepCopyInto(out *DevfileRegistrySpecMirrorSource) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecMirrorSource.
+func (in *DevfileRegistrySpecMirrorSource) DeepCopy() *DevfileRegistrySpecMirrorSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecMirrorSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecMonitoring) DeepCopyInto(out *DevfileRegistrySpecMonitoring) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecMonitoring.
+func (in *DevfileRegistrySpecMonitoring) DeepCopy() *DevfileRegistrySpecMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecOCIAuth) DeepCopyInto(out *DevfileRegistrySpecOCIAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecOCIAuth.
+func (in *DevfileRegistrySpecOCIAuth) DeepCopy() *DevfileRegistrySpecOCIAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecOCIAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecOCIRegistry) DeepCopyInto(out *DevfileRegistrySpecOCIRegistry) {
+	*out = *in
+	in.DevfileRegistrySpecContainer.DeepCopyInto(&out.DevfileRegistrySpecContainer)
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(DevfileRegistrySpecOCIAuth)
+		**out = **in
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(DevfileRegistrySpecGarbageCollection)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecOCIRegistry.
+func (in *DevfileRegistrySpecOCIRegistry) DeepCopy() *DevfileRegistrySpecOCIRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecOCIRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecOCIRegistryExposure) DeepCopyInto(out *DevfileRegistrySpecOCIRegistryExposure) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecOCIRegistryExposure.
+func (in *DevfileRegistrySpecOCIRegistryExposure) DeepCopy() *DevfileRegistrySpecOCIRegistryExposure {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecOCIRegistryExposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecOCIStorage) DeepCopyInto(out *DevfileRegistrySpecOCIStorage) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(DevfileRegistrySpecS3Storage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecOCIStorage.
+func (in *DevfileRegistrySpecOCIStorage) DeepCopy() *DevfileRegistrySpecOCIStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecOCIStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecS3Storage) DeepCopyInto(out *DevfileRegistrySpecS3Storage) {
+	*out = *in
+	if in.Secure != nil {
+		in, out := &in.Secure, &out.Secure
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecS3Storage.
+func (in *DevfileRegistrySpecS3Storage) DeepCopy() *DevfileRegistrySpecS3Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecS3Storage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecScheduling) DeepCopyInto(out *DevfileRegistrySpecScheduling) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecScheduling.
+func (in *DevfileRegistrySpecScheduling) DeepCopy() *DevfileRegistrySpecScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecSource) DeepCopyInto(out *DevfileRegistrySpecSource) {
+	*out = *in
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(DevfileRegistrySpecGitSource)
+		**out = **in
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(DevfileRegistrySpecMirrorSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecSource.
+func (in *DevfileRegistrySpecSource) DeepCopy() *DevfileRegistrySpecSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevfileRegistrySpecStorage) DeepCopyInto(out *DevfileRegistrySpecStorage) {
 	*out = *in
@@ -327,6 +758,26 @@ func (in *DevfileRegistrySpecStorage) DeepCopyInto(out *DevfileRegistrySpecStora
 		*out = new(bool)
 		**out = **in
 	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(DevfileRegistrySpecStorageBackup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestoreFrom != nil {
+		in, out := &in.RestoreFrom, &out.RestoreFrom
+		*out = new(DevfileRegistrySpecStorageRestore)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecStorage.
@@ -339,6 +790,46 @@ func (in *DevfileRegistrySpecStorage) DeepCopy() *DevfileRegistrySpecStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecStorageBackup) DeepCopyInto(out *DevfileRegistrySpecStorageBackup) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecStorageBackup.
+func (in *DevfileRegistrySpecStorageBackup) DeepCopy() *DevfileRegistrySpecStorageBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecStorageBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevfileRegistrySpecStorageRestore) DeepCopyInto(out *DevfileRegistrySpecStorageRestore) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevfileRegistrySpecStorageRestore.
+func (in *DevfileRegistrySpecStorageRestore) DeepCopy() *DevfileRegistrySpecStorageRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(DevfileRegistrySpecStorageRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevfileRegistrySpecTLS) DeepCopyInto(out *DevfileRegistrySpecTLS) {
 	*out = *in
@@ -377,6 +868,27 @@ func (in *DevfileRegistrySpecTelemetry) DeepCopy() *DevfileRegistrySpecTelemetry
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevfileRegistryStatus) DeepCopyInto(out *DevfileRegistryStatus) {
 	*out = *in
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastGarbageCollectionTime != nil {
+		in, out := &in.LastGarbageCollectionTime, &out.LastGarbageCollectionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSourceCheckTime != nil {
+		in, out := &in.LastSourceCheckTime, &out.LastSourceCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastMirrorSyncTime != nil {
+		in, out := &in.LastMirrorSyncTime, &out.LastMirrorSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]DevfileRegistryComponentStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -395,3 +907,112 @@ func (in *DevfileRegistryStatus) DeepCopy() *DevfileRegistryStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryOperatorConfig) DeepCopyInto(out *RegistryOperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryOperatorConfig.
+func (in *RegistryOperatorConfig) DeepCopy() *RegistryOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegistryOperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryOperatorConfigContainer) DeepCopyInto(out *RegistryOperatorConfigContainer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryOperatorConfigContainer.
+func (in *RegistryOperatorConfigContainer) DeepCopy() *RegistryOperatorConfigContainer {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryOperatorConfigContainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryOperatorConfigList) DeepCopyInto(out *RegistryOperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RegistryOperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryOperatorConfigList.
+func (in *RegistryOperatorConfigList) DeepCopy() *RegistryOperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryOperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegistryOperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryOperatorConfigSpec) DeepCopyInto(out *RegistryOperatorConfigSpec) {
+	*out = *in
+	out.DevfileIndex = in.DevfileIndex
+	out.OciRegistry = in.OciRegistry
+	out.RegistryViewer = in.RegistryViewer
+	out.IndexBuilder = in.IndexBuilder
+	if in.ImagePrefixRewrites != nil {
+		in, out := &in.ImagePrefixRewrites, &out.ImagePrefixRewrites
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLSEnabled != nil {
+		in, out := &in.TLSEnabled, &out.TLSEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForbiddenNamespaces != nil {
+		in, out := &in.ForbiddenNamespaces, &out.ForbiddenNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryOperatorConfigSpec.
+func (in *RegistryOperatorConfigSpec) DeepCopy() *RegistryOperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryOperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
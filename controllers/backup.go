@@ -0,0 +1,267 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/registry"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// restoreStorageIfNeeded replaces the registry PVC with one provisioned from the restore source of the custom
+// resource, if it was not provisioned from it yet. The registry deployment is deleted along with the PVC, so that
+// the PVC is released, and both are recreated once the PVC is gone. Returns true while the restore is in progress.
+func (r *DevfileRegistryReconciler) restoreStorageIfNeeded(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (bool, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.PVCName(cr), Namespace: cr.Namespace}, pvc)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// PVC not created yet, it is provisioned from the restore source when it is
+			r.updateStorageRestoreStatus(cr, nil)
+			return false, nil
+		}
+		r.Log.Error(err, "Error getting PersistentVolumeClaim")
+		return false, err
+	}
+	if pvc.DeletionTimestamp != nil {
+		// Wait for the registry pods to release the PVC before recreating it
+		return true, nil
+	}
+
+	source := registry.GetRestoreSource(cr)
+	restoredFrom := pvc.Annotations[registry.RestoredFromAnnotation]
+	if source == "" && restoredFrom != "" {
+		// Forget the previous source, so that setting it again restores it again
+		delete(pvc.Annotations, registry.RestoredFromAnnotation)
+		if err := r.Update(ctx, pvc); err != nil {
+			r.Log.Error(err, "Error updating PersistentVolumeClaim")
+			return false, err
+		}
+	}
+	if source == restoredFrom || source == "" {
+		r.updateStorageRestoreStatus(cr, nil)
+		return false, nil
+	}
+
+	// Only replace the volume once its source is known to exist, a PVC whose source is missing never gets bound
+	failed, err := r.checkRestoreSource(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	if failed != nil {
+		r.updateStorageRestoreStatus(cr, failed)
+		return false, nil
+	}
+
+	r.Log.Info("Restoring the DevfileRegistry storage", "from", source)
+	dep := &appsv1.Deployment{}
+	err = r.Get(ctx, types.NamespacedName{Name: registry.DeploymentName(cr), Namespace: cr.Namespace}, dep)
+	if err == nil {
+		err = r.Delete(ctx, dep, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "Error deleting Deployment")
+		return false, err
+	}
+	if err := r.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "Error deleting PersistentVolumeClaim", "name", pvc.Name)
+		return false, err
+	}
+	r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonPVCDeleted,
+		"Deleted PersistentVolumeClaim %s to restore it from %s", pvc.Name, source)
+
+	r.updateStorageRestoreStatus(cr, &metav1.Condition{
+		Status:  metav1.ConditionTrue,
+		Reason:  "Restoring",
+		Message: fmt.Sprintf("Replacing the registry volume with one provisioned from %s", source),
+	})
+	return true, nil
+}
+
+// checkRestoreSource returns a condition telling why the restore source of the custom resource cannot be used,
+// or nil if it can
+func (r *DevfileRegistryReconciler) checkRestoreSource(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (*metav1.Condition, error) {
+	restoreFrom := cr.Spec.Storage.RestoreFrom
+	var source client.Object
+	var name string
+	if restoreFrom.VolumeSnapshot != "" {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(registry.VolumeSnapshotGVK)
+		source, name = snapshot, restoreFrom.VolumeSnapshot
+	} else {
+		if restoreFrom.PersistentVolumeClaim == registry.PVCName(cr) {
+			return &metav1.Condition{
+				Status:  metav1.ConditionFalse,
+				Reason:  "InvalidSource",
+				Message: "The registry volume cannot be restored from itself",
+			}, nil
+		}
+		source, name = &corev1.PersistentVolumeClaim{}, restoreFrom.PersistentVolumeClaim
+	}
+
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, source)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return &metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  "SourceNotFound",
+			Message: fmt.Sprintf("Restore source %s not found, the registry volume is left unchanged", registry.GetRestoreSource(cr)),
+		}, nil
+	}
+	if err != nil {
+		r.Log.Error(err, "Error getting the restore source")
+		return nil, err
+	}
+	if snapshot, isSnapshot := source.(*unstructured.Unstructured); isSnapshot {
+		if ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); !ready {
+			return &metav1.Condition{
+				Status:  metav1.ConditionFalse,
+				Reason:  "SourceNotReady",
+				Message: fmt.Sprintf("Volume snapshot %s is not ready to use yet, the registry volume is left unchanged", name),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// reconcileBackups takes a snapshot of the registry volume when one is due and deletes the snapshots exceeding the
+// retention. Returns the time left until the next snapshot is due, or zero if none is scheduled.
+func (r *DevfileRegistryReconciler) reconcileBackups(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (time.Duration, error) {
+	if !registry.IsBackupEnabled(cr) {
+		r.updateBackupStatus(cr, nil, nil)
+		return 0, nil
+	}
+
+	snapshots := &unstructured.UnstructuredList{}
+	snapshots.SetGroupVersionKind(registry.VolumeSnapshotGVK.GroupVersion().WithKind(registry.VolumeSnapshotGVK.Kind + "List"))
+	err := r.List(ctx, snapshots, client.InNamespace(cr.Namespace), client.MatchingLabels(registry.GetBackupLabels(cr)))
+	if meta.IsNoMatchError(err) {
+		r.updateBackupStatus(cr, nil, &metav1.Condition{
+			Reason:  "VolumeSnapshotsNotSupported",
+			Message: "The volume snapshot API is not installed on the cluster",
+		})
+		return 0, nil
+	}
+	if err != nil {
+		r.Log.Error(err, "Failed to list VolumeSnapshots")
+		return 0, err
+	}
+	backups := snapshots.Items
+	registry.SortBackups(backups)
+
+	// Without any snapshot yet, the first one is due on the first scheduled time after the registry was created
+	now := time.Now()
+	lastBackupTime := cr.CreationTimestamp.Time
+	if len(backups) > 0 {
+		lastBackupTime = registry.GetBackupScheduledAt(&backups[len(backups)-1])
+	}
+	next, err := registry.GetNextBackupTime(cr, lastBackupTime)
+	if err != nil {
+		r.updateBackupStatus(cr, nil, &metav1.Condition{
+			Reason:  "InvalidSchedule",
+			Message: err.Error(),
+		})
+		return 0, nil
+	}
+
+	if !next.IsZero() && !next.After(now) {
+		// Missed scheduled times only result in one snapshot, taken now
+		snapshot := registry.GenerateVolumeSnapshot(cr, now.Truncate(time.Minute))
+		r.Log.Info("Taking a snapshot of the DevfileRegistry storage", "VolumeSnapshot.Name", snapshot.GetName())
+		if err := r.Create(ctx, snapshot); err != nil && !errors.IsAlreadyExists(err) {
+			r.Log.Error(err, "Failed to create VolumeSnapshot")
+			r.updateBackupStatus(cr, nil, &metav1.Condition{
+				Reason:  "SnapshotFailed",
+				Message: err.Error(),
+			})
+			return 0, nil
+		}
+		backups = append(backups, *snapshot)
+
+		expired := registry.GetExpiredBackups(cr, backups)
+		for i := range expired {
+			r.Log.Info("Deleting the expired snapshot of the DevfileRegistry storage", "VolumeSnapshot.Name", expired[i].GetName())
+			if err := r.Delete(ctx, &expired[i]); err != nil && !errors.IsNotFound(err) {
+				r.Log.Error(err, "Failed to delete VolumeSnapshot", "name", expired[i].GetName())
+				return 0, err
+			}
+		}
+
+		if next, err = registry.GetNextBackupTime(cr, now.Truncate(time.Minute)); err != nil {
+			return 0, err
+		}
+	}
+
+	// Report the error of the last snapshot, if any
+	var lastBackup *unstructured.Unstructured
+	var failed *metav1.Condition
+	if len(backups) > 0 {
+		lastBackup = &backups[len(backups)-1]
+		if message, found, _ := unstructured.NestedString(lastBackup.Object, "status", "error", "message"); found {
+			failed = &metav1.Condition{
+				Reason:  "SnapshotFailed",
+				Message: message,
+			}
+		}
+	}
+	r.updateBackupStatus(cr, lastBackup, failed)
+
+	if next.IsZero() {
+		return 0, nil
+	}
+	return next.Sub(now), nil
+}
+
+// updateStorageRestoreStatus reports the restore of the registry volume, restore being nil if no restore is pending
+func (r *DevfileRegistryReconciler) updateStorageRestoreStatus(cr *registryv1alpha1.DevfileRegistry, restore *metav1.Condition) {
+	if restore != nil {
+		restore.Type = typeStorageRestoring
+		meta.SetStatusCondition(&cr.Status.Conditions, *restore)
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typeStorageRestoring)
+	}
+}
+
+// updateBackupStatus reports the last snapshot of the registry volume and why the scheduled snapshots fail, failed
+// being nil if they do not
+func (r *DevfileRegistryReconciler) updateBackupStatus(cr *registryv1alpha1.DevfileRegistry, lastBackup *unstructured.Unstructured, failed *metav1.Condition) {
+	if lastBackup != nil {
+		lastBackupTime := metav1.NewTime(registry.GetBackupScheduledAt(lastBackup))
+		cr.Status.LastBackupTime = &lastBackupTime
+		cr.Status.LastBackupSnapshot = lastBackup.GetName()
+	} else if !registry.IsBackupEnabled(cr) {
+		cr.Status.LastBackupTime = nil
+		cr.Status.LastBackupSnapshot = ""
+	}
+	if failed != nil {
+		failed.Type = typeBackupFailed
+		failed.Status = metav1.ConditionTrue
+		meta.SetStatusCondition(&cr.Status.Conditions, *failed)
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typeBackupFailed)
+	}
+}
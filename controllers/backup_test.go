@@ -0,0 +1,218 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/registry"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRestoreStorageIfNeeded(t *testing.T) {
+	scheme := newTestScheme()
+	storageEnabled := true
+
+	readySnapshot := &unstructured.Unstructured{}
+	readySnapshot.SetGroupVersionKind(registry.VolumeSnapshotGVK)
+	readySnapshot.SetName("good-snapshot")
+	readySnapshot.SetNamespace("test")
+	_ = unstructured.SetNestedField(readySnapshot.Object, true, "status", "readyToUse")
+
+	tests := []struct {
+		name          string
+		restoreFrom   *registryv1alpha1.DevfileRegistrySpecStorageRestore
+		restoredFrom  string
+		wantRestoring bool
+		wantReason    string
+	}{
+		{
+			name:          "No restore source",
+			restoreFrom:   nil,
+			wantRestoring: false,
+		},
+		{
+			name:          "Volume already restored from the source",
+			restoreFrom:   &registryv1alpha1.DevfileRegistrySpecStorageRestore{VolumeSnapshot: "good-snapshot"},
+			restoredFrom:  "VolumeSnapshot/good-snapshot",
+			wantRestoring: false,
+		},
+		{
+			name:          "New snapshot to restore",
+			restoreFrom:   &registryv1alpha1.DevfileRegistrySpecStorageRestore{VolumeSnapshot: "good-snapshot"},
+			wantRestoring: true,
+			wantReason:    "Restoring",
+		},
+		{
+			name:          "Missing snapshot",
+			restoreFrom:   &registryv1alpha1.DevfileRegistrySpecStorageRestore{VolumeSnapshot: "missing-snapshot"},
+			wantRestoring: false,
+			wantReason:    "SourceNotFound",
+		},
+		{
+			name:          "Clone of its own volume",
+			restoreFrom:   &registryv1alpha1.DevfileRegistrySpecStorageRestore{PersistentVolumeClaim: "devfile-registry"},
+			wantRestoring: false,
+			wantReason:    "InvalidSource",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+						Enabled:     &storageEnabled,
+						RestoreFrom: tt.restoreFrom,
+					},
+				},
+			}
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: registry.PVCName(cr), Namespace: cr.Namespace},
+			}
+			if tt.restoredFrom != "" {
+				pvc.Annotations = map[string]string{registry.RestoredFromAnnotation: tt.restoredFrom}
+			}
+			dep := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: registry.DeploymentName(cr), Namespace: cr.Namespace},
+			}
+			r := newTestReconciler(scheme, cr, pvc, dep, readySnapshot.DeepCopy())
+
+			restoring, err := r.restoreStorageIfNeeded(context.TODO(), cr)
+			if err != nil {
+				t.Fatalf("restoreStorageIfNeeded() unexpected error: %v", err)
+			}
+			if restoring != tt.wantRestoring {
+				t.Errorf("restoreStorageIfNeeded() = %v, want %v", restoring, tt.wantRestoring)
+			}
+
+			for _, obj := range []client.Object{&corev1.PersistentVolumeClaim{}, &appsv1.Deployment{}} {
+				err := r.Get(context.TODO(), client.ObjectKey{Name: registry.PVCName(cr), Namespace: cr.Namespace}, obj)
+				if deleted := errors.IsNotFound(err); deleted != tt.wantRestoring {
+					t.Errorf("restoreStorageIfNeeded() deleted %T = %v, want %v", obj, deleted, tt.wantRestoring)
+				}
+			}
+
+			condition := meta.FindStatusCondition(cr.Status.Conditions, typeStorageRestoring)
+			if tt.wantReason == "" && condition != nil {
+				t.Errorf("restoreStorageIfNeeded() unexpected condition %v", condition)
+			} else if tt.wantReason != "" && (condition == nil || condition.Reason != tt.wantReason) {
+				t.Errorf("restoreStorageIfNeeded() condition = %v, want reason %v", condition, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestReconcileBackups(t *testing.T) {
+	scheme := newTestScheme()
+	storageEnabled := true
+	retention := int32(2)
+	now := time.Now().Truncate(time.Minute)
+
+	tests := []struct {
+		name            string
+		schedule        string
+		lastBackupTimes []time.Time
+		wantSnapshots   []time.Time
+	}{
+		{
+			name:          "First snapshot due",
+			schedule:      "* * * * *",
+			wantSnapshots: []time.Time{now},
+		},
+		{
+			name:            "Snapshot not due yet",
+			schedule:        "0 0 1 1 *",
+			lastBackupTimes: []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour)},
+			wantSnapshots:   []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour)},
+		},
+		{
+			name:            "Snapshot due, oldest one expired",
+			schedule:        "* * * * *",
+			lastBackupTimes: []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour)},
+			wantSnapshots:   []time.Time{now.Add(-time.Hour), now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "devfile-registry",
+					Namespace:         "test",
+					CreationTimestamp: metav1.NewTime(now.Add(-3 * time.Hour)),
+				},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+						Enabled: &storageEnabled,
+						Backup: &registryv1alpha1.DevfileRegistrySpecStorageBackup{
+							Schedule:  tt.schedule,
+							Retention: &retention,
+						},
+					},
+				},
+			}
+			objects := []client.Object{cr}
+			for _, lastBackupTime := range tt.lastBackupTimes {
+				objects = append(objects, registry.GenerateVolumeSnapshot(cr, lastBackupTime))
+			}
+			r := newTestReconciler(scheme, objects...)
+
+			requeueAfter, err := r.reconcileBackups(context.TODO(), cr)
+			if err != nil {
+				t.Fatalf("reconcileBackups() unexpected error: %v", err)
+			}
+			if requeueAfter <= 0 {
+				t.Errorf("reconcileBackups() requeue after %v, want the time until the next snapshot", requeueAfter)
+			}
+
+			snapshots := &unstructured.UnstructuredList{}
+			snapshots.SetGroupVersionKind(registry.VolumeSnapshotGVK.GroupVersion().WithKind(registry.VolumeSnapshotGVK.Kind + "List"))
+			if err := r.List(context.TODO(), snapshots, client.InNamespace(cr.Namespace)); err != nil {
+				t.Fatalf("failed to list VolumeSnapshots: %v", err)
+			}
+			registry.SortBackups(snapshots.Items)
+			var got []time.Time
+			for i := range snapshots.Items {
+				got = append(got, registry.GetBackupScheduledAt(&snapshots.Items[i]))
+			}
+			if len(got) != len(tt.wantSnapshots) {
+				t.Fatalf("reconcileBackups() snapshots = %v, want %v", got, tt.wantSnapshots)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.wantSnapshots[i]) {
+					t.Errorf("reconcileBackups() snapshots = %v, want %v", got, tt.wantSnapshots)
+				}
+			}
+
+			last := tt.wantSnapshots[len(tt.wantSnapshots)-1]
+			if cr.Status.LastBackupTime == nil || !cr.Status.LastBackupTime.Time.Equal(last) || cr.Status.LastBackupSnapshot != registry.VolumeSnapshotName(cr, last) {
+				t.Errorf("reconcileBackups() last backup = %v %s, want %v", cr.Status.LastBackupTime, cr.Status.LastBackupSnapshot, last)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// NewCacheOptions returns the cache options of the manager: the secrets and config maps of the cluster are not all
+// cached, only the ones generated for the DevfileRegistry resources. The ones of the users are read with the API reader.
+func NewCacheOptions() cache.Options {
+	return cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Secret{}:    {Label: registry.GeneratedResourcesSelector()},
+			&corev1.ConfigMap{}: {Label: registry.GeneratedResourcesSelector()},
+		},
+	}
+}
+
+// getUserSecret reads a secret of the user, which is not cached by the manager
+func (r *DevfileRegistryReconciler) getUserSecret(ctx context.Context, key types.NamespacedName, secret *corev1.Secret) error {
+	if r.APIReader == nil {
+		return r.Get(ctx, key, secret)
+	}
+	return r.APIReader.Get(ctx, key, secret)
+}
+
+// getUserConfigMap reads a config map of the user, which is not cached by the manager
+func (r *DevfileRegistryReconciler) getUserConfigMap(ctx context.Context, key types.NamespacedName, cm *corev1.ConfigMap) error {
+	if r.APIReader == nil {
+		return r.Get(ctx, key, cm)
+	}
+	return r.APIReader.Get(ctx, key, cm)
+}
@@ -0,0 +1,107 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewCacheOptions(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"}}
+
+	var secretSelector, configMapSelector labels.Selector
+	for object, byObject := range NewCacheOptions().ByObject {
+		switch object.(type) {
+		case *corev1.Secret:
+			secretSelector = byObject.Label
+		case *corev1.ConfigMap:
+			configMapSelector = byObject.Label
+		}
+	}
+	if secretSelector == nil {
+		t.Fatalf("NewCacheOptions() does not restrict the cached secrets")
+	}
+	if configMapSelector == nil {
+		t.Fatalf("NewCacheOptions() does not restrict the cached config maps")
+	}
+
+	secret := registry.GenerateOCIRegistryAuthSecret(cr, "", runtime.NewScheme(), registry.LabelsForDevfileRegistry(cr))
+	if !secretSelector.Matches(labels.Set(secret.Labels)) {
+		t.Errorf("NewCacheOptions() selector %s does not match the generated secret labels %v", secretSelector, secret.Labels)
+	}
+	if secretSelector.Matches(labels.Set{"app": "my-app"}) {
+		t.Errorf("NewCacheOptions() selector %s matches the secrets of the users", secretSelector)
+	}
+
+	for _, cm := range []*corev1.ConfigMap{
+		registry.GenerateRegistryConfigMap(cr, "", runtime.NewScheme(), registry.LabelsForDevfileRegistry(cr)),
+		registry.GenerateMirrorConfigMap(cr, "[]", runtime.NewScheme(), registry.LabelsForDevfileRegistry(cr)),
+	} {
+		if !configMapSelector.Matches(labels.Set(cm.Labels)) {
+			t.Errorf("NewCacheOptions() selector %s does not match the generated config map labels %v", configMapSelector, cm.Labels)
+		}
+	}
+	if configMapSelector.Matches(labels.Set{"app": "my-app"}) {
+		t.Errorf("NewCacheOptions() selector %s matches the config maps of the users", configMapSelector)
+	}
+}
+
+func TestGetUserSecret(t *testing.T) {
+	scheme := newTestScheme()
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "my-secret", Namespace: "test"}}
+	key := types.NamespacedName{Name: "my-secret", Namespace: "test"}
+
+	// The cached client does not hold the secrets of the users
+	r := newTestReconciler(scheme)
+	r.APIReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	if err := r.getUserSecret(context.TODO(), key, &corev1.Secret{}); err != nil {
+		t.Errorf("getUserSecret() unexpected error: %v", err)
+	}
+
+	r.APIReader = nil
+	if err := r.getUserSecret(context.TODO(), key, &corev1.Secret{}); err == nil {
+		t.Errorf("getUserSecret() expected the client to be used without an API reader")
+	}
+}
+
+func TestGetUserConfigMap(t *testing.T) {
+	scheme := newTestScheme()
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "my-config", Namespace: "test"}}
+	key := types.NamespacedName{Name: "my-config", Namespace: "test"}
+
+	// The cached client does not hold the config maps of the users
+	r := newTestReconciler(scheme)
+	r.APIReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+	if err := r.getUserConfigMap(context.TODO(), key, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("getUserConfigMap() unexpected error: %v", err)
+	}
+
+	r.APIReader = nil
+	if err := r.getUserConfigMap(context.TODO(), key, &corev1.ConfigMap{}); err == nil {
+		t.Errorf("getUserConfigMap() expected the client to be used without an API reader")
+	}
+}
@@ -23,11 +23,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 
 	"github.com/go-logr/logr"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/metrics"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,13 +38,15 @@ import (
 // ClusterDevfileRegistriesListReconciler reconciles a ClusterDevfileRegistriesList object
 type ClusterDevfileRegistriesListReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=registry.devfile.io,resources=clusterdevfileregistrieslists,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=registry.devfile.io,resources=clusterdevfileregistrieslists/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=registry.devfile.io,resources=clusterdevfileregistrieslists/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -64,6 +68,7 @@ func (r *ClusterDevfileRegistriesListReconciler) Reconcile(ctx context.Context,
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			log.Info("ClusterDevfileRegistriesList resource not found. Ignoring since object must be deleted")
+			metrics.DeleteRegistryList(metrics.KindClusterDevfileRegistriesList, req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -19,8 +19,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/metrics"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
@@ -51,9 +53,12 @@ func (r *ClusterDevfileRegistriesListReconciler) SetValidateDevfileRegistriesCon
 		}
 	}
 
+	// Copy the unreachable registries of the previous validation before they are replaced
+	previous := slices.Clone(clusterDevfileRegistriesList.Status.UnreachableRegistries)
 	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		validateDevfileRegistriesAndUpdateCondition(clusterDevfileRegistriesList.Spec.DevfileRegistries, condition, func(c metav1.Condition) {
+		validateDevfileRegistriesAndUpdateCondition(r.Recorder, metrics.KindClusterDevfileRegistriesList, clusterDevfileRegistriesList, previous, clusterDevfileRegistriesList.Spec.DevfileRegistries, condition, func(c metav1.Condition, unreachable []string) {
 			meta.SetStatusCondition(&clusterDevfileRegistriesList.Status.Conditions, c)
+			clusterDevfileRegistriesList.Status.UnreachableRegistries = unreachable
 		})
 		return r.Status().Update(ctx, clusterDevfileRegistriesList)
 	})
@@ -16,9 +16,28 @@
 
 package controllers
 
+// Standard conditions of a DevfileRegistry, summarizing the ones below
 const (
-	typeValidateDevfileRegistries = "ValidateDevfileRegistries"
-	typeUpdateDevfileRegistries   = "UpdateDevfileRegistries"
-	typeUpdateDevfileRegistry     = "UpdateDevfileRegistry"
-	typeNoDeployDevfileRegistry   = "NoDeployDevfileRegistry"
+	typeAvailable   = "Available"
+	typeProgressing = "Progressing"
+	typeDegraded    = "Degraded"
+)
+
+const (
+	typeValidateDevfileRegistries  = "ValidateDevfileRegistries"
+	typeUpdateDevfileRegistries    = "UpdateDevfileRegistries"
+	typeUpdateDevfileRegistry      = "UpdateDevfileRegistry"
+	typeNoDeployDevfileRegistry    = "NoDeployDevfileRegistry"
+	typeReplicasLimited            = "ReplicasLimited"
+	typeStorageResizeBlocked       = "StorageResizeBlocked"
+	typeRegistryConfigInvalid      = "RegistryConfigInvalid"
+	typePodTemplateOverrideInvalid = "PodTemplateOverrideInvalid"
+	typeStorageRestoring           = "StorageRestoring"
+	typeBackupFailed               = "BackupFailed"
+	typeGitSourceFailed            = "GitSourceFailed"
+	typeMirrorSyncFailed           = "MirrorSyncFailed"
+	typeMirrorSyncInProgress       = "MirrorSyncInProgress"
+	typeMonitoringUnavailable      = "MonitoringUnavailable"
+	typeGatewayUnavailable         = "GatewayUnavailable"
+	typeServerNotReady             = "ServerNotReady"
 )
@@ -19,8 +19,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/metrics"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
@@ -51,9 +53,12 @@ func (r *DevfileRegistriesListReconciler) SetValidateDevfileRegistriesConditionA
 		}
 	}
 
+	// Copy the unreachable registries of the previous validation before they are replaced
+	previous := slices.Clone(devfileRegistriesList.Status.UnreachableRegistries)
 	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		validateDevfileRegistriesAndUpdateCondition(devfileRegistriesList.Spec.DevfileRegistries, condition, func(c metav1.Condition) {
+		validateDevfileRegistriesAndUpdateCondition(r.Recorder, metrics.KindDevfileRegistriesList, devfileRegistriesList, previous, devfileRegistriesList.Spec.DevfileRegistries, condition, func(c metav1.Condition, unreachable []string) {
 			meta.SetStatusCondition(&devfileRegistriesList.Status.Conditions, c)
+			devfileRegistriesList.Status.UnreachableRegistries = unreachable
 		})
 		return r.Status().Update(ctx, devfileRegistriesList)
 	})
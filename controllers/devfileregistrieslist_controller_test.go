@@ -64,6 +64,8 @@ var _ = Describe("DevfileRegistriesList controller test", func() {
 			Expect(k8sClient.Update(ctx, drl)).Should(Succeed())
 			// verify unreachable status
 			validateStatus(drlLookupKey, NamespaceListType, fmt.Sprintf(registryUnreachable, testServer.URL))
+			Expect(k8sClient.Get(ctx, drlLookupKey, drl)).Should(Succeed())
+			Expect(drl.Status.UnreachableRegistries).Should(ConsistOf(testServer.URL))
 
 		})
 	})
@@ -21,53 +21,87 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	configv1 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 	"github.com/devfile/registry-operator/pkg/cluster"
 	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/metrics"
 	"github.com/devfile/registry-operator/pkg/registry"
 	"github.com/devfile/registry-operator/pkg/util"
 )
 
+// userConfigCheckInterval is the time between two reads of the config map holding the OCI registry configuration
+// and of the htpasswd secret of a DevfileRegistry, which are not watched
+const userConfigCheckInterval = time.Minute
+
 // DevfileRegistryReconciler reconciles a DevfileRegistry object
 type DevfileRegistryReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	// APIReader reads the resources the client does not cache, such as the secrets and config maps of the users. Defaults to the client.
+	APIReader client.Reader
+	Log       logr.Logger
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	// Config resolves the operator-wide defaults of the registries
+	Config *config.Provider
+
+	// defaults are the operator-wide defaults resolved at the start of the current reconcile
+	defaults config.Defaults
+	// mirrorSyncer copies the stacks of the mirrored devfile registries in the background
+	mirrorSyncer *mirrorSyncer
 }
 
 // +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistries,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistries/status;devfileregistries/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=registry.devfile.io,resources=registryoperatorconfigs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps;services;persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingressclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=ingresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;patch;delete
 
-func (r *DevfileRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *DevfileRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	log := r.Log.WithValues("devfileregistry", req.NamespacedName)
 
 	// Fetch the DevfileRegistry instance
 	devfileRegistry := &registryv1alpha1.DevfileRegistry{}
-	err := r.Get(ctx, req.NamespacedName, devfileRegistry)
+	err = r.Get(ctx, req.NamespacedName, devfileRegistry)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			log.Info("DevfileRegistry resource not found. Ignoring since object must be deleted")
+			metrics.DeleteRegistry(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -75,38 +109,54 @@ func (r *DevfileRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	// Block the Devfile Registry deployment if an Ingress domain is missing for Kubernetes
-	if !config.ControllerCfg.IsOpenShift() && registry.IsIngressSkipped(devfileRegistry) {
+	// The status is changed in memory along the reconcile and written once at the end, whatever its outcome, along
+	// with the rollout of the registry deployment
+	original := devfileRegistry.DeepCopy()
+	defer func() {
+		if rolloutErr := r.updateRolloutStatus(ctx, devfileRegistry); rolloutErr != nil && err == nil {
+			err = rolloutErr
+		}
+		if statusErr := r.patchStatus(ctx, original, devfileRegistry); statusErr != nil && err == nil {
+			err = statusErr
+		}
+	}()
+
+	// Resolve the defaults of the RegistryOperatorConfig resource and of the cluster once, so that the whole registry
+	// is deployed with the same ones
+	defaults, err := r.Config.GetDefaults(ctx)
+	if err != nil {
+		log.Error(err, "Failed to get the operator defaults")
+		return ctrl.Result{}, err
+	}
+	r = r.withDefaults(defaults)
+
+	r.updateGatewayStatus(devfileRegistry)
+
+	// Block the Devfile Registry deployment if it cannot be exposed, e.g. an Ingress domain is missing for Kubernetes
+	if blockedMessage := getExposureBlockedMessage(devfileRegistry, r.defaults); blockedMessage != "" {
 		meta.SetStatusCondition(&devfileRegistry.Status.Conditions, metav1.Condition{
 			Type:    typeNoDeployDevfileRegistry,
 			Status:  metav1.ConditionUnknown,
 			Reason:  "DeploymentBlocked",
-			Message: "No Ingress domain set for Devfile Registry - Deployment Blocked",
+			Message: blockedMessage,
 		})
 
-		log.Info("Blocked deployment due to unset Ingress domain")
-
-		err = r.Status().Update(ctx, devfileRegistry)
+		log.Info("Blocked deployment", "reason", blockedMessage)
+		r.Recorder.Event(devfileRegistry, corev1.EventTypeWarning, eventReasonDeploymentBlocked, blockedMessage)
+		metrics.IncDeploymentBlocked(req.Namespace, req.Name, metrics.BlockedReasonExposure)
 
-		return ctrl.Result{}, err
+		return ctrl.Result{}, nil
 	}
+	// An ingress domain can be set in the RegistryOperatorConfig after the deployment was blocked
+	meta.RemoveStatusCondition(&devfileRegistry.Status.Conditions, typeNoDeployDevfileRegistry)
 
 	if devfileRegistry.Status.Conditions == nil || len(devfileRegistry.Status.Conditions) == 0 {
-		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			meta.SetStatusCondition(&devfileRegistry.Status.Conditions, metav1.Condition{
-				Type:    typeUpdateDevfileRegistry,
-				Status:  metav1.ConditionUnknown,
-				Reason:  "NotReady",
-				Message: "Starting reconciliation",
-			})
-
-			return r.Status().Update(ctx, devfileRegistry)
+		meta.SetStatusCondition(&devfileRegistry.Status.Conditions, metav1.Condition{
+			Type:    typeUpdateDevfileRegistry,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "NotReady",
+			Message: "Starting reconciliation",
 		})
-
-		if err != nil {
-			log.Error(err, "Failed to update DevfileRegistriesList status")
-			return ctrl.Result{}, err
-		}
 	}
 
 	// Generate labels for any subresources generated by the operator
@@ -119,14 +169,55 @@ func (r *DevfileRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	if result != nil {
 		return *result, err
 	}
+	if err = r.reconcileInternalService(ctx, devfileRegistry, labels); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// If storage is enabled, create a persistent volume claim
 	if registry.IsStorageEnabled(devfileRegistry) {
+		// Replace the volume if a new restore source is set, the registry is redeployed once the old volume is gone
+		restoring, err := r.restoreStorageIfNeeded(ctx, devfileRegistry)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if restoring {
+			log.Info("Waiting for the old registry volume to be deleted before restoring it")
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
 		// Check if the persistentvolumeclaim already exists, if not create a new one
 		result, err = r.ensure(ctx, devfileRegistry, &corev1.PersistentVolumeClaim{}, labels, "")
 		if result != nil {
 			return *result, err
 		}
+	} else {
+		// Without persistent storage, there is no volume to resize or restore
+		r.updateStorageResizeStatus(devfileRegistry, nil)
+		r.updateStorageRestoreStatus(devfileRegistry, nil)
+	}
+
+	// The registry is not updated while the referenced OCI registry configuration is missing or invalid
+	_, invalidConfig, err := r.getRegistryConfig(ctx, devfileRegistry)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.updateRegistryConfigStatus(devfileRegistry, invalidConfig)
+	if invalidConfig != nil {
+		log.Info("Blocked deployment due to the OCI registry config", "reason", invalidConfig.Message)
+		r.Recorder.Event(devfileRegistry, corev1.EventTypeWarning, eventReasonDeploymentBlocked, invalidConfig.Message)
+		metrics.IncDeploymentBlocked(req.Namespace, req.Name, metrics.BlockedReasonRegistryConfig)
+		// The config map of the user is not watched, check it again until it is fixed
+		return ctrl.Result{RequeueAfter: userConfigCheckInterval}, nil
+	}
+
+	// The registry is not updated while its pod template override cannot be applied to the generated deployment
+	invalidOverride := getPodTemplateOverrideError(devfileRegistry, r.defaults, r.Scheme, labels)
+	r.updatePodTemplateOverrideStatus(devfileRegistry, invalidOverride)
+	if invalidOverride != nil {
+		log.Info("Blocked deployment due to the pod template override", "reason", invalidOverride.Message)
+		r.Recorder.Event(devfileRegistry, corev1.EventTypeWarning, eventReasonDeploymentBlocked, invalidOverride.Message)
+		metrics.IncDeploymentBlocked(req.Namespace, req.Name, metrics.BlockedReasonPodTemplateOverride)
+		return ctrl.Result{}, nil
 	}
 
 	result, err = r.ensure(ctx, devfileRegistry, &corev1.ConfigMap{}, labels, "")
@@ -134,52 +225,107 @@ func (r *DevfileRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return *result, err
 	}
 
+	// Generate a credential for the clients of the OCI registry and its htpasswd file if authentication is enabled
+	if registry.IsOCIRegistryAuthEnabled(devfileRegistry) {
+		result, err = r.ensure(ctx, devfileRegistry, &corev1.Secret{}, labels, "")
+		if result != nil {
+			return *result, err
+		}
+	} else {
+		err = r.deleteOldAuthSecretIfNeeded(ctx, devfileRegistry)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	r.updateOCIRegistryAuthStatus(devfileRegistry)
+
+	// Check the tracked revision of the Git source for new commits, the registry index is rebuilt when it changes
+	nextSourceCheck, err := r.reconcileGitSource(ctx, devfileRegistry)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Start a sync of the stacks of the mirrored devfile registry in the background when one is due
+	nextMirrorSync, err := r.reconcileMirror(ctx, devfileRegistry)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	result, err = r.ensure(ctx, devfileRegistry, &appsv1.Deployment{}, labels, "")
 	if result != nil {
 		return *result, err
 	}
 
-	// Check to see if there's an old PVC that needs to be deleted
-	// Has to happen AFTER the deployment has been updated.
-	err = r.deleteOldPVCIfNeeded(ctx, devfileRegistry)
+	// In high-availability mode, keep the registry available during node drains, otherwise clean up the old disruption budget
+	if registry.IsHighAvailabilityEnabled(devfileRegistry) {
+		result, err = r.ensure(ctx, devfileRegistry, &policyv1.PodDisruptionBudget{}, labels, "")
+		if result != nil {
+			return *result, err
+		}
+	} else {
+		err = r.deleteOldPDBIfNeeded(ctx, devfileRegistry)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	err = r.updateReplicaStatus(ctx, devfileRegistry)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Create/update the ingress/route for the devfile registry
-	hostname := devfileRegistry.Spec.K8s.IngressDomain
-	if config.ControllerCfg.IsOpenShift() && hostname == "" {
-		// Check if the route exposing the devfile index exists
-		result, err = r.ensure(ctx, devfileRegistry, &routev1.Route{}, labels, "")
+	// Have Prometheus scrape the registry metrics if monitoring is enabled, otherwise clean up the old ServiceMonitor
+	if registry.IsMonitoringEnabled(devfileRegistry) && config.IsMonitoringAPIAvailable() {
+		result, err = r.ensure(ctx, devfileRegistry, &monitoringv1.ServiceMonitor{}, labels, "")
 		if result != nil {
 			return *result, err
 		}
-
-		// Get the hostname of the generated devfile route
-		devfilesRoute := &routev1.Route{}
-		err = r.Get(ctx, types.NamespacedName{Name: registry.IngressName(devfileRegistry), Namespace: devfileRegistry.Namespace}, devfilesRoute)
+	} else {
+		err = r.deleteOldServiceMonitorIfNeeded(ctx, devfileRegistry)
 		if err != nil {
-			// Log an error, but requeue, as the controller's cached kube client likely hasn't registered the new route yet.
-			// See https://github.com/operator-framework/operator-sdk/issues/4013#issuecomment-707267616 for an explanation on why we requeue rather than error out here
-			log.Error(err, "Failed to get Route")
-			return ctrl.Result{Requeue: true}, nil
+			return ctrl.Result{}, err
 		}
-		hostname = devfilesRoute.Spec.Host
-	} else {
-		// Create/update the ingress for the devfile registry
-		hostname = registry.GetDevfileRegistryIngress(devfileRegistry)
-		result, err = r.ensure(ctx, devfileRegistry, &networkingv1.Ingress{}, labels, hostname)
+	}
+	r.updateMonitoringStatus(devfileRegistry)
+
+	// Collect the garbage of the OCI registry storage on schedule, otherwise clean up the old CronJob
+	if registry.IsGarbageCollectionEnabled(devfileRegistry) {
+		result, err = r.ensure(ctx, devfileRegistry, &batchv1.CronJob{}, labels, "")
 		if result != nil {
 			return *result, err
 		}
+	} else {
+		err = r.deleteOldGarbageCollectionCronJobIfNeeded(ctx, devfileRegistry)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	err = r.updateGarbageCollectionStatus(ctx, devfileRegistry)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Check to see if there's an old PVC that needs to be deleted
+	// Has to happen AFTER the deployment has been updated.
+	err = r.deleteOldPVCIfNeeded(ctx, devfileRegistry)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
-	var devfileRegistryServer string
+	// Take the scheduled snapshots of the registry volume, and reconcile again when the next one is due
+	nextBackup, err := r.reconcileBackups(ctx, devfileRegistry)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
-	if registry.IsTLSEnabled(devfileRegistry) {
-		devfileRegistryServer = "https://" + hostname
-	} else {
-		devfileRegistryServer = "http://" + hostname
+	// Create/update the resources exposing the devfile registry, and get its URL
+	devfileRegistryServer, result, err := r.reconcileExposure(ctx, devfileRegistry, labels)
+	if result != nil {
+		return *result, err
+	}
+	ociRegistryServer, result, err := r.reconcileOCIRegistryExposure(ctx, devfileRegistry, labels)
+	if result != nil {
+		return *result, err
 	}
 
 	if devfileRegistry.Status.URL != devfileRegistryServer {
@@ -187,37 +333,162 @@ func (r *DevfileRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		// when deploying a new devfile registry, it may not have a signed cert installed yet, so we will skip TLS checking.  We just want to make sure
 		// server is up and running
 		err = util.WaitForServer(devfileRegistryServer, 30*time.Second, false)
+		r.updateServerReadinessStatus(devfileRegistry, devfileRegistryServer, err)
 		if err != nil {
 			log.Error(err, "Devfile registry server failed to start after 30 seconds, re-queueing...")
+			r.Recorder.Eventf(devfileRegistry, corev1.EventTypeWarning, eventReasonReadinessTimeout,
+				"Devfile registry server %s failed to start after 30 seconds", devfileRegistryServer)
+			metrics.IncReadinessTimeout(req.Namespace, req.Name)
 			return ctrl.Result{Requeue: true}, err
 		}
 
-		// Update the status
 		devfileRegistry.Status.URL = devfileRegistryServer
-		err := r.Status().Update(ctx, devfileRegistry)
-		if err != nil {
-			log.Error(err, "Failed to update DevfileRegistry status")
-			return ctrl.Result{Requeue: true}, err
-		}
 	}
 
-	// Update condition status
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		meta.SetStatusCondition(&devfileRegistry.Status.Conditions, metav1.Condition{
-			Type:    typeUpdateDevfileRegistry,
-			Status:  metav1.ConditionTrue,
-			Reason:  "Ready",
-			Message: "Devfile Registry deployed",
-		})
-		return r.Status().Update(ctx, devfileRegistry)
+	r.updateEndpointStatus(devfileRegistry, ociRegistryServer)
+	registryInfo := metrics.RegistryInfo{
+		Namespace:         devfileRegistry.Namespace,
+		Name:              devfileRegistry.Name,
+		URL:               devfileRegistry.Status.URL,
+		DevfileIndexImage: registry.GetDevfileIndexImage(devfileRegistry, r.defaults),
+		OCIRegistryImage:  registry.GetOCIRegistryImage(devfileRegistry, r.defaults),
+	}
+	if !registry.IsHeadlessEnabled(devfileRegistry) {
+		registryInfo.RegistryViewerImage = registry.GetRegistryViewerImage(devfileRegistry, r.defaults)
+	}
+	metrics.SetRegistryInfo(registryInfo)
+
+	meta.SetStatusCondition(&devfileRegistry.Status.Conditions, metav1.Condition{
+		Type:    typeUpdateDevfileRegistry,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Ready",
+		Message: "Devfile Registry deployed",
 	})
 
+	// The config map and htpasswd secret of the user are not watched, check them again for changes to roll out
+	nextConfigCheck := time.Duration(0)
+	if devfileRegistry.Spec.OciRegistry.Config != nil || registry.GetUserHtpasswdSecretName(devfileRegistry) != "" {
+		nextConfigCheck = userConfigCheckInterval
+	}
+
+	return ctrl.Result{RequeueAfter: earliestRequeue(nextBackup, nextSourceCheck, nextMirrorSync, nextConfigCheck)}, nil
+}
+
+// earliestRequeue returns the shortest of the given requeue durations, ignoring the zero ones
+func earliestRequeue(durations ...time.Duration) time.Duration {
+	earliest := time.Duration(0)
+	for _, d := range durations {
+		if d > 0 && (earliest == 0 || d < earliest) {
+			earliest = d
+		}
+	}
+	return earliest
+}
+
+// patchStatus writes the status of the DevfileRegistry changed during the reconcile, if it changed since the given
+// original. The patch fails on a conflict with a status written by the mirror syncer meanwhile, the reconcile being
+// retried with the latest status.
+func (r *DevfileRegistryReconciler) patchStatus(ctx context.Context, original *registryv1alpha1.DevfileRegistry, cr *registryv1alpha1.DevfileRegistry) error {
+	if equality.Semantic.DeepEqual(original.Status, cr.Status) {
+		return nil
+	}
+	err := r.Status().Patch(ctx, cr, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
 	if err != nil {
-		log.Error(err, "Failed to update DevfileRegistry status")
-		return ctrl.Result{}, err
+		r.Log.Error(err, "Failed to update DevfileRegistry status")
+	}
+	return err
+}
+
+// updateStorageResizeStatus reports why the registry volume cannot be resized, blocked being nil if nothing prevents it
+func (r *DevfileRegistryReconciler) updateStorageResizeStatus(cr *registryv1alpha1.DevfileRegistry, blocked *metav1.Condition) {
+	if blocked != nil {
+		blocked.Type = typeStorageResizeBlocked
+		blocked.Status = metav1.ConditionTrue
+		meta.SetStatusCondition(&cr.Status.Conditions, *blocked)
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typeStorageResizeBlocked)
 	}
+}
 
-	return ctrl.Result{}, nil
+// updateRegistryConfigStatus reports whether the OCI registry configuration referenced by the DevfileRegistry
+// is missing or invalid
+func (r *DevfileRegistryReconciler) updateRegistryConfigStatus(cr *registryv1alpha1.DevfileRegistry, invalid *metav1.Condition) {
+	if invalid != nil {
+		invalid.Type = typeRegistryConfigInvalid
+		invalid.Status = metav1.ConditionTrue
+		meta.SetStatusCondition(&cr.Status.Conditions, *invalid)
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typeRegistryConfigInvalid)
+	}
+}
+
+// updatePodTemplateOverrideStatus reports whether the pod template override of the DevfileRegistry cannot be
+// applied to the registry deployment
+func (r *DevfileRegistryReconciler) updatePodTemplateOverrideStatus(cr *registryv1alpha1.DevfileRegistry, invalid *metav1.Condition) {
+	if invalid != nil {
+		invalid.Type = typePodTemplateOverrideInvalid
+		invalid.Status = metav1.ConditionTrue
+		meta.SetStatusCondition(&cr.Status.Conditions, *invalid)
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typePodTemplateOverrideInvalid)
+	}
+}
+
+// updateOCIRegistryAuthStatus reports the secret holding the credentials of the OCI registry
+func (r *DevfileRegistryReconciler) updateOCIRegistryAuthStatus(cr *registryv1alpha1.DevfileRegistry) {
+	cr.Status.OCIRegistryAuthSecret = registry.GetOCIRegistryAuthSecretName(cr)
+}
+
+// updateGarbageCollectionStatus reports the last garbage collection of the OCI registry storage
+func (r *DevfileRegistryReconciler) updateGarbageCollectionStatus(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) error {
+	if registry.IsGarbageCollectionEnabled(cr) {
+		cronJob := &batchv1.CronJob{}
+		err := r.Get(ctx, types.NamespacedName{Name: registry.GarbageCollectionCronJobName(cr), Namespace: cr.Namespace}, cronJob)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// CronJob was just created and is not in the cache yet, it will be reported on the next reconcile
+				return nil
+			}
+			r.Log.Error(err, "Failed to get CronJob")
+			return err
+		}
+		cr.Status.LastGarbageCollectionTime = cronJob.Status.LastScheduleTime
+		cr.Status.LastGarbageCollectionResult = registry.GetGarbageCollectionResult(cronJob)
+	} else {
+		cr.Status.LastGarbageCollectionTime = nil
+		cr.Status.LastGarbageCollectionResult = ""
+	}
+	return nil
+}
+
+// updateReplicaStatus reports the replicas of the registry deployment, used by the scale subresource, and whether
+// the number of replicas had to be limited because of the storage access mode
+func (r *DevfileRegistryReconciler) updateReplicaStatus(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) error {
+	dep := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.DeploymentName(cr), Namespace: cr.Namespace}, dep)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Deployment was just created and is not in the cache yet, it will be reported on the next reconcile
+			return nil
+		}
+		r.Log.Error(err, "Failed to get Deployment")
+		return err
+	}
+
+	cr.Status.Replicas = dep.Status.Replicas
+	cr.Status.ReadyReplicas = dep.Status.ReadyReplicas
+	cr.Status.LabelSelector = metav1.FormatLabelSelector(dep.Spec.Selector)
+	if registry.IsReplicasLimitedByStorage(cr) {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    typeReplicasLimited,
+			Status:  metav1.ConditionTrue,
+			Reason:  "StorageNotShareable",
+			Message: "Persistent storage cannot be shared between nodes, only one replica is deployed",
+		})
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typeReplicasLimited)
+	}
+	return nil
 }
 
 func (r *DevfileRegistryReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -226,18 +497,61 @@ func (r *DevfileRegistryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err != nil {
 		return err
 	}
-	config.ControllerCfg.SetIsOpenShift(isOS)
+	config.SetIsOpenShift(isOS)
+
+	// Check if the Gateway API is installed, to expose registries with HTTPRoutes. The APIs are only discovered on
+	// startup, so a failed check stops the operator instead of disabling the feature until the next restart.
+	isGatewayAPI, err := cluster.IsGatewayAPIAvailable()
+	if err != nil {
+		r.Log.Error(err, "Failed to check if the Gateway API is installed")
+		return err
+	}
+	config.SetIsGatewayAPIAvailable(isGatewayAPI)
+
+	// Check if the Prometheus Operator is installed, to have it scrape the registry metrics
+	isMonitoringAPI, err := cluster.IsMonitoringAPIAvailable()
+	if err != nil {
+		r.Log.Error(err, "Failed to check if the Prometheus Operator is installed")
+		return err
+	}
+	config.SetIsMonitoringAPIAvailable(isMonitoringAPI)
+
+	// Copy the stacks of the mirrored devfile registries in the background, outside of the reconciles
+	r.mirrorSyncer = newMirrorSyncer(r)
+	if err := mgr.Add(r.mirrorSyncer); err != nil {
+		return err
+	}
 
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&registryv1alpha1.DevfileRegistry{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
-		Owns(&networkingv1.Ingress{})
-
-	// If on OpenShift, mark routes as owned by the controller
-	if config.ControllerCfg.IsOpenShift() {
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&batchv1.CronJob{}).
+		// Only the generated secrets are cached, see NewCacheOptions
+		Owns(&corev1.Secret{}).
+		// Only the generated config maps are cached, see NewCacheOptions
+		Owns(&corev1.ConfigMap{}).
+		Owns(&networkingv1.Ingress{}).
+		// Update every registry with the new defaults when the RegistryOperatorConfig changes
+		Watches(&registryv1alpha1.RegistryOperatorConfig{}, handler.EnqueueRequestsFromMapFunc(r.registriesForOperatorConfig)).
+		// Update the registry ingresses when the default IngressClass of the cluster changes
+		Watches(&networkingv1.IngressClass{}, handler.EnqueueRequestsFromMapFunc(r.registriesForIngressClass))
+
+	// If on OpenShift, mark routes as owned by the controller, and update the registries when the apps domain of the
+	// cluster changes
+	if config.IsOpenShift() {
 		builder.Owns(&routev1.Route{})
+		builder.Watches(&configv1.Ingress{}, handler.EnqueueRequestsFromMapFunc(r.registriesForClusterIngress))
+	}
+
+	if config.IsGatewayAPIAvailable() {
+		builder.Owns(&gatewayv1.HTTPRoute{})
+	}
+
+	if config.IsMonitoringAPIAvailable() {
+		builder.Owns(&monitoringv1.ServiceMonitor{})
 	}
 
 	return builder.Complete(r)
@@ -23,15 +23,19 @@ import (
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 	"github.com/devfile/registry-operator/pkg/registry"
 	routev1 "github.com/openshift/api/route/v1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func (r *DevfileRegistryReconciler) ensure(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, resource client.Object, labels map[string]string, ingressDomain string) (*reconcile.Result, error) {
@@ -41,36 +45,81 @@ func (r *DevfileRegistryReconciler) ensure(ctx context.Context, cr *registryv1al
 	// Check to see if the requested resource exists on the cluster. If it doesn't exist, create it and return.
 	err := r.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: cr.Namespace}, resource)
 	if err != nil && errors.IsNotFound(err) {
-		generatedResource := r.generateResourceObject(cr, resource, labels, ingressDomain)
+		generatedResource, err := r.generateResourceObject(ctx, cr, resource, labels, ingressDomain)
+		if err != nil {
+			r.Log.Error(err, "Failed to generate new "+resourceType)
+			return &ctrl.Result{}, err
+		}
 		r.Log.Info("Creating a new resource ", resourceType, resourceType+".Namespace", cr.Namespace+".Name", resourceName)
 		err = r.Create(ctx, generatedResource)
 		if err != nil {
 			r.Log.Error(err, "Failed to create new ", resourceType, resourceType+".Namespace", cr.Namespace, "Service.Name", cr.Namespace+".Name", resourceName)
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonCreateFailed, "Failed to create %s %s: %v", resourceType, resourceName, err)
 			return &ctrl.Result{}, err
 		}
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonCreated, "Created %s %s", resourceType, resourceName)
 		return nil, nil
 	} else if err != nil {
 		r.Log.Error(err, "Failed to get "+resourceType)
 		return &ctrl.Result{}, err
 	}
 
+	// The resource version changes when any of the updates below is applied
+	resourceVersion := resource.GetResourceVersion()
+
+	// Keep the user defined labels and annotations in sync, the selector labels are never changed
+	if registry.UpdateUserMetadata(resource, getUserLabels(resource, cr, labels), getUserAnnotations(resource, cr)) {
+		r.Log.Info("Updating the labels and annotations of "+resourceType, resourceType+".Namespace", cr.Namespace, resourceType+".Name", resourceName)
+		err = r.Update(ctx, resource)
+		if err != nil {
+			r.Log.Error(err, "Failed to update "+resourceType)
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonUpdateFailed, "Failed to update %s %s: %v", resourceType, resourceName, err)
+			return &ctrl.Result{}, err
+		}
+	}
+
 	// Update the given resource, if needed
-	// At this moment, only registry deployments, routes and ingresses need to be updated.
+	// At this moment, only registry deployments, config maps, secrets, persistent volume claims, services, cron jobs, routes, ingresses, HTTPRoutes and ServiceMonitors need to be updated.
 	switch resource.(type) {
 	case *appsv1.Deployment:
 		dep, _ := resource.(*appsv1.Deployment)
 		err = r.updateDeployment(ctx, cr, dep)
+	case *corev1.ConfigMap:
+		cm, _ := resource.(*corev1.ConfigMap)
+		err = r.updateConfigMap(ctx, cr, cm)
+	case *corev1.Secret:
+		secret, _ := resource.(*corev1.Secret)
+		err = r.updateOCIRegistryAuthSecret(ctx, cr, secret)
+	case *corev1.PersistentVolumeClaim:
+		pvc, _ := resource.(*corev1.PersistentVolumeClaim)
+		err = r.updatePVC(ctx, cr, pvc)
+	case *corev1.Service:
+		svc, _ := resource.(*corev1.Service)
+		err = r.updateService(ctx, cr, svc)
+	case *batchv1.CronJob:
+		cronJob, _ := resource.(*batchv1.CronJob)
+		err = r.updateGarbageCollectionCronJob(ctx, cr, cronJob)
 	case *routev1.Route:
 		route, _ := resource.(*routev1.Route)
 		err = r.updateRoute(ctx, cr, route)
 	case *networkingv1.Ingress:
 		ingress, _ := resource.(*networkingv1.Ingress)
 		err = r.updateIngress(ctx, cr, ingressDomain, ingress)
+	case *gatewayv1.HTTPRoute:
+		httpRoute, _ := resource.(*gatewayv1.HTTPRoute)
+		err = r.updateHTTPRoute(ctx, cr, httpRoute)
+	case *monitoringv1.ServiceMonitor:
+		serviceMonitor, _ := resource.(*monitoringv1.ServiceMonitor)
+		err = r.updateServiceMonitor(ctx, cr, serviceMonitor)
 	}
 	if err != nil {
 		r.Log.Error(err, "Failed to update "+resourceType)
+		r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonUpdateFailed, "Failed to update %s %s: %v", resourceType, resourceName, err)
 		return &ctrl.Result{}, err
 	}
+	if resource.GetResourceVersion() != resourceVersion {
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonUpdated, "Updated %s %s", resourceType, resourceName)
+	}
 	return nil, nil
 }
 
@@ -84,26 +133,94 @@ func getResourceName(resource runtime.Object, cr *registryv1alpha1.DevfileRegist
 		return registry.PVCName(cr)
 	case *corev1.Service:
 		return registry.ServiceName(cr)
-	case *routev1.Route, *networkingv1.Ingress:
+	case *policyv1.PodDisruptionBudget:
+		return registry.PodDisruptionBudgetName(cr)
+	case *corev1.Secret:
+		return registry.OCIRegistryAuthSecretName(cr)
+	case *batchv1.CronJob:
+		return registry.GarbageCollectionCronJobName(cr)
+	case *routev1.Route, *networkingv1.Ingress, *gatewayv1.HTTPRoute:
 		return registry.IngressName(cr)
+	case *monitoringv1.ServiceMonitor:
+		return registry.ServiceMonitorName(cr)
 	}
 	return registry.GenericResourceName(cr)
 }
 
-func (r *DevfileRegistryReconciler) generateResourceObject(cr *registryv1alpha1.DevfileRegistry, resource client.Object, labels map[string]string, ingressDomain string) client.Object {
+// getUserLabels returns the user defined labels of the given resource
+func getUserLabels(resource runtime.Object, cr *registryv1alpha1.DevfileRegistry, labels map[string]string) map[string]string {
+	switch resource.(type) {
+	case *monitoringv1.ServiceMonitor:
+		return registry.GetServiceMonitorLabels(cr, labels)
+	}
+	return registry.GetUserLabels(cr, labels)
+}
+
+// getUserAnnotations returns the user defined annotations of the given resource
+func getUserAnnotations(resource runtime.Object, cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	switch resource.(type) {
+	case *appsv1.Deployment:
+		return registry.GetDeploymentAnnotations(cr)
+	case *corev1.ConfigMap:
+		return registry.GetConfigMapAnnotations(cr)
+	case *corev1.PersistentVolumeClaim:
+		return registry.GetPVCAnnotations(cr)
+	case *corev1.Service:
+		return registry.GetServiceAnnotations(cr)
+	case *routev1.Route:
+		return registry.GetRouteAnnotations(cr)
+	case *networkingv1.Ingress:
+		return registry.GetIngressAnnotations(cr)
+	}
+	return registry.GetCommonAnnotations(cr)
+}
+
+func (r *DevfileRegistryReconciler) generateResourceObject(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, resource client.Object, labels map[string]string, ingressDomain string) (client.Object, error) {
 	switch resource.(type) {
 	case *appsv1.Deployment:
-		return registry.GenerateDeployment(cr, r.Scheme, labels)
+		registryConfig, err := r.getValidRegistryConfig(ctx, cr)
+		if err != nil {
+			return nil, err
+		}
+		dep, err := registry.GenerateDeployment(cr, r.defaults, r.Scheme, labels)
+		if err != nil {
+			return nil, err
+		}
+		registry.SetRegistryConfigHash(&dep.Spec.Template, registryConfig)
+		htpasswd, err := r.getOCIRegistryHtpasswd(ctx, cr)
+		if err != nil {
+			return nil, err
+		}
+		registry.SetOCIRegistryHtpasswdHash(&dep.Spec.Template, htpasswd)
+		return dep, nil
 	case *corev1.ConfigMap:
-		return registry.GenerateRegistryConfigMap(cr, r.Scheme, labels)
+		registryConfig, err := r.getValidRegistryConfig(ctx, cr)
+		if err != nil {
+			return nil, err
+		}
+		return registry.GenerateRegistryConfigMap(cr, registryConfig, r.Scheme, labels), nil
 	case *corev1.PersistentVolumeClaim:
 		return registry.GeneratePVC(cr, r.Scheme, labels)
 	case *corev1.Service:
-		return registry.GenerateService(cr, r.Scheme, labels)
+		return registry.GenerateService(cr, r.Scheme, labels), nil
+	case *policyv1.PodDisruptionBudget:
+		return registry.GeneratePodDisruptionBudget(cr, r.Scheme, labels), nil
+	case *corev1.Secret:
+		userHtpasswd, err := r.getUserHtpasswd(ctx, cr)
+		if err != nil {
+			return nil, err
+		}
+		return registry.GenerateOCIRegistryAuthSecret(cr, userHtpasswd, r.Scheme, labels), nil
+	case *batchv1.CronJob:
+		return registry.GenerateGarbageCollectionCronJob(cr, r.defaults, r.Scheme, labels), nil
 	case *routev1.Route:
-		return registry.GenerateRoute(cr, r.Scheme, labels)
+		return registry.GenerateRoute(cr, r.defaults, r.Scheme, labels), nil
 	case *networkingv1.Ingress:
-		return registry.GenerateIngress(cr, ingressDomain, r.Scheme, labels)
+		return registry.GenerateIngress(cr, r.defaults, ingressDomain, r.Scheme, labels), nil
+	case *gatewayv1.HTTPRoute:
+		return registry.GenerateHTTPRoute(cr, r.defaults, r.Scheme, labels), nil
+	case *monitoringv1.ServiceMonitor:
+		return registry.GenerateServiceMonitor(cr, r.Scheme, labels), nil
 	}
-	return nil
+	return nil, nil
 }
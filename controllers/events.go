@@ -0,0 +1,55 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"slices"
+
+	"github.com/devfile/registry-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the events emitted by the reconcilers
+const (
+	eventReasonCreated             = "Created"
+	eventReasonCreateFailed        = "CreateFailed"
+	eventReasonUpdated             = "Updated"
+	eventReasonUpdateFailed        = "UpdateFailed"
+	eventReasonDeploymentBlocked   = "DeploymentBlocked"
+	eventReasonPVCDeleted          = "PersistentVolumeClaimDeleted"
+	eventReasonReadinessTimeout    = "ReadinessTimeout"
+	eventReasonRegistryReachable   = "RegistryReachable"
+	eventReasonRegistryUnreachable = "RegistryUnreachable"
+)
+
+// recordRegistryReachabilityEvents emits an event for each registry of the list that became reachable or unreachable
+// since the previous validation. previous holds the URLs of the registries unreachable by the previous validation, and
+// unreachable the ones of the registries unreachable now.
+func recordRegistryReachabilityEvents(recorder record.EventRecorder, list client.Object, previous []string, devfileRegistries []v1alpha1.DevfileRegistryService, unreachable map[string]bool) {
+	for _, devfileRegistry := range devfileRegistries {
+		wasUnreachable := slices.Contains(previous, devfileRegistry.URL)
+		if unreachable[devfileRegistry.URL] && !wasUnreachable {
+			recorder.Eventf(list, corev1.EventTypeWarning, eventReasonRegistryUnreachable,
+				"Devfile registry %s cannot be reached at %s", devfileRegistry.Name, devfileRegistry.URL)
+		} else if !unreachable[devfileRegistry.URL] && wasUnreachable {
+			recorder.Eventf(list, corev1.EventTypeNormal, eventReasonRegistryReachable,
+				"Devfile registry %s is reachable again at %s", devfileRegistry.Name, devfileRegistry.URL)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/devfile/registry-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordRegistryReachabilityEvents(t *testing.T) {
+	devfileRegistries := []v1alpha1.DevfileRegistryService{
+		{Name: "community", URL: "https://registry.devfile.io"},
+		{Name: "staging", URL: "https://registry.stage.devfile.io"},
+	}
+	list := &v1alpha1.DevfileRegistriesList{ObjectMeta: metav1.ObjectMeta{Name: "test-list", Namespace: "test-namespace"}}
+
+	tests := []struct {
+		name        string
+		previous    []string
+		unreachable map[string]bool
+		wantEvents  []string
+	}{
+		{
+			name:        "Case 1: First validation with an unreachable registry",
+			unreachable: map[string]bool{"https://registry.stage.devfile.io": true},
+			wantEvents:  []string{"Warning RegistryUnreachable Devfile registry staging cannot be reached at https://registry.stage.devfile.io"},
+		},
+		{
+			name:        "Case 2: Registries still reachable",
+			previous:    []string{},
+			unreachable: map[string]bool{},
+		},
+		{
+			name:        "Case 3: Registry still unreachable",
+			previous:    []string{"https://registry.stage.devfile.io"},
+			unreachable: map[string]bool{"https://registry.stage.devfile.io": true},
+		},
+		{
+			name:        "Case 4: Registries changing between reachable and unreachable",
+			previous:    []string{"https://registry.stage.devfile.io"},
+			unreachable: map[string]bool{"https://registry.devfile.io": true},
+			wantEvents: []string{
+				"Warning RegistryUnreachable Devfile registry community cannot be reached at https://registry.devfile.io",
+				"Normal RegistryReachable Devfile registry staging is reachable again at https://registry.stage.devfile.io",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			recordRegistryReachabilityEvents(recorder, list, tt.previous, devfileRegistries, tt.unreachable)
+			close(recorder.Events)
+
+			var got []string
+			for event := range recorder.Events {
+				got = append(got, event)
+			}
+			if len(got) != len(tt.wantEvents) {
+				t.Fatalf("recordRegistryReachabilityEvents() events = %v, want %v", got, tt.wantEvents)
+			}
+			for i := range got {
+				if got[i] != tt.wantEvents[i] {
+					t.Errorf("recordRegistryReachabilityEvents() events = %v, want %v", got, tt.wantEvents)
+				}
+			}
+		})
+	}
+}
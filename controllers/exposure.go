@@ -0,0 +1,422 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/metrics"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// loadBalancerRequeueInterval is how long to wait before checking again if an address is assigned to the LoadBalancer
+// service of a registry
+const loadBalancerRequeueInterval = 10 * time.Second
+
+// getExposureBlockedMessage returns why the registry cannot be exposed with its exposure type, or "" if it can
+func getExposureBlockedMessage(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) string {
+	switch registry.GetExposureType(cr) {
+	case registry.ExposureTypeGateway:
+		if !config.IsGatewayAPIAvailable() {
+			return "The Gateway API was not installed on the cluster when the operator started - Deployment Blocked"
+		}
+		if registry.GetGatewayHostname(cr, defaults) == "" {
+			return "No Gateway hostname or Ingress domain set for Devfile Registry or the RegistryOperatorConfig - Deployment Blocked"
+		}
+	case registry.ExposureTypeRoute:
+		if !config.IsOpenShift() {
+			return "Routes are only available on OpenShift - Deployment Blocked"
+		}
+	case registry.ExposureTypeIngress:
+		if registry.IsIngressSkipped(cr, defaults) {
+			return "No Ingress domain set for Devfile Registry or the RegistryOperatorConfig - Deployment Blocked"
+		}
+	}
+	return ""
+}
+
+// updateGatewayStatus reports when the registry is exposed through a Gateway on a cluster without the Gateway API.
+// The API is only discovered when the operator starts, so the operator must be restarted once it is installed.
+func (r *DevfileRegistryReconciler) updateGatewayStatus(cr *registryv1alpha1.DevfileRegistry) {
+	if registry.GetExposureType(cr) == registry.ExposureTypeGateway && !config.IsGatewayAPIAvailable() {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    typeGatewayUnavailable,
+			Status:  metav1.ConditionTrue,
+			Reason:  "GatewayAPINotInstalled",
+			Message: "The Gateway API was not installed on the cluster when the operator started, the registry is not exposed until the operator is restarted",
+		})
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typeGatewayUnavailable)
+	}
+}
+
+// reconcileExposure creates or updates the Route, Ingress or HTTPRoute exposing the devfile registry, and deletes
+// the ones of the other exposure types. Returns the URL of the registry, or a result if the reconcile must stop.
+func (r *DevfileRegistryReconciler) reconcileExposure(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, labels map[string]string) (string, *ctrl.Result, error) {
+	var hostname string
+	switch registry.GetExposureType(cr) {
+	case registry.ExposureTypeGateway:
+		// Check if the HTTPRoute attaching the devfile index to the Gateway exists
+		result, err := r.ensure(ctx, cr, &gatewayv1.HTTPRoute{}, labels, "")
+		if result != nil {
+			return "", result, err
+		}
+		if err = r.deleteOldExposureIfNeeded(ctx, cr, &gatewayv1.HTTPRoute{}); err != nil {
+			return "", &ctrl.Result{}, err
+		}
+
+		// Get the hostname of the generated HTTPRoute
+		httpRoute := &gatewayv1.HTTPRoute{}
+		err = r.Get(ctx, types.NamespacedName{Name: registry.IngressName(cr), Namespace: cr.Namespace}, httpRoute)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				r.Log.Error(err, "Failed to get HTTPRoute")
+				return "", &ctrl.Result{}, err
+			}
+			// Requeue, as the controller's cached kube client likely hasn't registered the new HTTPRoute yet
+			r.Log.Info("Waiting for the new HTTPRoute to be cached", "HTTPRoute.Name", registry.IngressName(cr))
+			return "", &ctrl.Result{Requeue: true}, nil
+		}
+		if len(httpRoute.Spec.Hostnames) == 0 {
+			// The hostnames were removed from the HTTPRoute behind the operator, e.g. by a policy of the cluster.
+			// Requeueing would not bring them back, the HTTPRoute is reconciled again when it changes.
+			r.blockHTTPRouteWithoutHostname(cr, httpRoute.Name)
+			return "", &ctrl.Result{}, nil
+		}
+		hostname = string(httpRoute.Spec.Hostnames[0])
+	case registry.ExposureTypeRoute:
+		// Check if the route exposing the devfile index exists
+		result, err := r.ensure(ctx, cr, &routev1.Route{}, labels, "")
+		if result != nil {
+			return "", result, err
+		}
+		if err = r.deleteOldExposureIfNeeded(ctx, cr, &routev1.Route{}); err != nil {
+			return "", &ctrl.Result{}, err
+		}
+
+		// Get the hostname of the generated devfile route
+		devfilesRoute := &routev1.Route{}
+		err = r.Get(ctx, types.NamespacedName{Name: registry.IngressName(cr), Namespace: cr.Namespace}, devfilesRoute)
+		if err != nil {
+			// Log an error, but requeue, as the controller's cached kube client likely hasn't registered the new route yet.
+			// See https://github.com/operator-framework/operator-sdk/issues/4013#issuecomment-707267616 for an explanation on why we requeue rather than error out here
+			r.Log.Error(err, "Failed to get Route")
+			return "", &ctrl.Result{Requeue: true}, nil
+		}
+		hostname = devfilesRoute.Spec.Host
+	case registry.ExposureTypeIngress:
+		// Create/update the ingress for the devfile registry
+		hostname = registry.GetDevfileRegistryIngress(cr, r.defaults)
+		result, err := r.ensure(ctx, cr, &networkingv1.Ingress{}, labels, hostname)
+		if result != nil {
+			return "", result, err
+		}
+		if err = r.deleteOldExposureIfNeeded(ctx, cr, &networkingv1.Ingress{}); err != nil {
+			return "", &ctrl.Result{}, err
+		}
+	default:
+		// The registry is exposed with its service only
+		if err := r.deleteOldExposureIfNeeded(ctx, cr, nil); err != nil {
+			return "", &ctrl.Result{}, err
+		}
+		if registry.GetExposureType(cr) != registry.ExposureTypeLoadBalancer {
+			return registry.GetServiceURL(cr, registry.DevfileIndexPort), nil, nil
+		}
+
+		svc := &corev1.Service{}
+		err := r.Get(ctx, types.NamespacedName{Name: registry.ServiceName(cr), Namespace: cr.Namespace}, svc)
+		if err != nil {
+			r.Log.Error(err, "Failed to get Service")
+			return "", &ctrl.Result{Requeue: true}, nil
+		}
+		loadBalancerURL := registry.GetLoadBalancerURL(svc, registry.DevfileIndexPort)
+		if loadBalancerURL == "" {
+			r.Log.Info("Waiting for an address to be assigned to the LoadBalancer service", "Service.Name", svc.Name)
+			return "", &ctrl.Result{RequeueAfter: loadBalancerRequeueInterval}, nil
+		}
+		return loadBalancerURL, nil, nil
+	}
+
+	if registry.IsTLSEnabled(cr, r.defaults) {
+		return "https://" + hostname, nil, nil
+	}
+	return "http://" + hostname, nil, nil
+}
+
+// updateHTTPRoute checks to see if the spec of an existing HTTPRoute needs to be updated
+func (r *DevfileRegistryReconciler) updateHTTPRoute(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, httpRoute *gatewayv1.HTTPRoute) error {
+	spec := registry.GetHTTPRouteSpec(cr, r.defaults)
+	if equality.Semantic.DeepEqual(httpRoute.Spec, spec) {
+		return nil
+	}
+	httpRoute.Spec = spec
+	return r.Update(ctx, httpRoute)
+}
+
+// blockHTTPRouteWithoutHostname reports that the registry cannot be reached, as the given HTTPRoute exposing it
+// has no hostname
+func (r *DevfileRegistryReconciler) blockHTTPRouteWithoutHostname(cr *registryv1alpha1.DevfileRegistry, name string) {
+	message := fmt.Sprintf("HTTPRoute %s has no hostname - Deployment Blocked", name)
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:    typeNoDeployDevfileRegistry,
+		Status:  metav1.ConditionUnknown,
+		Reason:  "DeploymentBlocked",
+		Message: message,
+	})
+	r.Log.Info("Blocked deployment", "reason", message)
+	r.Recorder.Event(cr, corev1.EventTypeWarning, eventReasonDeploymentBlocked, message)
+	metrics.IncDeploymentBlocked(cr.Namespace, cr.Name, metrics.BlockedReasonExposure)
+}
+
+// reconcileOCIRegistryExposure creates or updates the Route or Ingress exposing the OCI registry if enabled, and
+// deletes it otherwise. Returns the URL of the OCI registry, or a result if the reconcile must stop.
+func (r *DevfileRegistryReconciler) reconcileOCIRegistryExposure(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, labels map[string]string) (string, *ctrl.Result, error) {
+	if !registry.IsOCIRegistryExposed(cr) {
+		if err := r.deleteUnusedExposures(ctx, cr, registry.OCIRegistryIngressName(cr), nil); err != nil {
+			return "", &ctrl.Result{}, err
+		}
+		// The OCI registry is never published on an external registry service, only inside of the cluster
+		return registry.GetServiceURL(cr, registry.OCIServerPort), nil, nil
+	}
+
+	var inUse client.Object
+	var hostname string
+	if registry.GetExposureType(cr) == registry.ExposureTypeRoute {
+		route, err := r.applyOCIRegistryRoute(ctx, cr, labels)
+		if err != nil {
+			return "", &ctrl.Result{}, err
+		}
+		if route == nil {
+			// Requeue, as the host of the new route is only known once it is admitted
+			return "", &ctrl.Result{Requeue: true}, nil
+		}
+		inUse = route
+		hostname = route.Spec.Host
+	} else {
+		hostname = registry.GetOCIRegistryHostname(cr, r.defaults)
+		ingress, err := r.applyOCIRegistryIngress(ctx, cr, hostname, labels)
+		if err != nil {
+			return "", &ctrl.Result{}, err
+		}
+		inUse = ingress
+	}
+	if err := r.deleteUnusedExposures(ctx, cr, registry.OCIRegistryIngressName(cr), inUse); err != nil {
+		return "", &ctrl.Result{}, err
+	}
+
+	if registry.IsTLSEnabled(cr, r.defaults) {
+		return "https://" + hostname, nil, nil
+	}
+	return "http://" + hostname, nil, nil
+}
+
+// reconcileInternalService creates or updates the internal service of the OCI registry and metrics ports when the
+// registry service is reachable from outside of the cluster, and deletes it otherwise
+func (r *DevfileRegistryReconciler) reconcileInternalService(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, labels map[string]string) error {
+	svc := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.InternalServiceName(cr), Namespace: cr.Namespace}, svc)
+	if err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "Failed to get the internal Service")
+		return err
+	}
+
+	if !registry.IsServiceExternal(cr) {
+		// Leave the services not generated for the registry alone
+		if errors.IsNotFound(err) || !metav1.IsControlledBy(svc, cr) {
+			return nil
+		}
+		r.Log.Info("Deleting the internal Service as the registry service is not exposed", "Service.Name", svc.Name)
+		if err = r.Delete(ctx, svc); err != nil && !errors.IsNotFound(err) {
+			r.Log.Error(err, "Failed to delete the internal Service")
+			return err
+		}
+		return nil
+	}
+
+	if errors.IsNotFound(err) {
+		svc = registry.GenerateInternalService(cr, r.Scheme, labels)
+		r.Log.Info("Creating the internal Service", "Service.Name", svc.Name)
+		if err = r.Create(ctx, svc); err != nil {
+			r.Log.Error(err, "Failed to create the internal Service")
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonCreateFailed, "Failed to create Service %s: %v", svc.Name, err)
+			return err
+		}
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonCreated, "Created Service %s", svc.Name)
+		return nil
+	}
+
+	needsUpdating := registry.UpdateUserMetadata(svc, registry.GetUserLabels(cr, labels), nil)
+	if ports := registry.GetInternalServicePorts(); !servicePortsEqual(svc.Spec.Ports, ports) {
+		svc.Spec.Ports = ports
+		needsUpdating = true
+	}
+	if needsUpdating {
+		if err = r.Update(ctx, svc); err != nil {
+			r.Log.Error(err, "Failed to update the internal Service")
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonUpdateFailed, "Failed to update Service %s: %v", svc.Name, err)
+			return err
+		}
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonUpdated, "Updated Service %s", svc.Name)
+	}
+	return nil
+}
+
+// applyOCIRegistryRoute creates the Route exposing the OCI registry, or updates the existing one. Returns nil if
+// the Route was just created.
+func (r *DevfileRegistryReconciler) applyOCIRegistryRoute(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, labels map[string]string) (*routev1.Route, error) {
+	generated := registry.GenerateOCIRegistryRoute(cr, r.defaults, r.Scheme, labels)
+	route := &routev1.Route{}
+	err := r.Get(ctx, types.NamespacedName{Name: generated.Name, Namespace: cr.Namespace}, route)
+	if errors.IsNotFound(err) {
+		r.Log.Info("Creating the OCI registry Route", "Route.Name", generated.Name)
+		if err = r.Create(ctx, generated); err != nil {
+			r.Log.Error(err, "Failed to create the OCI registry Route")
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonCreateFailed, "Failed to create Route %s: %v", generated.Name, err)
+			return nil, err
+		}
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonCreated, "Created Route %s", generated.Name)
+		return nil, nil
+	}
+	if err != nil {
+		r.Log.Error(err, "Failed to get the OCI registry Route")
+		return nil, err
+	}
+
+	needsUpdating := registry.UpdateUserMetadata(route, registry.GetUserLabels(cr, labels), registry.GetOCIRegistryRouteAnnotations(cr))
+	// The host generated by OpenShift is kept when no ingress domain is known
+	if generated.Spec.Host != "" && route.Spec.Host != generated.Spec.Host {
+		route.Spec.Host = generated.Spec.Host
+		needsUpdating = true
+	}
+	if !equality.Semantic.DeepEqual(route.Spec.TLS, generated.Spec.TLS) {
+		route.Spec.TLS = generated.Spec.TLS
+		needsUpdating = true
+	}
+	if needsUpdating {
+		if err = r.Update(ctx, route); err != nil {
+			r.Log.Error(err, "Failed to update the OCI registry Route")
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonUpdateFailed, "Failed to update Route %s: %v", route.Name, err)
+			return nil, err
+		}
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonUpdated, "Updated Route %s", route.Name)
+	}
+	return route, nil
+}
+
+// applyOCIRegistryIngress creates the Ingress exposing the OCI registry on the given host, or updates the existing one
+func (r *DevfileRegistryReconciler) applyOCIRegistryIngress(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, hostname string, labels map[string]string) (*networkingv1.Ingress, error) {
+	ingress := &networkingv1.Ingress{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.OCIRegistryIngressName(cr), Namespace: cr.Namespace}, ingress)
+	if errors.IsNotFound(err) {
+		ingress = registry.GenerateOCIRegistryIngress(cr, r.defaults, hostname, r.Scheme, labels)
+		r.Log.Info("Creating the OCI registry Ingress", "Ingress.Name", ingress.Name)
+		if err = r.Create(ctx, ingress); err != nil {
+			r.Log.Error(err, "Failed to create the OCI registry Ingress")
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonCreateFailed, "Failed to create Ingress %s: %v", ingress.Name, err)
+			return nil, err
+		}
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonCreated, "Created Ingress %s", ingress.Name)
+		return ingress, nil
+	}
+	if err != nil {
+		r.Log.Error(err, "Failed to get the OCI registry Ingress")
+		return nil, err
+	}
+
+	needsUpdating := registry.UpdateUserMetadata(ingress, registry.GetUserLabels(cr, labels), registry.GetOCIRegistryIngressAnnotations(cr))
+	if spec := registry.GetOCIRegistryIngressSpec(cr, r.defaults, hostname); !equality.Semantic.DeepEqual(ingress.Spec, spec) {
+		ingress.Spec = spec
+		needsUpdating = true
+	}
+	if needsUpdating {
+		if err = r.Update(ctx, ingress); err != nil {
+			r.Log.Error(err, "Failed to update the OCI registry Ingress")
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, eventReasonUpdateFailed, "Failed to update Ingress %s: %v", ingress.Name, err)
+			return nil, err
+		}
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonUpdated, "Updated Ingress %s", ingress.Name)
+	}
+	return ingress, nil
+}
+
+// updateEndpointStatus reports the URLs of the OCI registry and the registry viewer
+func (r *DevfileRegistryReconciler) updateEndpointStatus(cr *registryv1alpha1.DevfileRegistry, ociRegistryURL string) {
+	cr.Status.OCIRegistryURL = ociRegistryURL
+	cr.Status.RegistryViewerURL = registry.GetRegistryViewerURL(cr, cr.Status.URL)
+}
+
+// deleteOldExposureIfNeeded deletes the Ingress, Route or HTTPRoute previously generated for the registry
+// when it is now exposed with a resource of another kind
+func (r *DevfileRegistryReconciler) deleteOldExposureIfNeeded(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, inUse client.Object) error {
+	return r.deleteUnusedExposures(ctx, cr, registry.IngressName(cr), inUse)
+}
+
+// deleteUnusedExposures deletes the Ingress, Route and HTTPRoute with the given name generated for the registry,
+// except the one of the same kind as inUse
+func (r *DevfileRegistryReconciler) deleteUnusedExposures(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, name string, inUse client.Object) error {
+	exposures := []client.Object{&networkingv1.Ingress{}}
+	if config.IsOpenShift() {
+		exposures = append(exposures, &routev1.Route{})
+	}
+	if config.IsGatewayAPIAvailable() {
+		exposures = append(exposures, &gatewayv1.HTTPRoute{})
+	}
+
+	for _, exposure := range exposures {
+		if reflect.TypeOf(exposure) == reflect.TypeOf(inUse) {
+			continue
+		}
+		resourceType := reflect.TypeOf(exposure).Elem().Name()
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, exposure)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			r.Log.Error(err, "Error getting "+resourceType)
+			return err
+		}
+		// Leave the resources not generated for the registry alone
+		if !metav1.IsControlledBy(exposure, cr) {
+			continue
+		}
+
+		r.Log.Info("Old " + resourceType + " " + exposure.GetName() + " found. Deleting it as the registry is exposed differently.")
+		err = r.Delete(ctx, exposure)
+		if err != nil {
+			r.Log.Error(err, "Error deleting "+resourceType, "name", exposure.GetName())
+			return err
+		}
+	}
+	return nil
+}
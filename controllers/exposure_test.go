@@ -0,0 +1,490 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGetExposureBlockedMessage(t *testing.T) {
+	defer config.SetIsGatewayAPIAvailable(false)
+
+	gatewayRegistry := func(hostname string) *registryv1alpha1.DevfileRegistry {
+		return &registryv1alpha1.DevfileRegistry{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+			Spec: registryv1alpha1.DevfileRegistrySpec{
+				Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+					Gateway: &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway", Hostname: hostname},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name                string
+		cr                  *registryv1alpha1.DevfileRegistry
+		gatewayAPIAvailable bool
+		wantBlocked         bool
+	}{
+		{
+			name:                "Case 1: Gateway with a hostname",
+			cr:                  gatewayRegistry("registry.example.com"),
+			gatewayAPIAvailable: true,
+			wantBlocked:         false,
+		},
+		{
+			name:                "Case 2: Gateway API not installed",
+			cr:                  gatewayRegistry("registry.example.com"),
+			gatewayAPIAvailable: false,
+			wantBlocked:         true,
+		},
+		{
+			name:                "Case 3: Gateway without a hostname nor an ingress domain",
+			cr:                  gatewayRegistry(""),
+			gatewayAPIAvailable: true,
+			wantBlocked:         true,
+		},
+		{
+			name: "Case 4: Ingress without an ingress domain",
+			cr: &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+			},
+			wantBlocked: true,
+		},
+		{
+			name: "Case 5: Route on Kubernetes",
+			cr: &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: registry.ExposureTypeRoute},
+				},
+			},
+			wantBlocked: true,
+		},
+		{
+			name: "Case 6: ClusterIP without an ingress domain",
+			cr: &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: registry.ExposureTypeClusterIP},
+				},
+			},
+			wantBlocked: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetIsGatewayAPIAvailable(tt.gatewayAPIAvailable)
+			if got := getExposureBlockedMessage(tt.cr, config.Defaults{}); (got != "") != tt.wantBlocked {
+				t.Errorf("getExposureBlockedMessage() = %q, want blocked %v", got, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestUpdateGatewayStatus(t *testing.T) {
+	scheme := newTestScheme()
+	defer config.SetIsGatewayAPIAvailable(false)
+
+	tests := []struct {
+		name          string
+		gateway       *registryv1alpha1.DevfileRegistrySpecGateway
+		apiAvailable  bool
+		wantCondition bool
+	}{
+		{
+			name:          "Case 1: Gateway API not installed",
+			gateway:       &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway"},
+			apiAvailable:  false,
+			wantCondition: true,
+		},
+		{
+			name:          "Case 2: Gateway API installed",
+			gateway:       &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway"},
+			apiAvailable:  true,
+			wantCondition: false,
+		},
+		{
+			name:          "Case 3: Registry not exposed through a Gateway",
+			apiAvailable:  false,
+			wantCondition: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetIsGatewayAPIAvailable(tt.apiAvailable)
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Gateway: tt.gateway},
+				},
+			}
+			r := newTestReconciler(scheme, cr)
+
+			r.updateGatewayStatus(cr)
+			if got := meta.IsStatusConditionTrue(cr.Status.Conditions, typeGatewayUnavailable); got != tt.wantCondition {
+				t.Errorf("updateGatewayStatus() condition = %v, want %v", got, tt.wantCondition)
+			}
+		})
+	}
+}
+
+func TestUpdateHTTPRoute(t *testing.T) {
+	scheme := newTestScheme()
+
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+				Gateway: &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway", Hostname: "registry.example.com"},
+			},
+		},
+	}
+	httpRoute := registry.GenerateHTTPRoute(cr, config.Defaults{}, scheme, nil)
+	r := newTestReconciler(scheme, httpRoute)
+
+	cr.Spec.Exposure.Gateway.Hostname = "new.example.com"
+	if err := r.updateHTTPRoute(context.TODO(), cr, httpRoute); err != nil {
+		t.Fatalf("updateHTTPRoute() unexpected error: %v", err)
+	}
+
+	updated := &gatewayv1.HTTPRoute{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: httpRoute.Name, Namespace: httpRoute.Namespace}, updated); err != nil {
+		t.Fatalf("updateHTTPRoute() failed to get the HTTPRoute: %v", err)
+	}
+	if len(updated.Spec.Hostnames) != 1 || updated.Spec.Hostnames[0] != "new.example.com" {
+		t.Errorf("updateHTTPRoute() hostnames = %v, want [new.example.com]", updated.Spec.Hostnames)
+	}
+}
+
+func TestDeleteOldExposureIfNeeded(t *testing.T) {
+	scheme := newTestScheme()
+	config.SetIsGatewayAPIAvailable(true)
+	defer config.SetIsGatewayAPIAvailable(false)
+
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", UID: "test-uid"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			K8s: registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressDomain: "my-domain"},
+			Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+				Gateway: &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway"},
+			},
+		},
+	}
+	name := types.NamespacedName{Name: registry.IngressName(cr), Namespace: cr.Namespace}
+
+	tests := []struct {
+		name        string
+		ingress     *networkingv1.Ingress
+		wantDeleted bool
+	}{
+		{
+			name:        "Case 1: Ingress generated for the registry deleted",
+			ingress:     registry.GenerateIngress(cr, config.Defaults{}, registry.GetDevfileRegistryIngress(cr, config.Defaults{}), scheme, nil),
+			wantDeleted: true,
+		},
+		{
+			name:        "Case 2: Ingress not generated for the registry kept",
+			ingress:     &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}},
+			wantDeleted: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(scheme, tt.ingress, registry.GenerateHTTPRoute(cr, config.Defaults{}, scheme, nil))
+
+			if err := r.deleteOldExposureIfNeeded(context.TODO(), cr, &gatewayv1.HTTPRoute{}); err != nil {
+				t.Fatalf("deleteOldExposureIfNeeded() unexpected error: %v", err)
+			}
+			err := r.Get(context.TODO(), name, &networkingv1.Ingress{})
+			if deleted := errors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Errorf("deleteOldExposureIfNeeded() ingress deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if err := r.Get(context.TODO(), name, &gatewayv1.HTTPRoute{}); err != nil {
+				t.Errorf("deleteOldExposureIfNeeded() HTTPRoute in use deleted: %v", err)
+			}
+		})
+	}
+}
+
+func TestReconcileServiceExposure(t *testing.T) {
+	scheme := newTestScheme()
+
+	newRegistry := func(exposureType string) *registryv1alpha1.DevfileRegistry {
+		return &registryv1alpha1.DevfileRegistry{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", UID: "test-uid"},
+			Spec: registryv1alpha1.DevfileRegistrySpec{
+				K8s:      registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressDomain: "my-domain"},
+				Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: exposureType},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		cr          *registryv1alpha1.DevfileRegistry
+		lbIngress   []corev1.LoadBalancerIngress
+		wantURL     string
+		wantRequeue bool
+	}{
+		{
+			name:    "Case 1: ClusterIP",
+			cr:      newRegistry(registry.ExposureTypeClusterIP),
+			wantURL: "http://test-name-devfile-registry.test-namespace.svc:8080",
+		},
+		{
+			name:    "Case 2: NodePort",
+			cr:      newRegistry(registry.ExposureTypeNodePort),
+			wantURL: "http://test-name-devfile-registry.test-namespace.svc:8080",
+		},
+		{
+			name:        "Case 3: LoadBalancer without an address",
+			cr:          newRegistry(registry.ExposureTypeLoadBalancer),
+			wantRequeue: true,
+		},
+		{
+			name:      "Case 4: LoadBalancer with an address",
+			cr:        newRegistry(registry.ExposureTypeLoadBalancer),
+			lbIngress: []corev1.LoadBalancerIngress{{IP: "192.0.2.10"}},
+			wantURL:   "http://192.0.2.10:8080",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := registry.GenerateService(tt.cr, scheme, nil)
+			svc.Status.LoadBalancer.Ingress = tt.lbIngress
+			ingress := registry.GenerateIngress(tt.cr, config.Defaults{}, registry.GetDevfileRegistryIngress(tt.cr, config.Defaults{}), scheme, nil)
+			r := newTestReconciler(scheme, svc, ingress)
+
+			url, result, err := r.reconcileExposure(context.TODO(), tt.cr, nil)
+			if err != nil {
+				t.Fatalf("reconcileExposure() unexpected error: %v", err)
+			}
+			if (result != nil) != tt.wantRequeue {
+				t.Errorf("reconcileExposure() result = %v, want requeue %v", result, tt.wantRequeue)
+			}
+			if url != tt.wantURL {
+				t.Errorf("reconcileExposure() URL = %q, want %q", url, tt.wantURL)
+			}
+			err = r.Get(context.TODO(), types.NamespacedName{Name: ingress.Name, Namespace: ingress.Namespace}, &networkingv1.Ingress{})
+			if !errors.IsNotFound(err) {
+				t.Errorf("reconcileExposure() old Ingress not deleted: %v", err)
+			}
+		})
+	}
+}
+
+func TestReconcileGatewayExposureWithoutHostname(t *testing.T) {
+	scheme := newTestScheme()
+	config.SetIsGatewayAPIAvailable(true)
+	defer config.SetIsGatewayAPIAvailable(false)
+
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", UID: "test-uid"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			K8s: registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressDomain: "my-domain"},
+			Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+				Gateway: &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway"},
+			},
+		},
+	}
+	// The hostnames of the HTTPRoute are removed behind the operator, and its updates are reverted
+	httpRoute := registry.GenerateHTTPRoute(cr, config.Defaults{}, scheme, nil)
+	httpRoute.Spec.Hostnames = nil
+	r := newTestReconciler(scheme)
+	r.Client = interceptor.NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(httpRoute).Build(), interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if _, ok := obj.(*gatewayv1.HTTPRoute); ok {
+				return nil
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	})
+
+	url, result, err := r.reconcileExposure(context.TODO(), cr, nil)
+	if err != nil {
+		t.Fatalf("reconcileExposure() unexpected error: %v", err)
+	}
+	if result == nil || result.Requeue || result.RequeueAfter != 0 {
+		t.Errorf("reconcileExposure() result = %v, want stop without requeue", result)
+	}
+	if url != "" {
+		t.Errorf("reconcileExposure() URL = %q, want none", url)
+	}
+	if !meta.IsStatusConditionPresentAndEqual(cr.Status.Conditions, typeNoDeployDevfileRegistry, metav1.ConditionUnknown) {
+		t.Errorf("reconcileExposure() conditions = %v, want %s", cr.Status.Conditions, typeNoDeployDevfileRegistry)
+	}
+}
+
+func TestReconcileOCIRegistryExposure(t *testing.T) {
+	scheme := newTestScheme()
+	enabled := true
+
+	tests := []struct {
+		name        string
+		exposure    registryv1alpha1.DevfileRegistrySpecExposure
+		auth        *registryv1alpha1.DevfileRegistrySpecOCIAuth
+		wantURL     string
+		wantIngress bool
+	}{
+		{
+			name: "Case 1: OCI registry exposed with an Ingress",
+			exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+				OCIRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistryExposure{Enabled: &enabled},
+			},
+			auth:        &registryv1alpha1.DevfileRegistrySpecOCIAuth{},
+			wantURL:     "https://test-name-devfile-registry-test-namespace-oci.my-domain",
+			wantIngress: true,
+		},
+		{
+			name:        "Case 2: OCI registry not exposed",
+			wantURL:     "http://test-name-devfile-registry.test-namespace.svc:5000",
+			wantIngress: false,
+		},
+		{
+			name: "Case 3: OCI registry not exposed without authentication",
+			exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+				OCIRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistryExposure{Enabled: &enabled},
+			},
+			wantURL:     "http://test-name-devfile-registry.test-namespace.svc:5000",
+			wantIngress: false,
+		},
+		{
+			name:        "Case 4: OCI registry on the internal service with a LoadBalancer",
+			exposure:    registryv1alpha1.DevfileRegistrySpecExposure{Type: registry.ExposureTypeLoadBalancer},
+			wantURL:     "http://test-name-devfile-registry-internal.test-namespace.svc:5000",
+			wantIngress: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", UID: "test-uid"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					K8s:         registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressDomain: "my-domain"},
+					Exposure:    tt.exposure,
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{Auth: tt.auth},
+				},
+			}
+			// The OCI registry Ingress left by a previous reconcile
+			oldIngress := registry.GenerateOCIRegistryIngress(cr, config.Defaults{}, "old.example.com", scheme, nil)
+			r := newTestReconciler(scheme, oldIngress)
+
+			url, result, err := r.reconcileOCIRegistryExposure(context.TODO(), cr, nil)
+			if err != nil || result != nil {
+				t.Fatalf("reconcileOCIRegistryExposure() unexpected result: %v, error: %v", result, err)
+			}
+			if url != tt.wantURL {
+				t.Errorf("reconcileOCIRegistryExposure() URL = %q, want %q", url, tt.wantURL)
+			}
+
+			ingress := &networkingv1.Ingress{}
+			err = r.Get(context.TODO(), types.NamespacedName{Name: registry.OCIRegistryIngressName(cr), Namespace: cr.Namespace}, ingress)
+			if (err == nil) != tt.wantIngress {
+				t.Fatalf("reconcileOCIRegistryExposure() Ingress found = %v, want %v", err == nil, tt.wantIngress)
+			}
+			if tt.wantIngress && ingress.Spec.Rules[0].Host != registry.GetOCIRegistryHostname(cr, config.Defaults{}) {
+				t.Errorf("reconcileOCIRegistryExposure() Ingress host = %q, want %q", ingress.Spec.Rules[0].Host, registry.GetOCIRegistryHostname(cr, config.Defaults{}))
+			}
+		})
+	}
+}
+
+func TestReconcileInternalService(t *testing.T) {
+	scheme := newTestScheme()
+
+	tests := []struct {
+		name         string
+		exposureType string
+		existing     bool
+		wantService  bool
+	}{
+		{
+			name:         "Case 1: Internal service created with a LoadBalancer",
+			exposureType: registry.ExposureTypeLoadBalancer,
+			wantService:  true,
+		},
+		{
+			name:         "Case 2: Internal service kept with a NodePort",
+			exposureType: registry.ExposureTypeNodePort,
+			existing:     true,
+			wantService:  true,
+		},
+		{
+			name:         "Case 3: No internal service with a ClusterIP",
+			exposureType: registry.ExposureTypeClusterIP,
+			wantService:  false,
+		},
+		{
+			name:         "Case 4: Internal service deleted once the registry service is no longer external",
+			exposureType: registry.ExposureTypeClusterIP,
+			existing:     true,
+			wantService:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", UID: "test-uid"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: tt.exposureType},
+				},
+			}
+			var objects []client.Object
+			if tt.existing {
+				existing := registry.GenerateInternalService(cr, scheme, nil)
+				// Ports of an older operator version
+				existing.Spec.Ports = existing.Spec.Ports[:1]
+				objects = append(objects, existing)
+			}
+			r := newTestReconciler(scheme, objects...)
+
+			if err := r.reconcileInternalService(context.TODO(), cr, nil); err != nil {
+				t.Fatalf("reconcileInternalService() unexpected error: %v", err)
+			}
+			svc := &corev1.Service{}
+			err := r.Get(context.TODO(), types.NamespacedName{Name: registry.InternalServiceName(cr), Namespace: cr.Namespace}, svc)
+			if (err == nil) != tt.wantService {
+				t.Fatalf("reconcileInternalService() Service found = %v, want %v", err == nil, tt.wantService)
+			}
+			if tt.wantService && !servicePortsEqual(svc.Spec.Ports, registry.GetInternalServicePorts()) {
+				t.Errorf("reconcileInternalService() ports = %v, want %v", svc.Spec.Ports, registry.GetInternalServicePorts())
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/registry"
+	"github.com/devfile/registry-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileGitSource resolves the commit the tracked revision of the Git source points to and records it in the
+// status, so that the registry index is rebuilt when it changes. The remote is only checked once the poll interval
+// has elapsed since the last check. Returns the time left until the revision is checked again, or zero if the
+// registry has no Git source.
+func (r *DevfileRegistryReconciler) reconcileGitSource(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (time.Duration, error) {
+	if !registry.IsGitSourceEnabled(cr) {
+		r.updateGitSourceStatus(cr, "", nil)
+		return 0, nil
+	}
+	if nextCheck := getNextGitSourceCheck(cr, time.Now()); nextCheck > 0 {
+		return nextCheck, nil
+	}
+	git := cr.Spec.Source.Git
+
+	var credentials *util.GitCredentials
+	if git.CredentialsSecret != "" {
+		secret := &corev1.Secret{}
+		err := r.getUserSecret(ctx, types.NamespacedName{Name: git.CredentialsSecret, Namespace: cr.Namespace}, secret)
+		if errors.IsNotFound(err) {
+			r.updateGitSourceStatus(cr, cr.Status.SourceCommit, &metav1.Condition{
+				Reason:  "CredentialsNotFound",
+				Message: fmt.Sprintf("Secret %s holding the credentials of the Git source not found", git.CredentialsSecret),
+			})
+			return registry.DefaultGitSourcePollInterval, nil
+		}
+		if err != nil {
+			r.Log.Error(err, "Error getting the Git source credentials", "name", git.CredentialsSecret)
+			return 0, err
+		}
+		credentials = &util.GitCredentials{
+			Username: string(secret.Data[registry.GitCredentialsUsernameKey]),
+			Password: string(secret.Data[registry.GitCredentialsPasswordKey]),
+		}
+		if credentials.Username == "" {
+			credentials.Username = "git"
+		}
+	}
+
+	// Keep building the index from the last known commit while the revision cannot be resolved
+	commit, err := util.ResolveGitRevision(ctx, git.URL, git.Revision, credentials)
+	if err != nil {
+		r.Log.Info("Failed to resolve the revision of the Git source", "url", git.URL, "revision", git.Revision, "reason", err.Error())
+		r.updateGitSourceStatus(cr, cr.Status.SourceCommit, &metav1.Condition{
+			Reason:  "RevisionNotResolved",
+			Message: err.Error(),
+		})
+		return registry.DefaultGitSourcePollInterval, nil
+	}
+	if commit != cr.Status.SourceCommit {
+		r.Log.Info("Building the registry index from a new commit of the Git source", "url", git.URL, "commit", commit)
+	}
+	r.updateGitSourceStatus(cr, commit, nil)
+	return registry.DefaultGitSourcePollInterval, nil
+}
+
+// getNextGitSourceCheck returns the time left until the tracked revision of the Git source is due to be resolved
+// again, or zero if it is due now: when it was never resolved, after a failed check, or once the spec changed
+func getNextGitSourceCheck(cr *registryv1alpha1.DevfileRegistry, now time.Time) time.Duration {
+	lastCheck := cr.Status.LastSourceCheckTime
+	if lastCheck == nil || cr.Status.SourceCommit == "" || cr.Generation != cr.Status.ObservedGeneration ||
+		meta.IsStatusConditionTrue(cr.Status.Conditions, typeGitSourceFailed) {
+		return 0
+	}
+	nextCheck := lastCheck.Add(registry.DefaultGitSourcePollInterval).Sub(now)
+	if nextCheck < 0 {
+		return 0
+	}
+	return nextCheck
+}
+
+// updateGitSourceStatus reports the commit of the Git source the registry index is built from and why the tracked
+// revision cannot be resolved, failed being nil if it can. The time of the check is only recorded when the revision
+// is resolved.
+func (r *DevfileRegistryReconciler) updateGitSourceStatus(cr *registryv1alpha1.DevfileRegistry, commit string, failed *metav1.Condition) {
+	cr.Status.SourceCommit = commit
+	if failed != nil {
+		failed.Type = typeGitSourceFailed
+		failed.Status = metav1.ConditionTrue
+		meta.SetStatusCondition(&cr.Status.Conditions, *failed)
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typeGitSourceFailed)
+	}
+	switch {
+	case commit == "":
+		cr.Status.LastSourceCheckTime = nil
+	case failed == nil:
+		cr.Status.LastSourceCheckTime = &metav1.Time{Time: time.Now()}
+	}
+}
@@ -0,0 +1,140 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileGitSource(t *testing.T) {
+	scheme := newTestScheme()
+
+	commit := "1111111111111111111111111111111111111111"
+	head := commit + " HEAD\x00symref=HEAD:refs/heads/main\n"
+	refs := fmt.Sprintf("%04x%s0000", len(head)+4, head)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, password, _ := r.BasicAuth(); password != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		_, _ = w.Write([]byte(refs))
+	}))
+	defer server.Close()
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-credentials", Namespace: "test"},
+		Data:       map[string][]byte{registry.GitCredentialsPasswordKey: []byte("token")},
+	}
+
+	tests := []struct {
+		name       string
+		git        *registryv1alpha1.DevfileRegistrySpecGitSource
+		lastCommit string
+		lastCheck  time.Duration
+		wantCommit string
+		wantReason string
+	}{
+		{
+			name:       "No Git source",
+			git:        nil,
+			lastCommit: commit,
+			wantCommit: "",
+		},
+		{
+			name:       "Default branch resolved",
+			git:        &registryv1alpha1.DevfileRegistrySpecGitSource{URL: server.URL, CredentialsSecret: "git-credentials"},
+			wantCommit: commit,
+		},
+		{
+			name:       "Missing credentials keep the last commit",
+			git:        &registryv1alpha1.DevfileRegistrySpecGitSource{URL: server.URL, CredentialsSecret: "missing"},
+			lastCommit: "2222222222222222222222222222222222222222",
+			wantCommit: "2222222222222222222222222222222222222222",
+			wantReason: "CredentialsNotFound",
+		},
+		{
+			name:       "Authentication failure",
+			git:        &registryv1alpha1.DevfileRegistrySpecGitSource{URL: server.URL},
+			wantCommit: "",
+			wantReason: "RevisionNotResolved",
+		},
+		{
+			name:       "Remote not checked before the poll interval elapsed",
+			git:        &registryv1alpha1.DevfileRegistrySpecGitSource{URL: server.URL},
+			lastCommit: "3333333333333333333333333333333333333333",
+			lastCheck:  time.Minute,
+			wantCommit: "3333333333333333333333333333333333333333",
+		},
+		{
+			name:       "Remote checked once the poll interval elapsed",
+			git:        &registryv1alpha1.DevfileRegistrySpecGitSource{URL: server.URL, CredentialsSecret: "git-credentials"},
+			lastCommit: "3333333333333333333333333333333333333333",
+			lastCheck:  registry.DefaultGitSourcePollInterval + time.Minute,
+			wantCommit: commit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Source: registryv1alpha1.DevfileRegistrySpecSource{Git: tt.git},
+				},
+				Status: registryv1alpha1.DevfileRegistryStatus{SourceCommit: tt.lastCommit},
+			}
+			if tt.lastCheck != 0 {
+				cr.Status.LastSourceCheckTime = &metav1.Time{Time: time.Now().Add(-tt.lastCheck)}
+			}
+			r := newTestReconciler(scheme, cr, credentials.DeepCopy())
+
+			requeueAfter, err := r.reconcileGitSource(context.TODO(), cr)
+			if err != nil {
+				t.Fatalf("reconcileGitSource() unexpected error: %v", err)
+			}
+			if (requeueAfter != 0) != (tt.git != nil) {
+				t.Errorf("reconcileGitSource() requeue after %v, want a requeue: %v", requeueAfter, tt.git != nil)
+			}
+			if cr.Status.SourceCommit != tt.wantCommit {
+				t.Errorf("reconcileGitSource() commit = %q, want %q", cr.Status.SourceCommit, tt.wantCommit)
+			}
+			if wantCheck := tt.wantCommit != "" && tt.wantReason == ""; (cr.Status.LastSourceCheckTime != nil) != wantCheck {
+				t.Errorf("reconcileGitSource() last check time = %v, want one: %v", cr.Status.LastSourceCheckTime, wantCheck)
+			}
+
+			condition := meta.FindStatusCondition(cr.Status.Conditions, typeGitSourceFailed)
+			if tt.wantReason == "" && condition != nil {
+				t.Errorf("reconcileGitSource() unexpected condition %v", condition)
+			}
+			if tt.wantReason != "" && (condition == nil || condition.Reason != tt.wantReason) {
+				t.Errorf("reconcileGitSource() condition = %v, want reason %s", condition, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	configv1 "github.com/openshift/api/config/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// newTestScheme returns a scheme holding all the types managed by the reconcilers, the volume snapshots being
+// unstructured as in the operator
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = registryv1alpha1.AddToScheme(scheme)
+	_ = routev1.AddToScheme(scheme)
+	_ = configv1.Install(scheme)
+	_ = gatewayv1.Install(scheme)
+	_ = monitoringv1.AddToScheme(scheme)
+	scheme.AddKnownTypeWithName(registry.VolumeSnapshotGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(registry.VolumeSnapshotGVK.GroupVersion().WithKind(registry.VolumeSnapshotGVK.Kind+"List"), &unstructured.UnstructuredList{})
+	return scheme
+}
+
+// newTestReconciler returns a DevfileRegistryReconciler with a fake client holding the given objects. The status of
+// the DevfileRegistry objects is a subresource, as in a cluster.
+func newTestReconciler(scheme *runtime.Scheme, objects ...client.Object) *DevfileRegistryReconciler {
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&registryv1alpha1.DevfileRegistry{}).
+		Build()
+	return &DevfileRegistryReconciler{
+		Client:   c,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+		Config:   &config.Provider{Reader: c},
+	}
+}
@@ -0,0 +1,413 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/registry"
+	"github.com/devfile/registry-operator/pkg/util"
+	indexSchema "github.com/devfile/registry-support/index/generator/schema"
+	registryLibrary "github.com/devfile/registry-support/registry-library/library"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// mirrorSyncTimeout bounds the time spent by the mirror syncer copying the stacks of a mirrored devfile registry
+	mirrorSyncTimeout = 30 * time.Minute
+	// mirrorProgressInterval is the minimum time between two reports of the progress of a sync in the status
+	mirrorProgressInterval = 30 * time.Second
+	// Backoff of the failed syncs, retried until the sync interval
+	mirrorRetryBaseDelay = 30 * time.Second
+)
+
+// mirrorSyncer syncs the mirrored devfile registries in the background, one DevfileRegistry at a time, so that
+// copying their stacks, which can take minutes, does not block the reconciles. It runs with the manager, in the
+// leader only, and retries the failed syncs with an exponential backoff.
+type mirrorSyncer struct {
+	r     *DevfileRegistryReconciler
+	queue workqueue.RateLimitingInterface
+}
+
+// newMirrorSyncer returns a mirror syncer running the syncs of the given reconciler
+func newMirrorSyncer(r *DevfileRegistryReconciler) *mirrorSyncer {
+	return &mirrorSyncer{
+		r:     r,
+		queue: workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(mirrorRetryBaseDelay, registry.DefaultMirrorInterval)),
+	}
+}
+
+// Start runs the queued syncs until the context is done
+func (s *mirrorSyncer) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		s.queue.ShutDown()
+	}()
+	for {
+		item, shutdown := s.queue.Get()
+		if shutdown {
+			return nil
+		}
+		key := item.(types.NamespacedName)
+		if err := s.r.syncMirror(ctx, key); err != nil {
+			s.r.Log.Info("Failed to sync the mirrored devfile registry, retrying", "DevfileRegistry", key, "reason", err.Error())
+			s.queue.AddRateLimited(key)
+		} else {
+			s.queue.Forget(key)
+		}
+		s.queue.Done(key)
+	}
+}
+
+// NeedLeaderElection only runs the syncs in the leader, which reconciles the DevfileRegistries
+func (s *mirrorSyncer) NeedLeaderElection() bool {
+	return true
+}
+
+// enqueue queues a sync of the given DevfileRegistry, unless a sync or a retry of a failed one is already queued
+func (s *mirrorSyncer) enqueue(key types.NamespacedName) {
+	if s.queue.NumRequeues(key) > 0 {
+		return
+	}
+	s.queue.Add(key)
+}
+
+// reconcileMirror serves the index of the mirrored stacks through the mirror config map, and has the mirror syncer
+// copy the stacks of the mirrored devfile registry in the background when a sync is due. Returns the time left until
+// the next sync is due, or zero if the registry mirrors no devfile registry.
+func (r *DevfileRegistryReconciler) reconcileMirror(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (time.Duration, error) {
+	if !registry.IsMirrorEnabled(cr) {
+		if err := r.deleteOldMirrorConfigMapIfNeeded(ctx, cr); err != nil {
+			return 0, err
+		}
+		r.clearMirrorStatus(cr)
+		return 0, nil
+	}
+	interval := registry.GetMirrorInterval(cr)
+
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.MirrorConfigMapName(cr), Namespace: cr.Namespace}, cm)
+	if errors.IsNotFound(err) {
+		// The devfile index server cannot start without an index, serve an empty one until the first sync
+		if err := r.applyMirrorConfigMap(ctx, cr, nil, "[]"); err != nil {
+			return 0, err
+		}
+	} else if err != nil {
+		r.Log.Error(err, "Failed to get the mirror ConfigMap")
+		return 0, err
+	}
+
+	pods, err := r.getReadyRegistryPods(ctx, cr)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	if !registry.IsMirrorSyncDue(cr, pods, now) {
+		return cr.Status.LastMirrorSyncTime.Add(interval).Sub(now), nil
+	}
+	// The stacks are pushed through the registry service, the sync is started once a registry pod is ready
+	if len(pods) > 0 && r.mirrorSyncer != nil {
+		r.mirrorSyncer.enqueue(client.ObjectKeyFromObject(cr))
+	}
+	return interval, nil
+}
+
+// syncMirror copies the stacks of the mirrored devfile registry of the given DevfileRegistry to its OCI registry,
+// through the registry service, and serves the index of the copied stacks through the mirror config map. The
+// progress and the result of the sync are reported in the status. Returns an error if the sync failed.
+func (r *DevfileRegistryReconciler) syncMirror(ctx context.Context, key types.NamespacedName) error {
+	cr := &registryv1alpha1.DevfileRegistry{}
+	if err := r.Get(ctx, key, cr); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !registry.IsMirrorEnabled(cr) {
+		return nil
+	}
+	pods, err := r.getReadyRegistryPods(ctx, cr)
+	if err != nil {
+		return err
+	}
+	// The sync time is the one it started at, for the pods becoming ready during the sync to be synced again
+	start := time.Now()
+	if len(pods) == 0 || !registry.IsMirrorSyncDue(cr, pods, start) {
+		return nil
+	}
+
+	mirror := cr.Spec.Source.Mirror
+	r.Log.Info("Mirroring the stacks of the devfile registry", "url", mirror.URL, "DevfileRegistry", key)
+	if err := r.reportMirrorSyncProgress(ctx, key, fmt.Sprintf("Fetching the index of %s", mirror.URL)); err != nil {
+		return err
+	}
+
+	index, err := registryLibrary.GetRegistryIndex(mirror.URL, registry.GetMirrorRegistryOptions(cr), indexSchema.StackDevfileType)
+	if err != nil {
+		failed := &metav1.Condition{
+			Reason:  "IndexNotFetched",
+			Message: fmt.Sprintf("Failed to fetch the index of %s: %v", mirror.URL, err),
+		}
+		return r.finishMirrorSync(ctx, key, nil, 0, failed)
+	}
+
+	from, to, failed, err := r.getMirrorRegistries(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if failed != nil {
+		return r.finishMirrorSync(ctx, key, nil, 0, failed)
+	}
+
+	syncCtx, cancel := context.WithTimeout(ctx, mirrorSyncTimeout)
+	defer cancel()
+	stacks := registry.GetMirroredStacks(index)
+	mirrored := []indexSchema.Schema{}
+	failedStacks := []string{}
+	lastProgress := time.Now()
+	for i, stack := range stacks {
+		if time.Since(lastProgress) >= mirrorProgressInterval {
+			lastProgress = time.Now()
+			message := fmt.Sprintf("Mirrored %d of the %d stacks of %s", i, len(stacks), mirror.URL)
+			if err := r.reportMirrorSyncProgress(ctx, key, message); err != nil {
+				return err
+			}
+		}
+		if err := mirrorStack(syncCtx, stack, from, to); err != nil {
+			r.Log.Info("Failed to mirror stack", "stack", stack.Name, "reason", err.Error())
+			failedStacks = append(failedStacks, stack.Name)
+			continue
+		}
+		mirrored = append(mirrored, stack)
+	}
+
+	mirrorIndex, err := registry.GetMirrorIndex(mirrored)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{}
+	err = r.Get(ctx, types.NamespacedName{Name: registry.MirrorConfigMapName(cr), Namespace: cr.Namespace}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "Failed to get the mirror ConfigMap")
+		return err
+	}
+	if err := r.applyMirrorConfigMap(ctx, cr, cm, mirrorIndex); err != nil {
+		return err
+	}
+
+	var syncFailed *metav1.Condition
+	if len(failedStacks) > 0 {
+		syncFailed = &metav1.Condition{
+			Reason:  "StacksNotMirrored",
+			Message: fmt.Sprintf("Failed to mirror the stacks %s of %s", strings.Join(failedStacks, ", "), mirror.URL),
+		}
+	}
+	r.Log.Info("Mirrored the stacks of the devfile registry", "url", mirror.URL, "stacks", len(mirrored), "failed", len(failedStacks))
+	lastSync := metav1.NewTime(start)
+	return r.finishMirrorSync(ctx, key, &lastSync, int32(len(mirrored)), syncFailed)
+}
+
+// getReadyRegistryPods returns the ready pods of the registry, a sync being due when one became ready since the
+// last one
+func (r *DevfileRegistryReconciler) getReadyRegistryPods(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	err := r.List(ctx, podList, client.InNamespace(cr.Namespace), client.MatchingLabels(registry.LabelsForDevfileRegistry(cr)))
+	if err != nil {
+		r.Log.Error(err, "Failed to list the DevfileRegistry pods")
+		return nil, err
+	}
+	pods := []corev1.Pod{}
+	for _, pod := range podList.Items {
+		if ready, _ := registry.GetPodReadyTime(&pod); ready && pod.DeletionTimestamp == nil {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// getMirrorRegistries returns the OCI registry of the mirrored devfile registry and the one of the registry, reached
+// through the registry service, along with a condition telling why the registry cannot be authenticated to, if it
+// cannot
+func (r *DevfileRegistryReconciler) getMirrorRegistries(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (util.OCIRegistry, util.OCIRegistry, *metav1.Condition, error) {
+	mirror := cr.Spec.Source.Mirror
+	from := util.OCIRegistry{SkipTLSVerify: mirror.SkipTLSVerify}
+	if mirrorURL, err := url.Parse(mirror.URL); err == nil {
+		from.Host = mirrorURL.Host
+		from.PlainHTTP = mirrorURL.Scheme == "http"
+	}
+	to := util.OCIRegistry{PlainHTTP: true}
+	if serviceURL, err := url.Parse(registry.GetServiceURL(cr, registry.OCIServerPort)); err == nil {
+		to.Host = serviceURL.Host
+	}
+
+	if !registry.IsOCIRegistryAuthEnabled(cr) {
+		return from, to, nil, nil
+	}
+	// The stacks are pushed with the credential generated for the OCI registry clients
+	secretName := registry.GetOCIRegistryAuthSecretName(cr)
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: cr.Namespace}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "Error getting the OCI registry credentials", "name", secretName)
+		return from, to, nil, err
+	}
+	to.Username = string(secret.Data[registry.OCIRegistryAuthUsernameKey])
+	to.Password = string(secret.Data[registry.OCIRegistryAuthPasswordKey])
+	if to.Username == "" || to.Password == "" {
+		return from, to, &metav1.Condition{
+			Reason: "CredentialsNotFound",
+			Message: fmt.Sprintf("Secret %s has no %s and %s keys to push the mirrored stacks to the OCI registry with",
+				secretName, registry.OCIRegistryAuthUsernameKey, registry.OCIRegistryAuthPasswordKey),
+		}, nil
+	}
+	return from, to, nil, nil
+}
+
+// mirrorStack copies every version of the given stack to the given OCI registry
+func mirrorStack(ctx context.Context, stack indexSchema.Schema, from util.OCIRegistry, to util.OCIRegistry) error {
+	for _, version := range stack.Versions {
+		err := util.CopyOCIArtifact(ctx, from, to, version.Links["self"], registryLibrary.DevfileAllMediaTypesList)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMirrorConfigMap creates the mirror config map holding the given index, or updates the given one
+func (r *DevfileRegistryReconciler) applyMirrorConfigMap(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, cm *corev1.ConfigMap, index string) error {
+	if cm == nil || cm.ResourceVersion == "" {
+		cm = registry.GenerateMirrorConfigMap(cr, index, r.Scheme, registry.LabelsForDevfileRegistry(cr))
+		r.Log.Info("Creating the mirror ConfigMap", "ConfigMap.Name", cm.Name)
+		err := r.Create(ctx, cm)
+		if err != nil {
+			r.Log.Error(err, "Failed to create the mirror ConfigMap")
+		}
+		return err
+	}
+	if cm.Data[registry.MirrorIndexKey] == index {
+		return nil
+	}
+	cm.Data = map[string]string{registry.MirrorIndexKey: index}
+	err := r.Update(ctx, cm)
+	if err != nil {
+		r.Log.Error(err, "Failed to update the mirror ConfigMap")
+	}
+	return err
+}
+
+// deleteOldMirrorConfigMapIfNeeded deletes the mirror config map once the registry no longer mirrors a devfile registry
+func (r *DevfileRegistryReconciler) deleteOldMirrorConfigMapIfNeeded(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) error {
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.MirrorConfigMapName(cr), Namespace: cr.Namespace}, cm)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		r.Log.Error(err, "Failed to get the mirror ConfigMap")
+		return err
+	}
+	if !metav1.IsControlledBy(cm, cr) {
+		return nil
+	}
+	r.Log.Info("Deleting the mirror ConfigMap", "ConfigMap.Name", cm.Name)
+	if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "Failed to delete the mirror ConfigMap")
+		return err
+	}
+	return nil
+}
+
+// clearMirrorStatus removes the result of the last sync from the status, once the registry no longer mirrors a devfile
+// registry
+func (r *DevfileRegistryReconciler) clearMirrorStatus(cr *registryv1alpha1.DevfileRegistry) {
+	cr.Status.LastMirrorSyncTime = nil
+	cr.Status.MirroredStacks = 0
+	meta.RemoveStatusCondition(&cr.Status.Conditions, typeMirrorSyncFailed)
+	meta.RemoveStatusCondition(&cr.Status.Conditions, typeMirrorSyncInProgress)
+}
+
+// reportMirrorSyncProgress reports the progress of the running sync with the MirrorSyncInProgress condition
+func (r *DevfileRegistryReconciler) reportMirrorSyncProgress(ctx context.Context, key types.NamespacedName, message string) error {
+	return r.updateMirrorSyncStatus(ctx, key, func(status *registryv1alpha1.DevfileRegistryStatus) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:    typeMirrorSyncInProgress,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Syncing",
+			Message: message,
+		})
+	})
+}
+
+// finishMirrorSync reports the result of the sync: the time of the last successful sync, lastSync being nil if the
+// sync failed before copying any stack, the number of stacks it mirrored and why it failed, failed being nil if it
+// did not. Returns an error if the sync failed.
+func (r *DevfileRegistryReconciler) finishMirrorSync(ctx context.Context, key types.NamespacedName, lastSync *metav1.Time, stacks int32, failed *metav1.Condition) error {
+	err := r.updateMirrorSyncStatus(ctx, key, func(status *registryv1alpha1.DevfileRegistryStatus) {
+		if lastSync != nil {
+			status.LastMirrorSyncTime = lastSync
+			status.MirroredStacks = stacks
+		}
+		if failed != nil {
+			failed.Type = typeMirrorSyncFailed
+			failed.Status = metav1.ConditionTrue
+			meta.SetStatusCondition(&status.Conditions, *failed)
+		} else {
+			meta.RemoveStatusCondition(&status.Conditions, typeMirrorSyncFailed)
+		}
+		meta.RemoveStatusCondition(&status.Conditions, typeMirrorSyncInProgress)
+	})
+	if err != nil {
+		return err
+	}
+	if failed != nil {
+		return fmt.Errorf("%s", failed.Message)
+	}
+	return nil
+}
+
+// updateMirrorSyncStatus applies the given change to the latest status of the given DevfileRegistry, as the syncs
+// run alongside the reconciles
+func (r *DevfileRegistryReconciler) updateMirrorSyncStatus(ctx context.Context, key types.NamespacedName, update func(status *registryv1alpha1.DevfileRegistryStatus)) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cr := &registryv1alpha1.DevfileRegistry{}
+		if err := r.Get(ctx, key, cr); err != nil {
+			return err
+		}
+		status := cr.Status.DeepCopy()
+		update(status)
+		if equality.Semantic.DeepEqual(cr.Status, *status) {
+			return nil
+		}
+		cr.Status = *status
+		return r.Status().Update(ctx, cr)
+	})
+	if err != nil {
+		r.Log.Error(err, "Failed to update DevfileRegistry status")
+	}
+	return err
+}
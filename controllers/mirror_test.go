@@ -0,0 +1,269 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileMirror(t *testing.T) {
+	scheme := newTestScheme()
+
+	recentSync := metav1.NewTime(time.Now().Add(-10 * time.Minute).Truncate(time.Second))
+	mirror := &registryv1alpha1.DevfileRegistrySpecMirrorSource{URL: "https://registry.devfile.io"}
+
+	tests := []struct {
+		name        string
+		mirror      *registryv1alpha1.DevfileRegistrySpecMirrorSource
+		lastSync    *metav1.Time
+		cmIndex     string
+		podReady    bool
+		wantCMIndex string
+		wantQueued  int
+	}{
+		{
+			name:     "No mirrored registry",
+			lastSync: &recentSync,
+			cmIndex:  "[]",
+		},
+		{
+			name:        "Empty index served until the first sync",
+			mirror:      mirror,
+			wantCMIndex: "[]",
+		},
+		{
+			name:        "Sync started once a registry pod is ready",
+			mirror:      mirror,
+			cmIndex:     `[{"name":"go"}]`,
+			podReady:    true,
+			wantCMIndex: `[{"name":"go"}]`,
+			wantQueued:  1,
+		},
+		{
+			name:        "Sync not due",
+			mirror:      mirror,
+			lastSync:    &recentSync,
+			cmIndex:     "[]",
+			wantCMIndex: "[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test", UID: "devfile-registry-uid"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Source: registryv1alpha1.DevfileRegistrySpecSource{Mirror: tt.mirror},
+				},
+				Status: registryv1alpha1.DevfileRegistryStatus{LastMirrorSyncTime: tt.lastSync},
+			}
+			objects := []client.Object{cr}
+			if tt.cmIndex != "" {
+				objects = append(objects, registry.GenerateMirrorConfigMap(cr, tt.cmIndex, scheme, registry.LabelsForDevfileRegistry(cr)))
+			}
+			if tt.podReady {
+				objects = append(objects, newReadyRegistryPod(cr))
+			}
+			r := newTestReconciler(scheme, objects...)
+			r.mirrorSyncer = newMirrorSyncer(r)
+			defer r.mirrorSyncer.queue.ShutDown()
+
+			requeueAfter, err := r.reconcileMirror(context.TODO(), cr)
+			if err != nil {
+				t.Fatalf("reconcileMirror() unexpected error: %v", err)
+			}
+			if (requeueAfter != 0) != (tt.mirror != nil) {
+				t.Errorf("reconcileMirror() requeue after %v, want a requeue: %v", requeueAfter, tt.mirror != nil)
+			}
+			if requeueAfter > registry.DefaultMirrorInterval {
+				t.Errorf("reconcileMirror() requeue after %v, longer than the sync interval", requeueAfter)
+			}
+			if queued := r.mirrorSyncer.queue.Len(); queued != tt.wantQueued {
+				t.Errorf("reconcileMirror() queued %d syncs, want %d", queued, tt.wantQueued)
+			}
+
+			cm := &corev1.ConfigMap{}
+			err = r.Get(context.TODO(), types.NamespacedName{Name: registry.MirrorConfigMapName(cr), Namespace: cr.Namespace}, cm)
+			if tt.wantCMIndex == "" {
+				if !errors.IsNotFound(err) {
+					t.Errorf("reconcileMirror() expected the mirror ConfigMap to be deleted, got error: %v", err)
+				}
+			} else if got := strings.TrimSpace(cm.Data[registry.MirrorIndexKey]); got != tt.wantCMIndex {
+				t.Errorf("reconcileMirror() index = %s, want %s", got, tt.wantCMIndex)
+			}
+		})
+	}
+}
+
+func TestSyncMirror(t *testing.T) {
+	scheme := newTestScheme()
+
+	index := `[
+  {"name": "go", "type": "stack", "versions": [{"version": "1.0.0", "links": {"self": "devfile-catalog/go:1.0.0"}, "resources": ["devfile.yaml"]}]},
+  {"name": "nodejs-basic", "type": "sample"}
+]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2index" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(index))
+	}))
+	defer server.Close()
+
+	recentSync := metav1.NewTime(time.Now().Add(-10 * time.Minute).Truncate(time.Second))
+	htpasswd := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "htpasswd", Namespace: "test"},
+		Data:       map[string][]byte{registry.OCIRegistryHtpasswdKey: []byte("user:hash\n")},
+	}
+
+	tests := []struct {
+		name         string
+		mirror       *registryv1alpha1.DevfileRegistrySpecMirrorSource
+		auth         *registryv1alpha1.DevfileRegistrySpecOCIAuth
+		lastSync     *metav1.Time
+		podReady     bool
+		wantErr      bool
+		wantCMIndex  string
+		wantLastSync bool
+		wantReason   string
+	}{
+		{
+			name:        "No ready registry pod",
+			mirror:      &registryv1alpha1.DevfileRegistrySpecMirrorSource{URL: server.URL},
+			wantCMIndex: "[]",
+		},
+		{
+			name:        "Sync not due",
+			mirror:      &registryv1alpha1.DevfileRegistrySpecMirrorSource{URL: server.URL},
+			lastSync:    &recentSync,
+			podReady:    true,
+			wantCMIndex: "[]",
+		},
+		{
+			name:        "Unreachable registry",
+			mirror:      &registryv1alpha1.DevfileRegistrySpecMirrorSource{URL: server.URL + "/missing/"},
+			podReady:    true,
+			wantErr:     true,
+			wantCMIndex: "[]",
+			wantReason:  "IndexNotFetched",
+		},
+		{
+			name:        "OCI registry credentials not generated yet",
+			mirror:      &registryv1alpha1.DevfileRegistrySpecMirrorSource{URL: server.URL},
+			auth:        &registryv1alpha1.DevfileRegistrySpecOCIAuth{HtpasswdSecret: "htpasswd"},
+			podReady:    true,
+			wantErr:     true,
+			wantCMIndex: "[]",
+			wantReason:  "CredentialsNotFound",
+		},
+		{
+			name:         "Stacks not found in the mirrored registry",
+			mirror:       &registryv1alpha1.DevfileRegistrySpecMirrorSource{URL: server.URL},
+			podReady:     true,
+			wantErr:      true,
+			wantCMIndex:  "[]",
+			wantLastSync: true,
+			wantReason:   "StacksNotMirrored",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test", UID: "devfile-registry-uid"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Source:      registryv1alpha1.DevfileRegistrySpecSource{Mirror: tt.mirror},
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{Auth: tt.auth},
+				},
+				Status: registryv1alpha1.DevfileRegistryStatus{LastMirrorSyncTime: tt.lastSync},
+			}
+			objects := []client.Object{cr, htpasswd.DeepCopy(), registry.GenerateMirrorConfigMap(cr, "[]", scheme, registry.LabelsForDevfileRegistry(cr))}
+			if tt.podReady {
+				objects = append(objects, newReadyRegistryPod(cr))
+			}
+			r := newTestReconciler(scheme, objects...)
+
+			err := r.syncMirror(context.TODO(), client.ObjectKeyFromObject(cr))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("syncMirror() error = %v, want an error: %v", err, tt.wantErr)
+			}
+
+			if err := r.Get(context.TODO(), client.ObjectKeyFromObject(cr), cr); err != nil {
+				t.Fatalf("syncMirror() unexpected error getting the DevfileRegistry: %v", err)
+			}
+			if tt.wantLastSync == equalTimes(cr.Status.LastMirrorSyncTime, tt.lastSync) {
+				t.Errorf("syncMirror() last sync = %v, want a new sync time: %v", cr.Status.LastMirrorSyncTime, tt.wantLastSync)
+			}
+			if condition := meta.FindStatusCondition(cr.Status.Conditions, typeMirrorSyncInProgress); condition != nil {
+				t.Errorf("syncMirror() unexpected condition %v once the sync is done", condition)
+			}
+
+			cm := &corev1.ConfigMap{}
+			err = r.Get(context.TODO(), types.NamespacedName{Name: registry.MirrorConfigMapName(cr), Namespace: cr.Namespace}, cm)
+			if err != nil {
+				t.Errorf("syncMirror() unexpected error getting the mirror ConfigMap: %v", err)
+			} else if got := strings.TrimSpace(cm.Data[registry.MirrorIndexKey]); got != tt.wantCMIndex {
+				t.Errorf("syncMirror() index = %s, want %s", got, tt.wantCMIndex)
+			}
+
+			condition := meta.FindStatusCondition(cr.Status.Conditions, typeMirrorSyncFailed)
+			if tt.wantReason == "" && condition != nil {
+				t.Errorf("syncMirror() unexpected condition %v", condition)
+			}
+			if tt.wantReason != "" && (condition == nil || condition.Reason != tt.wantReason) {
+				t.Errorf("syncMirror() condition = %v, want reason %s", condition, tt.wantReason)
+			}
+		})
+	}
+}
+
+// newReadyRegistryPod returns a registry pod which became ready an hour ago
+func newReadyRegistryPod(cr *registryv1alpha1.DevfileRegistry) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry-0", Namespace: cr.Namespace, Labels: registry.LabelsForDevfileRegistry(cr)},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			}},
+		},
+	}
+}
+
+// equalTimes returns true if both times are unset or equal
+func equalTimes(a *metav1.Time, b *metav1.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b)
+}
@@ -0,0 +1,86 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// updateServiceMonitor checks to see if the spec of an existing ServiceMonitor needs to be updated
+func (r *DevfileRegistryReconciler) updateServiceMonitor(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, serviceMonitor *monitoringv1.ServiceMonitor) error {
+	spec := registry.GetServiceMonitorSpec(cr, registry.LabelsForDevfileRegistry(cr))
+	if equality.Semantic.DeepEqual(serviceMonitor.Spec, spec) {
+		return nil
+	}
+	serviceMonitor.Spec = spec
+	return r.Update(ctx, serviceMonitor)
+}
+
+// deleteOldServiceMonitorIfNeeded deletes the ServiceMonitor of the registry once monitoring is disabled
+func (r *DevfileRegistryReconciler) deleteOldServiceMonitorIfNeeded(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) error {
+	// ServiceMonitors cannot exist without the API of the Prometheus Operator
+	if registry.IsMonitoringEnabled(cr) || !config.IsMonitoringAPIAvailable() {
+		return nil
+	}
+
+	serviceMonitor := &monitoringv1.ServiceMonitor{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.ServiceMonitorName(cr), Namespace: cr.Namespace}, serviceMonitor)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// ServiceMonitor not found, so there's no old ServiceMonitor to delete.
+			return nil
+		}
+		r.Log.Error(err, "Error getting ServiceMonitor")
+		return err
+	}
+	if !metav1.IsControlledBy(serviceMonitor, cr) {
+		return nil
+	}
+
+	r.Log.Info("Old ServiceMonitor " + serviceMonitor.Name + " found. Deleting it as monitoring has been disabled.")
+	err = r.Delete(ctx, serviceMonitor)
+	if err != nil {
+		r.Log.Error(err, "Error deleting ServiceMonitor", "name", serviceMonitor.Name)
+		return err
+	}
+	return nil
+}
+
+// updateMonitoringStatus reports when monitoring is enabled on a cluster without the API of the Prometheus Operator
+func (r *DevfileRegistryReconciler) updateMonitoringStatus(cr *registryv1alpha1.DevfileRegistry) {
+	if registry.IsMonitoringEnabled(cr) && !config.IsMonitoringAPIAvailable() {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    typeMonitoringUnavailable,
+			Status:  metav1.ConditionTrue,
+			Reason:  "MonitoringAPINotInstalled",
+			Message: "The Prometheus Operator was not installed on the cluster when the operator started, no ServiceMonitor is generated for the registry until the operator is restarted",
+		})
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typeMonitoringUnavailable)
+	}
+}
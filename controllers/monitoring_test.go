@@ -0,0 +1,116 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDeleteOldServiceMonitorIfNeeded(t *testing.T) {
+	scheme := newTestScheme()
+	config.SetIsMonitoringAPIAvailable(true)
+	defer config.SetIsMonitoringAPIAvailable(false)
+	enabled := true
+
+	tests := []struct {
+		name        string
+		enabled     *bool
+		wantDeleted bool
+	}{
+		{
+			name:        "Case 1: ServiceMonitor deleted once monitoring is disabled",
+			wantDeleted: true,
+		},
+		{
+			name:        "Case 2: ServiceMonitor kept while monitoring is enabled",
+			enabled:     &enabled,
+			wantDeleted: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", UID: "test-uid"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Monitoring: registryv1alpha1.DevfileRegistrySpecMonitoring{Enabled: tt.enabled},
+				},
+			}
+			serviceMonitor := registry.GenerateServiceMonitor(cr, scheme, registry.LabelsForDevfileRegistry(cr))
+			r := newTestReconciler(scheme, serviceMonitor)
+
+			if err := r.deleteOldServiceMonitorIfNeeded(context.TODO(), cr); err != nil {
+				t.Fatalf("deleteOldServiceMonitorIfNeeded() unexpected error: %v", err)
+			}
+			err := r.Get(context.TODO(), types.NamespacedName{Name: serviceMonitor.Name, Namespace: serviceMonitor.Namespace}, &monitoringv1.ServiceMonitor{})
+			if deleted := errors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Errorf("deleteOldServiceMonitorIfNeeded() deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestUpdateMonitoringStatus(t *testing.T) {
+	scheme := newTestScheme()
+	defer config.SetIsMonitoringAPIAvailable(false)
+	enabled := true
+
+	tests := []struct {
+		name          string
+		apiAvailable  bool
+		wantCondition bool
+	}{
+		{
+			name:          "Case 1: Prometheus Operator not installed",
+			apiAvailable:  false,
+			wantCondition: true,
+		},
+		{
+			name:          "Case 2: Prometheus Operator installed",
+			apiAvailable:  true,
+			wantCondition: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetIsMonitoringAPIAvailable(tt.apiAvailable)
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Monitoring: registryv1alpha1.DevfileRegistrySpecMonitoring{Enabled: &enabled},
+				},
+			}
+			r := newTestReconciler(scheme, cr)
+
+			r.updateMonitoringStatus(cr)
+			if got := meta.IsStatusConditionTrue(cr.Status.Conditions, typeMonitoringUnavailable); got != tt.wantCondition {
+				t.Errorf("updateMonitoringStatus() condition = %v, want %v", got, tt.wantCondition)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+// withDefaults returns a copy of the reconciler using the given operator-wide defaults, scoped to a single reconcile
+func (r *DevfileRegistryReconciler) withDefaults(defaults config.Defaults) *DevfileRegistryReconciler {
+	scoped := *r
+	scoped.defaults = defaults
+	return &scoped
+}
+
+// registriesForOperatorConfig returns a request for each DevfileRegistry of the cluster when the RegistryOperatorConfig
+// resource changes, so that they are updated with the new defaults
+func (r *DevfileRegistryReconciler) registriesForOperatorConfig(ctx context.Context, operatorConfig client.Object) []reconcile.Request {
+	if operatorConfig.GetName() != registryv1alpha1.RegistryOperatorConfigName {
+		return nil
+	}
+	return r.allRegistries(ctx)
+}
+
+// registriesForIngressClass returns a request for each DevfileRegistry of the cluster when an IngressClass changes,
+// as it may change the default IngressClass of the cluster
+func (r *DevfileRegistryReconciler) registriesForIngressClass(ctx context.Context, _ client.Object) []reconcile.Request {
+	return r.allRegistries(ctx)
+}
+
+// registriesForClusterIngress returns a request for each DevfileRegistry of the cluster when the ingress configuration
+// of the OpenShift cluster changes, as it may change the default ingress domain of the registries
+func (r *DevfileRegistryReconciler) registriesForClusterIngress(ctx context.Context, ingress client.Object) []reconcile.Request {
+	if ingress.GetName() != "cluster" {
+		return nil
+	}
+	return r.allRegistries(ctx)
+}
+
+// allRegistries returns a request for each DevfileRegistry of the cluster
+func (r *DevfileRegistryReconciler) allRegistries(ctx context.Context) []reconcile.Request {
+	registries := &registryv1alpha1.DevfileRegistryList{}
+	if err := r.List(ctx, registries); err != nil {
+		r.Log.Error(err, "Failed to list DevfileRegistries")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, cr := range registries.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace},
+		})
+	}
+	return requests
+}
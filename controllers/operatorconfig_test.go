@@ -0,0 +1,61 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRegistriesForOperatorConfig(t *testing.T) {
+	scheme := newTestScheme()
+
+	r := newTestReconciler(scheme,
+		&registryv1alpha1.DevfileRegistry{ObjectMeta: metav1.ObjectMeta{Name: "registry-a", Namespace: "test-a"}},
+		&registryv1alpha1.DevfileRegistry{ObjectMeta: metav1.ObjectMeta{Name: "registry-b", Namespace: "test-b"}},
+	)
+
+	tests := []struct {
+		name         string
+		configName   string
+		wantRequests int
+	}{
+		{
+			name:         "Case 1: Every registry updated",
+			configName:   registryv1alpha1.RegistryOperatorConfigName,
+			wantRequests: 2,
+		},
+		{
+			name:         "Case 2: RegistryOperatorConfig with another name ignored",
+			configName:   "other",
+			wantRequests: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operatorConfig client.Object = &registryv1alpha1.RegistryOperatorConfig{ObjectMeta: metav1.ObjectMeta{Name: tt.configName}}
+			if got := r.registriesForOperatorConfig(context.TODO(), operatorConfig); len(got) != tt.wantRequests {
+				t.Errorf("registriesForOperatorConfig() = %v, want %d requests", got, tt.wantRequests)
+			}
+		})
+	}
+}
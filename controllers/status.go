@@ -0,0 +1,240 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/registry"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// registryComponents maps the components of the registry to the containers of the registry pods
+var registryComponents = []struct {
+	name      string
+	container string
+}{
+	{name: "devfile-index", container: indexContainerName},
+	{name: "oci-registry", container: ociContainerName},
+	{name: "registry-viewer", container: viewerContainerName},
+}
+
+// degradingConditionTypes are the conditions reporting a failure of the registry when true
+var degradingConditionTypes = []string{
+	typeRegistryConfigInvalid,
+	typePodTemplateOverrideInvalid,
+	typeStorageResizeBlocked,
+	typeBackupFailed,
+	typeGitSourceFailed,
+	typeMirrorSyncFailed,
+	typeServerNotReady,
+}
+
+// updateRolloutStatus reports the generation of the custom resource that was reconciled, the exposure type, the
+// status of each component and the Available, Progressing and Degraded conditions, summarizing the rollout of the
+// registry deployment and the other conditions.
+func (r *DevfileRegistryReconciler) updateRolloutStatus(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) error {
+	dep := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.DeploymentName(cr), Namespace: cr.Namespace}, dep)
+	if errors.IsNotFound(err) {
+		dep = nil
+	} else if err != nil {
+		r.Log.Error(err, "Failed to get Deployment")
+		return err
+	}
+	podList := &corev1.PodList{}
+	err = r.List(ctx, podList, client.InNamespace(cr.Namespace), client.MatchingLabels(registry.LabelsForDevfileRegistry(cr)))
+	if err != nil {
+		r.Log.Error(err, "Failed to list the DevfileRegistry pods")
+		return err
+	}
+
+	cr.Status.ObservedGeneration = cr.Generation
+	cr.Status.ExposureType = registry.GetExposureType(cr)
+	cr.Status.Components = getComponentStatuses(dep, podList.Items)
+	for _, condition := range getRolloutConditions(cr, dep) {
+		condition.ObservedGeneration = cr.Generation
+		meta.SetStatusCondition(&cr.Status.Conditions, condition)
+	}
+	return nil
+}
+
+// updateServerReadinessStatus reports when the registry server did not answer on the given URL in time, given the
+// error of the readiness check
+func (r *DevfileRegistryReconciler) updateServerReadinessStatus(cr *registryv1alpha1.DevfileRegistry, url string, err error) {
+	if err != nil {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    typeServerNotReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ReadinessTimeout",
+			Message: fmt.Sprintf("Devfile registry server %s failed to start after 30 seconds", url),
+		})
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, typeServerNotReady)
+	}
+}
+
+// getComponentStatuses returns the status of each component deployed by the given registry deployment, a component
+// being ready if its container is ready in at least one of the given pods
+func getComponentStatuses(dep *appsv1.Deployment, pods []corev1.Pod) []registryv1alpha1.DevfileRegistryComponentStatus {
+	if dep == nil {
+		return nil
+	}
+	var components []registryv1alpha1.DevfileRegistryComponentStatus
+	for _, component := range registryComponents {
+		for _, container := range dep.Spec.Template.Spec.Containers {
+			if container.Name != component.container {
+				continue
+			}
+			components = append(components, registryv1alpha1.DevfileRegistryComponentStatus{
+				Name:  component.name,
+				Image: container.Image,
+				Ready: isContainerReady(pods, container.Name),
+			})
+		}
+	}
+	return components
+}
+
+// isContainerReady returns true if the named container is ready in at least one of the given pods
+func isContainerReady(pods []corev1.Pod, name string) bool {
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == name && containerStatus.Ready {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getRolloutConditions returns the Available, Progressing and Degraded conditions of the registry, given its
+// deployment, nil if it is not deployed
+func getRolloutConditions(cr *registryv1alpha1.DevfileRegistry, dep *appsv1.Deployment) []metav1.Condition {
+	available := metav1.Condition{
+		Type:    typeAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NotDeployed",
+		Message: "The registry is not deployed",
+	}
+	progressing := metav1.Condition{
+		Type:    typeProgressing,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Deploying",
+		Message: "The registry deployment is being created",
+	}
+	degraded := metav1.Condition{
+		Type:    typeDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: "The registry is working as expected",
+	}
+
+	if dep != nil {
+		if dep.Status.AvailableReplicas > 0 {
+			available.Status = metav1.ConditionTrue
+			available.Reason = "ReplicasAvailable"
+			available.Message = fmt.Sprintf("%d of %d registry pods are available", dep.Status.AvailableReplicas, getDesiredReplicas(dep))
+		} else {
+			available.Reason = "NoReplicasAvailable"
+			available.Message = "No registry pod is available"
+		}
+	}
+
+	// A blocked deployment is not rolled out until the spec of the custom resource is fixed
+	if blocked := meta.FindStatusCondition(cr.Status.Conditions, typeNoDeployDevfileRegistry); blocked != nil {
+		progressing.Status = metav1.ConditionFalse
+		progressing.Reason = blocked.Reason
+		progressing.Message = blocked.Message
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = blocked.Reason
+		degraded.Message = blocked.Message
+		return []metav1.Condition{available, progressing, degraded}
+	}
+
+	if dep != nil {
+		if isDeploymentRolledOut(dep) {
+			progressing.Status = metav1.ConditionFalse
+			progressing.Reason = "RolloutComplete"
+			progressing.Message = "The registry deployment has rolled out"
+		} else {
+			progressing.Reason = "RollingOut"
+			progressing.Message = fmt.Sprintf("%d of %d registry pods are updated and available", dep.Status.UpdatedReplicas, getDesiredReplicas(dep))
+		}
+
+		if condition := getDeploymentCondition(dep, appsv1.DeploymentProgressing); condition != nil && condition.Reason == "ProgressDeadlineExceeded" {
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = condition.Reason
+			degraded.Message = condition.Message
+		}
+	}
+
+	// The rollout is stuck while the registry server does not answer on its URL
+	if notReady := meta.FindStatusCondition(cr.Status.Conditions, typeServerNotReady); notReady != nil && notReady.Status == metav1.ConditionTrue {
+		progressing.Status = metav1.ConditionFalse
+		progressing.Reason = notReady.Reason
+		progressing.Message = notReady.Message
+	}
+
+	for _, conditionType := range degradingConditionTypes {
+		if condition := meta.FindStatusCondition(cr.Status.Conditions, conditionType); condition != nil && condition.Status == metav1.ConditionTrue {
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = condition.Reason
+			degraded.Message = condition.Message
+			break
+		}
+	}
+	return []metav1.Condition{available, progressing, degraded}
+}
+
+// isDeploymentRolledOut returns true once all the pods of the deployment run its latest pod template and are available
+func isDeploymentRolledOut(dep *appsv1.Deployment) bool {
+	desired := getDesiredReplicas(dep)
+	return dep.Status.ObservedGeneration >= dep.Generation &&
+		dep.Status.UpdatedReplicas >= desired &&
+		dep.Status.Replicas <= dep.Status.UpdatedReplicas &&
+		dep.Status.AvailableReplicas >= dep.Status.UpdatedReplicas
+}
+
+// getDesiredReplicas returns the number of pods the deployment should run
+func getDesiredReplicas(dep *appsv1.Deployment) int32 {
+	if dep.Spec.Replicas == nil {
+		return 1
+	}
+	return *dep.Spec.Replicas
+}
+
+// getDeploymentCondition returns the condition of the deployment with the given type, nil if it is not set
+func getDeploymentCondition(dep *appsv1.Deployment, conditionType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
+	for i := range dep.Status.Conditions {
+		if dep.Status.Conditions[i].Type == conditionType {
+			return &dep.Status.Conditions[i]
+		}
+	}
+	return nil
+}
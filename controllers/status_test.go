@@ -0,0 +1,317 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGetRolloutConditions(t *testing.T) {
+	replicas := int32(2)
+	newDeployment := func(status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     status,
+		}
+	}
+
+	tests := []struct {
+		name            string
+		conditions      []metav1.Condition
+		dep             *appsv1.Deployment
+		wantAvailable   metav1.ConditionStatus
+		wantProgressing metav1.ConditionStatus
+		wantDegraded    metav1.ConditionStatus
+		wantReason      string
+	}{
+		{
+			name:            "Case 1: Deployment not created yet",
+			wantAvailable:   metav1.ConditionFalse,
+			wantProgressing: metav1.ConditionTrue,
+			wantDegraded:    metav1.ConditionFalse,
+		},
+		{
+			name: "Case 2: Deployment rolling out",
+			dep: newDeployment(appsv1.DeploymentStatus{
+				ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 2,
+			}),
+			wantAvailable:   metav1.ConditionTrue,
+			wantProgressing: metav1.ConditionTrue,
+			wantDegraded:    metav1.ConditionFalse,
+		},
+		{
+			name: "Case 3: Deployment change not observed yet",
+			dep: newDeployment(appsv1.DeploymentStatus{
+				ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2,
+			}),
+			wantAvailable:   metav1.ConditionTrue,
+			wantProgressing: metav1.ConditionTrue,
+			wantDegraded:    metav1.ConditionFalse,
+		},
+		{
+			name: "Case 4: Deployment rolled out",
+			dep: newDeployment(appsv1.DeploymentStatus{
+				ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2,
+			}),
+			wantAvailable:   metav1.ConditionTrue,
+			wantProgressing: metav1.ConditionFalse,
+			wantDegraded:    metav1.ConditionFalse,
+		},
+		{
+			name: "Case 5: Deployment past its progress deadline",
+			dep: newDeployment(appsv1.DeploymentStatus{
+				ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2,
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+				},
+			}),
+			wantAvailable:   metav1.ConditionFalse,
+			wantProgressing: metav1.ConditionTrue,
+			wantDegraded:    metav1.ConditionTrue,
+			wantReason:      "ProgressDeadlineExceeded",
+		},
+		{
+			name: "Case 6: Deployment blocked",
+			conditions: []metav1.Condition{
+				{Type: typeNoDeployDevfileRegistry, Status: metav1.ConditionUnknown, Reason: "DeploymentBlocked"},
+			},
+			wantAvailable:   metav1.ConditionFalse,
+			wantProgressing: metav1.ConditionFalse,
+			wantDegraded:    metav1.ConditionTrue,
+			wantReason:      "DeploymentBlocked",
+		},
+		{
+			name: "Case 7: Git source failing",
+			conditions: []metav1.Condition{
+				{Type: typeGitSourceFailed, Status: metav1.ConditionTrue, Reason: "CloneFailed"},
+			},
+			dep: newDeployment(appsv1.DeploymentStatus{
+				ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2,
+			}),
+			wantAvailable:   metav1.ConditionTrue,
+			wantProgressing: metav1.ConditionFalse,
+			wantDegraded:    metav1.ConditionTrue,
+			wantReason:      "CloneFailed",
+		},
+		{
+			name: "Case 8: Registry server not ready in time",
+			conditions: []metav1.Condition{
+				{Type: typeServerNotReady, Status: metav1.ConditionTrue, Reason: "ReadinessTimeout"},
+			},
+			dep: newDeployment(appsv1.DeploymentStatus{
+				ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 1,
+			}),
+			wantAvailable:   metav1.ConditionTrue,
+			wantProgressing: metav1.ConditionFalse,
+			wantDegraded:    metav1.ConditionTrue,
+			wantReason:      "ReadinessTimeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{Status: registryv1alpha1.DevfileRegistryStatus{Conditions: tt.conditions}}
+			var conditions []metav1.Condition
+			for _, condition := range getRolloutConditions(cr, tt.dep) {
+				meta.SetStatusCondition(&conditions, condition)
+			}
+
+			for conditionType, want := range map[string]metav1.ConditionStatus{
+				typeAvailable:   tt.wantAvailable,
+				typeProgressing: tt.wantProgressing,
+				typeDegraded:    tt.wantDegraded,
+			} {
+				if condition := meta.FindStatusCondition(conditions, conditionType); condition == nil || condition.Status != want {
+					t.Errorf("getRolloutConditions() %s condition = %v, want status %v", conditionType, condition, want)
+				}
+			}
+			if degraded := meta.FindStatusCondition(conditions, typeDegraded); tt.wantReason != "" && degraded.Reason != tt.wantReason {
+				t.Errorf("getRolloutConditions() Degraded reason = %v, want %v", degraded.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestUpdateRolloutStatus(t *testing.T) {
+	scheme := newTestScheme()
+	headless := true
+
+	tests := []struct {
+		name           string
+		headless       *bool
+		readyContainer string
+		wantComponents []registryv1alpha1.DevfileRegistryComponentStatus
+	}{
+		{
+			name:           "Case 1: Registry viewer not ready",
+			readyContainer: indexContainerName,
+			wantComponents: []registryv1alpha1.DevfileRegistryComponentStatus{
+				{Name: "devfile-index", Image: registry.DefaultDevfileIndexImage, Ready: true},
+				{Name: "oci-registry", Image: registry.DefaultOCIRegistryImage},
+				{Name: "registry-viewer", Image: registry.DefaultRegistryViewerImage},
+			},
+		},
+		{
+			name:           "Case 2: Headless registry",
+			headless:       &headless,
+			readyContainer: ociContainerName,
+			wantComponents: []registryv1alpha1.DevfileRegistryComponentStatus{
+				{Name: "devfile-index", Image: registry.DefaultDevfileIndexImage},
+				{Name: "oci-registry", Image: registry.DefaultOCIRegistryImage, Ready: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", Generation: 4},
+				Spec:       registryv1alpha1.DevfileRegistrySpec{Headless: tt.headless},
+			}
+			labels := registry.LabelsForDevfileRegistry(cr)
+			dep, err := registry.GenerateDeployment(cr, config.Defaults{}, scheme, labels)
+			if err != nil {
+				t.Fatalf("GenerateDeployment() unexpected error: %v", err)
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: cr.Namespace, Labels: labels},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: indexContainerName, Ready: tt.readyContainer == indexContainerName},
+						{Name: ociContainerName, Ready: tt.readyContainer == ociContainerName},
+						{Name: viewerContainerName},
+					},
+				},
+			}
+			r := newTestReconciler(scheme, cr, dep, pod)
+
+			if err := r.updateRolloutStatus(context.TODO(), cr); err != nil {
+				t.Fatalf("updateRolloutStatus() unexpected error: %v", err)
+			}
+			if cr.Status.ObservedGeneration != cr.Generation {
+				t.Errorf("updateRolloutStatus() observed generation = %d, want %d", cr.Status.ObservedGeneration, cr.Generation)
+			}
+			if cr.Status.ExposureType != registry.ExposureTypeIngress {
+				t.Errorf("updateRolloutStatus() exposure type = %s, want %s", cr.Status.ExposureType, registry.ExposureTypeIngress)
+			}
+			if len(cr.Status.Components) != len(tt.wantComponents) {
+				t.Fatalf("updateRolloutStatus() components = %v, want %v", cr.Status.Components, tt.wantComponents)
+			}
+			for i := range tt.wantComponents {
+				if cr.Status.Components[i] != tt.wantComponents[i] {
+					t.Errorf("updateRolloutStatus() components = %v, want %v", cr.Status.Components, tt.wantComponents)
+				}
+			}
+			if !meta.IsStatusConditionTrue(cr.Status.Conditions, typeProgressing) {
+				t.Errorf("updateRolloutStatus() expected the Progressing condition while no pod is available")
+			}
+		})
+	}
+}
+
+func TestUpdateServerReadinessStatus(t *testing.T) {
+	scheme := newTestScheme()
+	cr := &registryv1alpha1.DevfileRegistry{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"}}
+	r := newTestReconciler(scheme, cr)
+
+	r.updateServerReadinessStatus(cr, "https://registry.example.com", fmt.Errorf("timed out"))
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, typeServerNotReady) {
+		t.Errorf("updateServerReadinessStatus() expected the %s condition after a readiness timeout", typeServerNotReady)
+	}
+	r.updateServerReadinessStatus(cr, "https://registry.example.com", nil)
+	if meta.FindStatusCondition(cr.Status.Conditions, typeServerNotReady) != nil {
+		t.Errorf("updateServerReadinessStatus() %s condition not removed once the server is ready", typeServerNotReady)
+	}
+}
+
+func TestPatchStatus(t *testing.T) {
+	scheme := newTestScheme()
+
+	tests := []struct {
+		name    string
+		update  func(cr *registryv1alpha1.DevfileRegistry)
+		stale   bool
+		wantErr bool
+		wantURL string
+	}{
+		{
+			name:    "Unchanged status not written",
+			update:  func(cr *registryv1alpha1.DevfileRegistry) {},
+			wantURL: "http://old.example.com",
+		},
+		{
+			name:    "Changed status written",
+			update:  func(cr *registryv1alpha1.DevfileRegistry) { cr.Status.URL = "http://new.example.com" },
+			wantURL: "http://new.example.com",
+		},
+		{
+			name:    "Status written meanwhile not overwritten",
+			update:  func(cr *registryv1alpha1.DevfileRegistry) { cr.Status.URL = "http://new.example.com" },
+			stale:   true,
+			wantErr: true,
+			wantURL: "http://old.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+				Status:     registryv1alpha1.DevfileRegistryStatus{URL: "http://old.example.com"},
+			}
+			r := newTestReconciler(scheme, cr)
+			if err := r.Get(context.TODO(), client.ObjectKeyFromObject(cr), cr); err != nil {
+				t.Fatalf("patchStatus() unexpected error getting the DevfileRegistry: %v", err)
+			}
+			original := cr.DeepCopy()
+			if tt.stale {
+				// The mirror syncer writes the status during the reconcile
+				latest := cr.DeepCopy()
+				latest.Status.MirroredStacks = 1
+				if err := r.Status().Update(context.TODO(), latest); err != nil {
+					t.Fatalf("patchStatus() unexpected error updating the status: %v", err)
+				}
+			}
+
+			tt.update(cr)
+			err := r.patchStatus(context.TODO(), original, cr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("patchStatus() error = %v, want an error: %v", err, tt.wantErr)
+			}
+
+			got := &registryv1alpha1.DevfileRegistry{}
+			if err := r.Get(context.TODO(), client.ObjectKeyFromObject(cr), got); err != nil {
+				t.Fatalf("patchStatus() unexpected error getting the DevfileRegistry: %v", err)
+			}
+			if got.Status.URL != tt.wantURL {
+				t.Errorf("patchStatus() URL = %s, want %s", got.Status.URL, tt.wantURL)
+			}
+		})
+	}
+}
@@ -20,29 +20,67 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
 	"github.com/devfile/registry-operator/pkg/registry"
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const viewerContainerName = "registry-viewer"
+const (
+	indexContainerName  = "devfile-registry"
+	ociContainerName    = "oci-registry"
+	viewerContainerName = "registry-viewer"
+)
 
 // updateDeployment ensures that a devfile registry deployment exists on the cluster and is up to date with the custom resource
 func (r *DevfileRegistryReconciler) updateDeployment(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, dep *appsv1.Deployment) error {
 	// Check to see if the existing devfile registry deployment needs to be updated
 	needsUpdating := false
+	original := dep.DeepCopy()
+
+	// A changed or removed pod template override can leave fields behind that the checks below do not cover,
+	// so start over from a newly generated pod template
+	if overrideHash := registry.GetPodTemplateOverrideHash(cr); dep.Annotations[registry.PodTemplateOverrideHashAnnotation] != overrideHash {
+		r.Log.Info("Pod template override changed, regenerating the DevfileRegistry pod template")
+		generated, err := registry.GenerateDeployment(cr, r.defaults, r.Scheme, dep.Spec.Selector.MatchLabels)
+		if err != nil {
+			return err
+		}
+		dep.Spec.Template = generated.Spec.Template
+		if overrideHash == "" {
+			delete(dep.Annotations, registry.PodTemplateOverrideHashAnnotation)
+		} else {
+			if dep.Annotations == nil {
+				dep.Annotations = map[string]string{}
+			}
+			dep.Annotations[registry.PodTemplateOverrideHashAnnotation] = overrideHash
+		}
+		needsUpdating = true
+	}
 
-	indexImage := registry.GetDevfileIndexImage(cr)
-	indexImageContainer := dep.Spec.Template.Spec.Containers[0]
+	// The pod template override can reorder containers, environment variables and volumes, so look them up by name
+	indexImageContainer := findContainer(dep.Spec.Template.Spec.Containers, indexContainerName)
+	ociImageContainer := findContainer(dep.Spec.Template.Spec.Containers, ociContainerName)
+	if indexImageContainer == nil || ociImageContainer == nil {
+		return fmt.Errorf("deployment %s is missing the %s or %s container", dep.Name, indexContainerName, ociContainerName)
+	}
+
+	indexImage := registry.GetDevfileIndexImage(cr, r.defaults)
 	if indexImageContainer.Image != indexImage {
 		indexImageContainer.Image = indexImage
 		needsUpdating = true
@@ -50,34 +88,103 @@ func (r *DevfileRegistryReconciler) updateDeployment(ctx context.Context, cr *re
 		//check Telemetry config to see updates are needed
 		registryName := cr.Spec.Telemetry.RegistryName
 		registryKey := cr.Spec.Telemetry.Key
-		if indexImageContainer.Env[0].Value != registryName {
-			indexImageContainer.Env[0].Value = registryName
+		if updateEnvValue(&indexImageContainer.Env, "REGISTRY_NAME", registryName) {
 			needsUpdating = true
 		}
 
-		if indexImageContainer.Env[1].Value != registryKey {
-			indexImageContainer.Env[1].Value = registryKey
+		if updateEnvValue(&indexImageContainer.Env, "TELEMETRY_KEY", registryKey) {
 			needsUpdating = true
 		}
 
-		if indexImagePullPolicy := registry.GetDevfileIndexImagePullPolicy(cr); indexImageContainer.ImagePullPolicy != indexImagePullPolicy {
+		if indexImagePullPolicy := registry.GetDevfileIndexImagePullPolicy(cr, r.defaults); indexImageContainer.ImagePullPolicy != indexImagePullPolicy {
 			indexImageContainer.ImagePullPolicy = indexImagePullPolicy
 			needsUpdating = true
 		}
 	}
 
-	ociImage := registry.GetOCIRegistryImage(cr)
-	ociImageContainer := dep.Spec.Template.Spec.Containers[1]
+	if indexResources := registry.GetDevfileIndexResources(cr); !equality.Semantic.DeepEqual(indexImageContainer.Resources, indexResources) {
+		indexImageContainer.Resources = indexResources
+		needsUpdating = true
+	}
+
+	if updateContainerProbes(indexImageContainer, registry.GetDevfileIndexLivenessProbe(cr),
+		registry.GetDevfileIndexReadinessProbe(cr), registry.GetDevfileIndexStartupProbe(cr)) {
+		needsUpdating = true
+	}
+
+	ociImage := registry.GetOCIRegistryImage(cr, r.defaults)
 	if ociImageContainer.Image != ociImage {
 		ociImageContainer.Image = ociImage
 		needsUpdating = true
 	} else {
-		if ociImagePullPolicy := registry.GetOCIRegistryImagePullPolicy(cr); ociImageContainer.ImagePullPolicy != ociImagePullPolicy {
+		if ociImagePullPolicy := registry.GetOCIRegistryImagePullPolicy(cr, r.defaults); ociImageContainer.ImagePullPolicy != ociImagePullPolicy {
 			ociImageContainer.ImagePullPolicy = ociImagePullPolicy
 			needsUpdating = true
 		}
 	}
 
+	if ociResources := registry.GetOCIRegistryResources(cr); !equality.Semantic.DeepEqual(ociImageContainer.Resources, ociResources) {
+		ociImageContainer.Resources = ociResources
+		needsUpdating = true
+	}
+
+	if updateContainerProbes(ociImageContainer, registry.GetOCIRegistryLivenessProbe(cr),
+		registry.GetOCIRegistryReadinessProbe(cr), registry.GetOCIRegistryStartupProbe(cr)) {
+		needsUpdating = true
+	}
+
+	if ociEnv := registry.GetOCIRegistryStorageEnv(cr); !equality.Semantic.DeepEqual(ociImageContainer.Env, ociEnv) {
+		ociImageContainer.Env = ociEnv
+		needsUpdating = true
+	}
+
+	if updateOCIRegistryAuthVolume(cr, &dep.Spec.Template.Spec, ociImageContainer) {
+		needsUpdating = true
+	}
+
+	if updateOCIRegistryClientAuth(cr, &dep.Spec.Template.Spec, indexImageContainer) {
+		needsUpdating = true
+	}
+
+	if updateGitSource(cr, r.defaults, &dep.Spec.Template, indexImageContainer) {
+		needsUpdating = true
+	}
+
+	if updateMirrorSource(cr, &dep.Spec.Template.Spec, indexImageContainer) {
+		needsUpdating = true
+	}
+
+	if replicas := registry.GetReplicas(cr); dep.Spec.Replicas == nil || *dep.Spec.Replicas != replicas {
+		dep.Spec.Replicas = &replicas
+		needsUpdating = true
+	}
+
+	if updatePodScheduling(cr, &dep.Spec.Template.Spec) {
+		needsUpdating = true
+	}
+
+	if registry.UpdateUserMetadata(&dep.Spec.Template.ObjectMeta, registry.GetUserLabels(cr, dep.Spec.Selector.MatchLabels), registry.GetPodAnnotations(cr)) {
+		needsUpdating = true
+	}
+
+	// The OCI registry only reads its configuration on startup, roll out the pods when it changes
+	registryConfig, err := r.getValidRegistryConfig(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if registry.SetRegistryConfigHash(&dep.Spec.Template, registryConfig) {
+		needsUpdating = true
+	}
+
+	// The OCI registry only reads its htpasswd file on startup, roll out the pods when it changes
+	htpasswd, err := r.getOCIRegistryHtpasswd(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if registry.SetOCIRegistryHtpasswdHash(&dep.Spec.Template, htpasswd) {
+		needsUpdating = true
+	}
+
 	updated, err := r.updateDeploymentForHeadlessChange(cr, dep)
 	if err != nil {
 		return err
@@ -86,35 +193,34 @@ func (r *DevfileRegistryReconciler) updateDeployment(ctx context.Context, cr *re
 		needsUpdating = true
 	}
 
-	if registry.IsStorageEnabled(cr) {
-		if dep.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim == nil {
-			dep.Spec.Template.Spec.Volumes[0].VolumeSource = registry.GetDevfileRegistryVolumeSource(cr)
-			needsUpdating = true
-		}
-	} else {
-		if dep.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim != nil {
-			dep.Spec.Template.Spec.Volumes[0].VolumeSource = registry.GetDevfileRegistryVolumeSource(cr)
-			needsUpdating = true
+	if storageVolume := findVolume(dep.Spec.Template.Spec.Volumes, registry.DevfileRegistryVolumeName); storageVolume != nil {
+		if registry.IsStorageEnabled(cr) {
+			if storageVolume.PersistentVolumeClaim == nil {
+				storageVolume.VolumeSource = registry.GetDevfileRegistryVolumeSource(cr)
+				needsUpdating = true
+			}
+		} else {
+			if storageVolume.PersistentVolumeClaim != nil {
+				storageVolume.VolumeSource = registry.GetDevfileRegistryVolumeSource(cr)
+				needsUpdating = true
+			}
 		}
 	}
 
-	if len(dep.Spec.Template.Spec.Containers) > 2 {
-		viewerImage := registry.GetRegistryViewerImage(cr)
-		viewerImageContainer := dep.Spec.Template.Spec.Containers[2]
+	if viewerImageContainer := findContainer(dep.Spec.Template.Spec.Containers, viewerContainerName); viewerImageContainer != nil {
+		viewerImage := registry.GetRegistryViewerImage(cr, r.defaults)
 
 		//determine if the NEXT_PUBLIC_ANALYTICS_WRITE_KEY env needs updating
 		viewerKey := cr.Spec.Telemetry.RegistryViewerWriteKey
-		if viewerImageContainer.Env[0].Value != viewerKey {
+		if updateEnvValue(&viewerImageContainer.Env, "NEXT_PUBLIC_ANALYTICS_WRITE_KEY", viewerKey) {
 			r.Log.Info("Updating NEXT_PUBLIC_ANALYTICS_WRITE_KEY ", "value", viewerKey)
-			viewerImageContainer.Env[0].Value = viewerKey
 			needsUpdating = true
 		}
 
 		//determine if the DEVFILE_REGISTRIES env needs updating.  This will only occur on initial deployment since object name is unique
 		newDRValue := fmt.Sprintf(`[{"name": "%s","url": "http://localhost:8080","fqdn": "%s"}]`, cr.ObjectMeta.Name, cr.Status.URL)
-		if viewerImageContainer.Env[1].Value != newDRValue {
+		if updateEnvValue(&viewerImageContainer.Env, "DEVFILE_REGISTRIES", newDRValue) {
 			r.Log.Info("Updating DEVFILE_REGISTRIES ", "value", newDRValue)
-			viewerImageContainer.Env[1].Value = newDRValue
 			needsUpdating = true
 		}
 
@@ -122,11 +228,31 @@ func (r *DevfileRegistryReconciler) updateDeployment(ctx context.Context, cr *re
 			viewerImageContainer.Image = viewerImage
 			needsUpdating = true
 		} else {
-			if viewerImagePullPolicy := registry.GetRegistryViewerImagePullPolicy(cr); viewerImageContainer.ImagePullPolicy != viewerImagePullPolicy {
+			if viewerImagePullPolicy := registry.GetRegistryViewerImagePullPolicy(cr, r.defaults); viewerImageContainer.ImagePullPolicy != viewerImagePullPolicy {
 				viewerImageContainer.ImagePullPolicy = viewerImagePullPolicy
 				needsUpdating = true
 			}
 		}
+
+		if viewerResources := registry.GetRegistryViewerResources(cr); !equality.Semantic.DeepEqual(viewerImageContainer.Resources, viewerResources) {
+			viewerImageContainer.Resources = viewerResources
+			needsUpdating = true
+		}
+
+		if updateContainerProbes(viewerImageContainer, registry.GetRegistryViewerLivenessProbe(cr),
+			registry.GetRegistryViewerReadinessProbe(cr), registry.GetRegistryViewerStartupProbe(cr)) {
+			needsUpdating = true
+		}
+	}
+
+	// Reapply the pod template override, as the checks above revert the fields it sets. Only the final result
+	// tells whether the deployment actually changed.
+	if cr.Spec.PodTemplateOverride != nil {
+		if err := registry.ApplyPodTemplateOverride(cr, &dep.Spec.Template, dep.Spec.Selector.MatchLabels); err != nil {
+			return err
+		}
+		needsUpdating = !equality.Semantic.DeepEqual(original.Spec, dep.Spec) ||
+			!equality.Semantic.DeepEqual(original.Annotations, dep.Annotations)
 	}
 
 	if needsUpdating {
@@ -136,12 +262,579 @@ func (r *DevfileRegistryReconciler) updateDeployment(ctx context.Context, cr *re
 	return nil
 }
 
+// updateConfigMap ensures that the data of the devfile registry config map is up to date with the custom resource
+func (r *DevfileRegistryReconciler) updateConfigMap(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, cm *corev1.ConfigMap) error {
+	registryConfig, err := r.getValidRegistryConfig(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if data := registry.GetRegistryConfigMapData(cr, registryConfig); !equality.Semantic.DeepEqual(cm.Data, data) {
+		cm.Data = data
+		r.Log.Info("Updating the DevfileRegistry config map")
+		return r.Update(ctx, cm)
+	}
+	return nil
+}
+
+// updateOCIRegistryAuthSecret ensures that the htpasswd file of the OCI registry held by the generated secret is up to
+// date with the htpasswd secret of the user, the credential of the secret being kept
+func (r *DevfileRegistryReconciler) updateOCIRegistryAuthSecret(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, secret *corev1.Secret) error {
+	userHtpasswd, err := r.getUserHtpasswd(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if registry.UpdateOCIRegistryAuthSecretData(cr, secret, userHtpasswd) {
+		r.Log.Info("Updating the OCI registry auth secret")
+		return r.Update(ctx, secret)
+	}
+	return nil
+}
+
+// getUserHtpasswd returns the htpasswd file of the htpasswd secret of the user, or an empty string if none is set
+func (r *DevfileRegistryReconciler) getUserHtpasswd(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (string, error) {
+	secretName := registry.GetUserHtpasswdSecretName(cr)
+	if secretName == "" {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	err := r.getUserSecret(ctx, types.NamespacedName{Name: secretName, Namespace: cr.Namespace}, secret)
+	if errors.IsNotFound(err) {
+		return "", fmt.Errorf("htpasswd secret %s not found", secretName)
+	} else if err != nil {
+		return "", err
+	}
+	htpasswd, found := secret.Data[registry.OCIRegistryHtpasswdKey]
+	if !found {
+		return "", fmt.Errorf("htpasswd secret %s has no %s key", secretName, registry.OCIRegistryHtpasswdKey)
+	}
+	return string(htpasswd), nil
+}
+
+// getOCIRegistryHtpasswd returns the htpasswd file served by the OCI registry, or an empty string if authentication
+// is disabled or the generated secret holding it is not created yet
+func (r *DevfileRegistryReconciler) getOCIRegistryHtpasswd(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (string, error) {
+	if !registry.IsOCIRegistryAuthEnabled(cr) {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.GetOCIRegistryAuthSecretName(cr), Namespace: cr.Namespace}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	return string(secret.Data[registry.OCIRegistryHtpasswdKey]), nil
+}
+
+// updateGarbageCollectionCronJob ensures that the CronJob collecting the garbage of the OCI registry storage is up to
+// date with the custom resource
+func (r *DevfileRegistryReconciler) updateGarbageCollectionCronJob(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, cronJob *batchv1.CronJob) error {
+	needsUpdating := false
+	desired := registry.GenerateGarbageCollectionCronJob(cr, r.defaults, r.Scheme, registry.LabelsForDevfileRegistry(cr))
+
+	if cronJob.Spec.Schedule != desired.Spec.Schedule {
+		cronJob.Spec.Schedule = desired.Spec.Schedule
+		needsUpdating = true
+	}
+
+	podSpec := &cronJob.Spec.JobTemplate.Spec.Template.Spec
+	desiredPodSpec := &desired.Spec.JobTemplate.Spec.Template.Spec
+	if len(podSpec.Containers) != 1 {
+		podSpec.Containers = desiredPodSpec.Containers
+		needsUpdating = true
+	}
+	container := &podSpec.Containers[0]
+	desiredContainer := &desiredPodSpec.Containers[0]
+	if container.Image != desiredContainer.Image || container.ImagePullPolicy != desiredContainer.ImagePullPolicy {
+		container.Image = desiredContainer.Image
+		container.ImagePullPolicy = desiredContainer.ImagePullPolicy
+		needsUpdating = true
+	}
+	if !equality.Semantic.DeepEqual(container.Args, desiredContainer.Args) {
+		container.Args = desiredContainer.Args
+		needsUpdating = true
+	}
+	if !equality.Semantic.DeepEqual(container.Env, desiredContainer.Env) {
+		container.Env = desiredContainer.Env
+		needsUpdating = true
+	}
+	if !equality.Semantic.DeepEqual(container.Resources, desiredContainer.Resources) {
+		container.Resources = desiredContainer.Resources
+		needsUpdating = true
+	}
+
+	if !equality.Semantic.DeepEqual(podSpec.NodeSelector, desiredPodSpec.NodeSelector) ||
+		!equality.Semantic.DeepEqual(podSpec.Tolerations, desiredPodSpec.Tolerations) ||
+		!equality.Semantic.DeepEqual(podSpec.Affinity, desiredPodSpec.Affinity) ||
+		podSpec.PriorityClassName != desiredPodSpec.PriorityClassName {
+		podSpec.NodeSelector = desiredPodSpec.NodeSelector
+		podSpec.Tolerations = desiredPodSpec.Tolerations
+		podSpec.Affinity = desiredPodSpec.Affinity
+		podSpec.PriorityClassName = desiredPodSpec.PriorityClassName
+		needsUpdating = true
+	}
+	if !equality.Semantic.DeepEqual(podSpec.SecurityContext, desiredPodSpec.SecurityContext) {
+		podSpec.SecurityContext = desiredPodSpec.SecurityContext
+		needsUpdating = true
+	}
+
+	// The storage can move between a persistent volume and a bucket, which only needs an empty volume
+	if storageVolume := findVolume(podSpec.Volumes, registry.DevfileRegistryVolumeName); storageVolume != nil {
+		if (storageVolume.PersistentVolumeClaim != nil) != registry.IsStorageEnabled(cr) {
+			storageVolume.VolumeSource = registry.GetDevfileRegistryVolumeSource(cr)
+			needsUpdating = true
+		}
+	}
+
+	if registry.UpdateUserMetadata(&cronJob.Spec.JobTemplate.Spec.Template.ObjectMeta,
+		registry.GetUserLabels(cr, registry.GetGarbageCollectionPodLabels(cr)), registry.GetPodAnnotations(cr)) {
+		needsUpdating = true
+	}
+
+	if needsUpdating {
+		r.Log.Info("Updating the DevfileRegistry garbage collection CronJob")
+		return r.Update(ctx, cronJob)
+	}
+	return nil
+}
+
+// getRegistryConfig returns the OCI registry configuration of the DevfileRegistry, with the configuration of the
+// referenced config map merged over the default one. If the referenced configuration is missing or invalid, a
+// condition telling why is returned instead.
+func (r *DevfileRegistryReconciler) getRegistryConfig(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (string, *metav1.Condition, error) {
+	defaultConfig := registry.GetDefaultRegistryConfig(cr)
+	ref := cr.Spec.OciRegistry.Config
+	if ref == nil {
+		return defaultConfig, nil, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := r.getUserConfigMap(ctx, types.NamespacedName{Name: ref.Name, Namespace: cr.Namespace}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", nil, err
+	}
+	userConfig, found := cm.Data[ref.Key]
+	if err != nil || !found {
+		if ref.Optional != nil && *ref.Optional {
+			return defaultConfig, nil, nil
+		}
+		return "", &metav1.Condition{
+			Reason:  "ConfigMapNotFound",
+			Message: fmt.Sprintf(registryv1alpha1.MissingConfig, ref.Name, ref.Key),
+		}, nil
+	}
+
+	registryConfig, err := registry.MergeRegistryConfig(defaultConfig, userConfig)
+	if err == nil {
+		err = registryv1alpha1.IsRegistryConfigValid(registryConfig)
+	}
+	if err != nil {
+		return "", &metav1.Condition{
+			Reason:  "InvalidConfig",
+			Message: fmt.Sprintf(registryv1alpha1.InvalidConfig, err),
+		}, nil
+	}
+	return registryConfig, nil, nil
+}
+
+// getValidRegistryConfig returns the OCI registry configuration of the DevfileRegistry, or an error if the
+// referenced configuration is missing or invalid
+func (r *DevfileRegistryReconciler) getValidRegistryConfig(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (string, error) {
+	registryConfig, invalid, err := r.getRegistryConfig(ctx, cr)
+	if err == nil && invalid != nil {
+		err = fmt.Errorf("%s", invalid.Message)
+	}
+	return registryConfig, err
+}
+
+// getPodTemplateOverrideError returns a condition telling why the pod template override of the DevfileRegistry
+// cannot be applied to the registry deployment, or nil if it can
+func getPodTemplateOverrideError(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, scheme *runtime.Scheme, labels map[string]string) *metav1.Condition {
+	if _, err := registry.GenerateDeployment(cr, defaults, scheme, labels); err != nil {
+		return &metav1.Condition{
+			Reason:  "InvalidPodTemplateOverride",
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
+// updateContainerProbes sets the probes of the container, returns true if any of them changed
+func updateContainerProbes(container *corev1.Container, livenessProbe *corev1.Probe, readinessProbe *corev1.Probe, startupProbe *corev1.Probe) bool {
+	updated := false
+
+	if !equality.Semantic.DeepEqual(container.LivenessProbe, livenessProbe) {
+		container.LivenessProbe = livenessProbe
+		updated = true
+	}
+
+	if !equality.Semantic.DeepEqual(container.ReadinessProbe, readinessProbe) {
+		container.ReadinessProbe = readinessProbe
+		updated = true
+	}
+
+	if !equality.Semantic.DeepEqual(container.StartupProbe, startupProbe) {
+		container.StartupProbe = startupProbe
+		updated = true
+	}
+
+	return updated
+}
+
+// updateOCIRegistryAuthVolume mounts the htpasswd file of the OCI registry in the OCI registry container if
+// authentication is enabled and unmounts it otherwise, returns true if the pod spec changed
+func updateOCIRegistryAuthVolume(cr *registryv1alpha1.DevfileRegistry, podSpec *corev1.PodSpec, ociContainer *corev1.Container) bool {
+	return updateSecretVolume(podSpec, ociContainer, registry.OCIRegistryAuthVolumeName,
+		registry.GetOCIRegistryAuthVolume(cr), registry.GetOCIRegistryAuthVolumeMount())
+}
+
+// updateOCIRegistryClientAuth mounts the Docker config file with the credential of the devfile index server in the
+// devfile index container if authentication is enabled and unmounts it otherwise, returns true if the pod spec changed
+func updateOCIRegistryClientAuth(cr *registryv1alpha1.DevfileRegistry, podSpec *corev1.PodSpec, indexContainer *corev1.Container) bool {
+	authVolume := registry.GetOCIRegistryClientAuthVolume(cr)
+	updated := updateSecretVolume(podSpec, indexContainer, registry.OCIRegistryClientAuthVolumeName,
+		authVolume, registry.GetOCIRegistryClientAuthVolumeMount())
+
+	envVar := registry.GetOCIRegistryClientAuthEnv()
+	if authVolume == nil {
+		return removeEnvVar(&indexContainer.Env, envVar) || updated
+	}
+	for _, e := range indexContainer.Env {
+		if e.Name == envVar.Name && e.Value == envVar.Value {
+			return updated
+		}
+	}
+	indexContainer.Env = append(indexContainer.Env, envVar)
+	return true
+}
+
+// updateSecretVolume adds the given volume to the pod spec and mounts it in the container, or removes it if nil,
+// returns true if the pod spec changed
+func updateSecretVolume(podSpec *corev1.PodSpec, container *corev1.Container, name string, desired *corev1.Volume, desiredMount corev1.VolumeMount) bool {
+	updated := false
+
+	volume := findVolume(podSpec.Volumes, name)
+	if desired == nil && volume != nil {
+		volumes := []corev1.Volume{}
+		for _, v := range podSpec.Volumes {
+			if v.Name != name {
+				volumes = append(volumes, v)
+			}
+		}
+		podSpec.Volumes = volumes
+		updated = true
+	} else if desired != nil && volume == nil {
+		podSpec.Volumes = append(podSpec.Volumes, *desired)
+		updated = true
+	} else if desired != nil && !equality.Semantic.DeepEqual(volume.VolumeSource, desired.VolumeSource) {
+		volume.VolumeSource = desired.VolumeSource
+		updated = true
+	}
+
+	mounted := false
+	mounts := []corev1.VolumeMount{}
+	for _, mount := range container.VolumeMounts {
+		if mount.Name == name {
+			mounted = true
+			if desired == nil {
+				updated = true
+				continue
+			}
+		}
+		mounts = append(mounts, mount)
+	}
+	if desired != nil && !mounted {
+		mounts = append(mounts, desiredMount)
+		updated = true
+	}
+	if updated {
+		container.VolumeMounts = mounts
+	}
+
+	return updated
+}
+
+// updateGitSource adds the init container building the registry index from the Git source and points the devfile
+// index container to the built index if the registry has a Git source, and removes them otherwise. The pods are
+// rolled out when the commit of the Git source changes. Returns true if the pod template changed.
+func updateGitSource(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, template *corev1.PodTemplateSpec, indexContainer *corev1.Container) bool {
+	updated := false
+	enabled := registry.IsGitSourceEnabled(cr)
+	podSpec := &template.Spec
+
+	builder := findContainer(podSpec.InitContainers, registry.IndexBuilderContainerName)
+	switch {
+	case enabled && builder == nil:
+		podSpec.InitContainers = append(podSpec.InitContainers, registry.GenerateIndexBuilderContainer(cr, defaults))
+		updated = true
+	case enabled:
+		desired := registry.GenerateIndexBuilderContainer(cr, defaults)
+		if builder.Image != desired.Image || !equality.Semantic.DeepEqual(builder.Command, desired.Command) ||
+			!equality.Semantic.DeepEqual(builder.Env, desired.Env) || !equality.Semantic.DeepEqual(builder.Resources, desired.Resources) {
+			builder.Image = desired.Image
+			builder.Command = desired.Command
+			builder.Env = desired.Env
+			builder.Resources = desired.Resources
+			updated = true
+		}
+	case builder != nil:
+		initContainers := []corev1.Container{}
+		for _, c := range podSpec.InitContainers {
+			if c.Name != registry.IndexBuilderContainerName {
+				initContainers = append(initContainers, c)
+			}
+		}
+		podSpec.InitContainers = initContainers
+		updated = true
+	}
+
+	volume := findVolume(podSpec.Volumes, registry.SourceVolumeName)
+	if enabled && volume == nil {
+		podSpec.Volumes = append(podSpec.Volumes, registry.GetSourceVolume())
+		updated = true
+	} else if !enabled && volume != nil {
+		volumes := []corev1.Volume{}
+		for _, v := range podSpec.Volumes {
+			if v.Name != registry.SourceVolumeName {
+				volumes = append(volumes, v)
+			}
+		}
+		podSpec.Volumes = volumes
+		updated = true
+	}
+
+	mounted := false
+	mounts := []corev1.VolumeMount{}
+	for _, mount := range indexContainer.VolumeMounts {
+		if mount.Name == registry.SourceVolumeName {
+			mounted = true
+			continue
+		}
+		mounts = append(mounts, mount)
+	}
+	if enabled && !mounted {
+		indexContainer.VolumeMounts = append(indexContainer.VolumeMounts, registry.GetSourceVolumeMount())
+		updated = true
+	} else if !enabled && mounted {
+		indexContainer.VolumeMounts = mounts
+		updated = true
+	}
+
+	for _, envVar := range registry.GetSourceIndexEnv() {
+		if enabled {
+			if updateEnvValue(&indexContainer.Env, envVar.Name, envVar.Value) {
+				updated = true
+			}
+			continue
+		}
+		if removeEnvVar(&indexContainer.Env, envVar) {
+			updated = true
+		}
+	}
+
+	commit := ""
+	if enabled {
+		commit = cr.Status.SourceCommit
+	}
+	if registry.SetSourceCommit(template, commit) {
+		updated = true
+	}
+
+	return updated
+}
+
+// updateMirrorSource mounts the index of the stacks mirrored from the mirrored devfile registry in the devfile index
+// container and points the devfile index server to it, or removes them if the registry mirrors no devfile registry.
+// Returns true if the pod spec was updated.
+func updateMirrorSource(cr *registryv1alpha1.DevfileRegistry, podSpec *corev1.PodSpec, indexContainer *corev1.Container) bool {
+	updated := false
+	enabled := registry.IsMirrorEnabled(cr)
+
+	volume := findVolume(podSpec.Volumes, registry.MirrorVolumeName)
+	if desired := registry.GetMirrorVolume(cr); enabled && volume == nil {
+		podSpec.Volumes = append(podSpec.Volumes, desired)
+		updated = true
+	} else if enabled && !equality.Semantic.DeepEqual(volume.VolumeSource, desired.VolumeSource) {
+		volume.VolumeSource = desired.VolumeSource
+		updated = true
+	} else if !enabled && volume != nil {
+		volumes := []corev1.Volume{}
+		for _, v := range podSpec.Volumes {
+			if v.Name != registry.MirrorVolumeName {
+				volumes = append(volumes, v)
+			}
+		}
+		podSpec.Volumes = volumes
+		updated = true
+	}
+
+	mounted := false
+	mounts := []corev1.VolumeMount{}
+	for _, mount := range indexContainer.VolumeMounts {
+		if mount.Name == registry.MirrorVolumeName {
+			mounted = true
+			continue
+		}
+		mounts = append(mounts, mount)
+	}
+	if enabled && !mounted {
+		indexContainer.VolumeMounts = append(indexContainer.VolumeMounts, registry.GetMirrorVolumeMount())
+		updated = true
+	} else if !enabled && mounted {
+		indexContainer.VolumeMounts = mounts
+		updated = true
+	}
+
+	for _, envVar := range registry.GetMirrorIndexEnv() {
+		if enabled {
+			if updateEnvValue(&indexContainer.Env, envVar.Name, envVar.Value) {
+				updated = true
+			}
+		} else if removeEnvVar(&indexContainer.Env, envVar) {
+			updated = true
+		}
+	}
+
+	return updated
+}
+
+// removeEnvVar removes the given environment variable if it is set to the given value, leaving it if another
+// source of the registry index set it. Returns true if it was removed.
+func removeEnvVar(envVars *[]corev1.EnvVar, envVar corev1.EnvVar) bool {
+	kept := []corev1.EnvVar{}
+	for _, e := range *envVars {
+		if e.Name != envVar.Name || e.Value != envVar.Value {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(*envVars) {
+		return false
+	}
+	*envVars = kept
+	return true
+}
+
+// findContainer returns the container with the given name, or nil if there is none
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// findVolume returns the volume with the given name, or nil if there is none
+func findVolume(volumes []corev1.Volume, name string) *corev1.Volume {
+	for i := range volumes {
+		if volumes[i].Name == name {
+			return &volumes[i]
+		}
+	}
+	return nil
+}
+
+// updateEnvValue sets the value of the environment variable with the given name, adding it if missing.
+// Returns true if the environment variables changed.
+func updateEnvValue(envVars *[]corev1.EnvVar, name string, value string) bool {
+	for i := range *envVars {
+		if (*envVars)[i].Name == name {
+			if (*envVars)[i].Value == value && (*envVars)[i].ValueFrom == nil {
+				return false
+			}
+			(*envVars)[i].Value = value
+			(*envVars)[i].ValueFrom = nil
+			return true
+		}
+	}
+	*envVars = append(*envVars, corev1.EnvVar{Name: name, Value: value})
+	return true
+}
+
+// updatePodScheduling updates the scheduling constraints of the registry pods to match the custom resource,
+// returns true if any of them changed
+func updatePodScheduling(cr *registryv1alpha1.DevfileRegistry, podSpec *corev1.PodSpec) bool {
+	updated := false
+
+	if nodeSelector := registry.GetNodeSelector(cr); !equality.Semantic.DeepEqual(podSpec.NodeSelector, nodeSelector) {
+		podSpec.NodeSelector = nodeSelector
+		updated = true
+	}
+
+	if tolerations := registry.GetTolerations(cr); !equality.Semantic.DeepEqual(podSpec.Tolerations, tolerations) {
+		podSpec.Tolerations = tolerations
+		updated = true
+	}
+
+	if affinity := registry.GetAffinity(cr); !equality.Semantic.DeepEqual(podSpec.Affinity, affinity) {
+		podSpec.Affinity = affinity
+		updated = true
+	}
+
+	if constraints := registry.GetTopologySpreadConstraints(cr); !equality.Semantic.DeepEqual(podSpec.TopologySpreadConstraints, constraints) {
+		podSpec.TopologySpreadConstraints = constraints
+		updated = true
+	}
+
+	if priorityClassName := registry.GetPriorityClassName(cr); podSpec.PriorityClassName != priorityClassName {
+		podSpec.PriorityClassName = priorityClassName
+		updated = true
+	}
+
+	return updated
+}
+
+// updateService checks to see if the type or the ports of the devfile registry service need to be updated
+func (r *DevfileRegistryReconciler) updateService(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, svc *corev1.Service) error {
+	serviceType := registry.GetServiceType(cr)
+	ports := registry.GetServicePorts(cr)
+	if svc.Spec.Type == serviceType && servicePortsEqual(svc.Spec.Ports, ports) {
+		return nil
+	}
+
+	// Keep the node ports allocated to the existing ports, node ports being only allowed on NodePort and
+	// LoadBalancer services
+	if serviceType != corev1.ServiceTypeClusterIP {
+		nodePorts := map[string]int32{}
+		for _, port := range svc.Spec.Ports {
+			nodePorts[port.Name] = port.NodePort
+		}
+		for i := range ports {
+			ports[i].NodePort = nodePorts[ports[i].Name]
+		}
+	}
+
+	svc.Spec.Type = serviceType
+	svc.Spec.Ports = ports
+	return r.Update(ctx, svc)
+}
+
+// servicePortsEqual returns true if both lists have the same port names and numbers, ignoring the fields defaulted
+// by the cluster
+func servicePortsEqual(current []corev1.ServicePort, desired []corev1.ServicePort) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for i := range current {
+		if current[i].Name != desired[i].Name || current[i].Port != desired[i].Port {
+			return false
+		}
+	}
+	return true
+}
+
 // updateRoute checks to see if any of the fields in an existing devfile index route needs updating
 func (r *DevfileRegistryReconciler) updateRoute(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, route *routev1.Route) error {
 	needsUpdating := false
 
+	// Check to see if the ingress domain was updated
+	if registry.GetIngressDomain(cr, r.defaults) != "" {
+		if host := registry.GetDevfileRegistryIngress(cr, r.defaults); route.Spec.Host != host {
+			route.Spec.Host = host
+			needsUpdating = true
+		}
+	}
+
 	// Check to see if TLS fields were updated
-	if registry.IsTLSEnabled(cr) {
+	if registry.IsTLSEnabled(cr, r.defaults) {
 		if route.Spec.TLS == nil {
 			route.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
 			needsUpdating = true
@@ -163,7 +856,7 @@ func (r *DevfileRegistryReconciler) updateRoute(ctx context.Context, cr *registr
 func (r *DevfileRegistryReconciler) updateIngress(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, hostname string, ingress *networkingv1.Ingress) error {
 	needsUpdating := false
 	// Check to see if TLS fields were updated
-	if registry.IsTLSEnabled(cr) {
+	if registry.IsTLSEnabled(cr, r.defaults) {
 		if len(ingress.Spec.TLS) == 0 {
 			// TLS was toggled on, so enable it in the ingress spec
 			ingress.Spec.TLS = []networkingv1.IngressTLS{
@@ -187,12 +880,18 @@ func (r *DevfileRegistryReconciler) updateIngress(ctx context.Context, cr *regis
 		}
 	}
 
+	// Check to see if the ingress class was updated
+	if ingressClass := registry.GetK8sIngressClass(cr, r.defaults); ingress.Spec.IngressClassName == nil || *ingress.Spec.IngressClassName != ingressClass {
+		ingress.Spec.IngressClassName = &ingressClass
+		needsUpdating = true
+	}
+
 	// Check to see if the ingress domain was updated
 	if ingress.Spec.Rules[0].Host != hostname {
 		ingress.Spec.Rules[0].Host = hostname
 
 		// If TLS is enabled, need to update the hostname there too
-		if registry.IsTLSEnabled(cr) {
+		if registry.IsTLSEnabled(cr, r.defaults) {
 			ingress.Spec.TLS[0].Hosts = []string{hostname}
 		}
 		needsUpdating = true
@@ -205,6 +904,75 @@ func (r *DevfileRegistryReconciler) updateIngress(ctx context.Context, cr *regis
 	return nil
 }
 
+// updatePVC resizes the devfile registry PVC if a larger volume size is requested and its storage class allows volume
+// expansion. Shrink attempts and storage classes not allowing expansion are reported with a status condition instead.
+func (r *DevfileRegistryReconciler) updatePVC(ctx context.Context, cr *registryv1alpha1.DevfileRegistry, pvc *corev1.PersistentVolumeClaim) error {
+	size, err := resource.ParseQuantity(registry.GetDevfileRegistryVolumeSize(cr))
+	if err != nil {
+		return fmt.Errorf("invalid registry volume size: %w", err)
+	}
+
+	var blocked *metav1.Condition
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch size.Cmp(current) {
+	case -1:
+		blocked = &metav1.Condition{
+			Reason:  "ShrinkNotSupported",
+			Message: fmt.Sprintf("Requested volume size %s is smaller than the current size %s, persistent volumes cannot be shrunk", size.String(), current.String()),
+		}
+	case 1:
+		expandable, err := r.isStorageClassExpandable(ctx, pvc.Spec.StorageClassName)
+		if err != nil {
+			return err
+		}
+		if !expandable {
+			blocked = &metav1.Condition{
+				Reason:  "StorageClassNotExpandable",
+				Message: fmt.Sprintf("Cannot resize the volume to %s, its storage class does not allow volume expansion", size.String()),
+			}
+			break
+		}
+
+		r.Log.Info("Resizing PersistentVolumeClaim "+pvc.Name, "from", current.String(), "to", size.String())
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+		if err := r.Update(ctx, pvc); err != nil {
+			return err
+		}
+	}
+
+	// The storage class and access modes are only applied when the volume is created
+	if changes := registry.GetImmutablePVCChanges(cr, pvc); blocked == nil && len(changes) > 0 {
+		blocked = &metav1.Condition{
+			Reason:  "ImmutableSettingChanged",
+			Message: fmt.Sprintf("Cannot change the %s of the existing volume, it is only applied when the volume is created", strings.Join(changes, " and ")),
+		}
+	}
+
+	r.updateStorageResizeStatus(cr, blocked)
+	return nil
+}
+
+// isStorageClassExpandable returns true if the given storage class allows volume expansion
+func (r *DevfileRegistryReconciler) isStorageClassExpandable(ctx context.Context, storageClassName *string) (bool, error) {
+	if storageClassName == nil || *storageClassName == "" {
+		return false, nil
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	err := r.Get(ctx, types.NamespacedName{Name: *storageClassName}, storageClass)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		r.Log.Error(err, "Error getting StorageClass", "name", *storageClassName)
+		return false, err
+	}
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
 // deletePVCIfNeeded deletes the PVC for the devfile registry if one exists and if persistent storage was disabled
 func (r *DevfileRegistryReconciler) deleteOldPVCIfNeeded(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) error {
 	// Check to see if a PVC exists, if so, need to clean it up because storage was disabled
@@ -228,11 +996,101 @@ func (r *DevfileRegistryReconciler) deleteOldPVCIfNeeded(ctx context.Context, cr
 				r.Log.Error(err, "Error deleting PersistentVolumeClaim", pvc.Name)
 				return err
 			}
+			r.Recorder.Eventf(cr, corev1.EventTypeNormal, eventReasonPVCDeleted,
+				"Deleted PersistentVolumeClaim %s as storage has been disabled", pvc.Name)
 		}
 	}
 	return nil
 }
 
+// deleteOldAuthSecretIfNeeded deletes the secret generated for the authentication to the OCI registry, once
+// authentication is disabled
+func (r *DevfileRegistryReconciler) deleteOldAuthSecretIfNeeded(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) error {
+	if registry.IsOCIRegistryAuthEnabled(cr) {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.OCIRegistryAuthSecretName(cr), Namespace: cr.Namespace}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Secret not found, so there's no old secret to delete.
+			return nil
+		}
+		r.Log.Error(err, "Error getting Secret")
+		return err
+	}
+	if !metav1.IsControlledBy(secret, cr) {
+		// Secret of the user with the same name, leave it alone
+		return nil
+	}
+
+	r.Log.Info("Old Secret " + secret.Name + " found. Deleting it as the OCI registry authentication is disabled.")
+	err = r.Delete(ctx, secret)
+	if err != nil {
+		r.Log.Error(err, "Error deleting Secret", "name", secret.Name)
+		return err
+	}
+	return nil
+}
+
+// deleteOldGarbageCollectionCronJobIfNeeded deletes the CronJob collecting the garbage of the OCI registry storage,
+// once the garbage collection is no longer scheduled or the storage no longer outlives the registry pods
+func (r *DevfileRegistryReconciler) deleteOldGarbageCollectionCronJobIfNeeded(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) error {
+	if registry.IsGarbageCollectionEnabled(cr) {
+		return nil
+	}
+
+	cronJob := &batchv1.CronJob{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.GarbageCollectionCronJobName(cr), Namespace: cr.Namespace}, cronJob)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// CronJob not found, so there's no old CronJob to delete.
+			return nil
+		}
+		r.Log.Error(err, "Error getting CronJob")
+		return err
+	}
+	if !metav1.IsControlledBy(cronJob, cr) {
+		// CronJob of the user with the same name, leave it alone
+		return nil
+	}
+
+	r.Log.Info("Old CronJob " + cronJob.Name + " found. Deleting it as the garbage collection is no longer enabled.")
+	err = r.Delete(ctx, cronJob, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil {
+		r.Log.Error(err, "Error deleting CronJob", "name", cronJob.Name)
+		return err
+	}
+	return nil
+}
+
+// deleteOldPDBIfNeeded deletes the PodDisruptionBudget for the devfile registry if one exists and if high-availability mode was disabled
+func (r *DevfileRegistryReconciler) deleteOldPDBIfNeeded(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) error {
+	if registry.IsHighAvailabilityEnabled(cr) {
+		return nil
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	err := r.Get(ctx, types.NamespacedName{Name: registry.PodDisruptionBudgetName(cr), Namespace: cr.Namespace}, pdb)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// PDB not found, so there's no old PDB to delete.
+			return nil
+		}
+		r.Log.Error(err, "Error getting PodDisruptionBudget")
+		return err
+	}
+
+	r.Log.Info("Old PodDisruptionBudget " + pdb.Name + " found. Deleting it as high-availability mode has been disabled.")
+	err = r.Delete(ctx, pdb)
+	if err != nil {
+		r.Log.Error(err, "Error deleting PodDisruptionBudget", "name", pdb.Name)
+		return err
+	}
+	return nil
+}
+
 // updateRegistryHeadlessEnv updates or adds the REGISTRY_HEADLESS environment variable
 func updateRegistryHeadlessEnv(envVars []corev1.EnvVar, headless bool) []corev1.EnvVar {
 	found := false
@@ -270,6 +1128,11 @@ func (r *DevfileRegistryReconciler) updateDeploymentForHeadlessChange(cr *regist
 	runAsNonRoot := true
 	localHostname := "localhost"
 
+	indexImageContainer := findContainer(dep.Spec.Template.Spec.Containers, indexContainerName)
+	if indexImageContainer == nil {
+		return false, fmt.Errorf("deployment %s is missing the %s container", dep.Name, indexContainerName)
+	}
+
 	if !registry.IsHeadlessEnabled(cr) {
 		// Check if viewer container already exists before adding
 		viewerExists := false
@@ -281,24 +1144,10 @@ func (r *DevfileRegistryReconciler) updateDeploymentForHeadlessChange(cr *regist
 		}
 
 		if !viewerExists {
-			// Configure StartupProbe
-			dep.Spec.Template.Spec.Containers[0].StartupProbe = &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path:   "/viewer",
-						Port:   intstr.FromInt(registry.RegistryViewerPort),
-						Scheme: corev1.URISchemeHTTP,
-					},
-				},
-				InitialDelaySeconds: 30,
-				PeriodSeconds:       10,
-				TimeoutSeconds:      20,
-			}
-
 			// Append registry-viewer container
 			dep.Spec.Template.Spec.Containers = append(dep.Spec.Template.Spec.Containers, corev1.Container{
-				Image:           registry.GetRegistryViewerImage(cr),
-				ImagePullPolicy: registry.GetRegistryViewerImagePullPolicy(cr),
+				Image:           registry.GetRegistryViewerImage(cr, r.defaults),
+				ImagePullPolicy: registry.GetRegistryViewerImagePullPolicy(cr, r.defaults),
 				Name:            viewerContainerName,
 				SecurityContext: &corev1.SecurityContext{
 					AllowPrivilegeEscalation: &allowPrivilegeEscalation,
@@ -310,40 +1159,10 @@ func (r *DevfileRegistryReconciler) updateDeploymentForHeadlessChange(cr *regist
 						Type: "RuntimeDefault",
 					},
 				},
-				Resources: corev1.ResourceRequirements{
-					Requests: corev1.ResourceList{
-						corev1.ResourceCPU:    resource.MustParse("250m"),
-						corev1.ResourceMemory: resource.MustParse("64Mi"),
-					},
-					Limits: corev1.ResourceList{
-						corev1.ResourceCPU:    resource.MustParse("500m"),
-						corev1.ResourceMemory: resource.MustParse("256Mi"),
-					},
-				},
-				LivenessProbe: &corev1.Probe{
-					ProbeHandler: corev1.ProbeHandler{
-						HTTPGet: &corev1.HTTPGetAction{
-							Path:   "/viewer",
-							Port:   intstr.FromInt(registry.RegistryViewerPort),
-							Scheme: corev1.URISchemeHTTP,
-						},
-					},
-					InitialDelaySeconds: 15,
-					PeriodSeconds:       10,
-					TimeoutSeconds:      20,
-				},
-				ReadinessProbe: &corev1.Probe{
-					ProbeHandler: corev1.ProbeHandler{
-						HTTPGet: &corev1.HTTPGetAction{
-							Path:   "/viewer",
-							Port:   intstr.FromInt(registry.RegistryViewerPort),
-							Scheme: corev1.URISchemeHTTP,
-						},
-					},
-					InitialDelaySeconds: 15,
-					PeriodSeconds:       10,
-					TimeoutSeconds:      20,
-				},
+				Resources:      registry.GetRegistryViewerResources(cr),
+				StartupProbe:   registry.GetRegistryViewerStartupProbe(cr),
+				LivenessProbe:  registry.GetRegistryViewerLivenessProbe(cr),
+				ReadinessProbe: registry.GetRegistryViewerReadinessProbe(cr),
 				Env: []corev1.EnvVar{
 					{
 						Name:  "NEXT_PUBLIC_ANALYTICS_WRITE_KEY",
@@ -366,7 +1185,7 @@ func (r *DevfileRegistryReconciler) updateDeploymentForHeadlessChange(cr *regist
 	} else {
 		// Check if REGISTRY_HEADLESS env var needs to be updated
 		headlessEnvNeedsUpdate := true
-		for _, env := range dep.Spec.Template.Spec.Containers[0].Env {
+		for _, env := range indexImageContainer.Env {
 			if env.Name == "REGISTRY_HEADLESS" && env.Value == strconv.FormatBool(true) {
 				headlessEnvNeedsUpdate = false
 				break
@@ -384,8 +1203,8 @@ func (r *DevfileRegistryReconciler) updateDeploymentForHeadlessChange(cr *regist
 
 		if headlessEnvNeedsUpdate || viewerExists {
 			// Set REGISTRY_HEADLESS environment variable
-			dep.Spec.Template.Spec.Containers[0].Env = updateRegistryHeadlessEnv(
-				dep.Spec.Template.Spec.Containers[0].Env,
+			indexImageContainer.Env = updateRegistryHeadlessEnv(
+				indexImageContainer.Env,
 				true,
 			)
 
@@ -394,9 +1213,6 @@ func (r *DevfileRegistryReconciler) updateDeploymentForHeadlessChange(cr *regist
 				dep.Spec.Template.Spec.Containers,
 			)
 
-			// Clear startup probe
-			dep.Spec.Template.Spec.Containers[0].StartupProbe = nil
-
 			updated = true
 		}
 	}
@@ -17,11 +17,24 @@
 package controllers
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/registry"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestUpdateDeploymentForHeadlessChange(t *testing.T) {
@@ -141,3 +154,761 @@ func TestUpdateDeploymentForHeadlessChange(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdatePodScheduling(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			Scheduling: registryv1alpha1.DevfileRegistrySpecScheduling{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+				Tolerations: []corev1.Toleration{
+					{
+						Key:      "node-role.kubernetes.io/infra",
+						Operator: corev1.TolerationOpExists,
+						Effect:   corev1.TaintEffectNoSchedule,
+					},
+				},
+				PriorityClassName: "system-cluster-critical",
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		cr      *registryv1alpha1.DevfileRegistry
+		podSpec *corev1.PodSpec
+		want    bool
+	}{
+		{
+			name:    "Scheduling not set, pod spec unchanged",
+			cr:      &registryv1alpha1.DevfileRegistry{},
+			podSpec: &corev1.PodSpec{},
+			want:    false,
+		},
+		{
+			name:    "Scheduling set, pod spec needs updating",
+			cr:      cr,
+			podSpec: &corev1.PodSpec{},
+			want:    true,
+		},
+		{
+			name: "Scheduling set, pod spec already up to date",
+			cr:   cr,
+			podSpec: &corev1.PodSpec{
+				NodeSelector:      cr.Spec.Scheduling.NodeSelector,
+				Tolerations:       cr.Spec.Scheduling.Tolerations,
+				PriorityClassName: cr.Spec.Scheduling.PriorityClassName,
+			},
+			want: false,
+		},
+		{
+			name: "Scheduling removed, pod spec needs updating",
+			cr:   &registryv1alpha1.DevfileRegistry{},
+			podSpec: &corev1.PodSpec{
+				NodeSelector: cr.Spec.Scheduling.NodeSelector,
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := updatePodScheduling(tt.cr, tt.podSpec)
+			if value != tt.want {
+				t.Errorf("updatePodScheduling() = %v, want %v", value, tt.want)
+			}
+			if value && tt.podSpec.PriorityClassName != tt.cr.Spec.Scheduling.PriorityClassName {
+				t.Errorf("updatePodScheduling() did not update the priority class name")
+			}
+		})
+	}
+}
+
+func TestUpdateEnvValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars []corev1.EnvVar
+		want    []corev1.EnvVar
+		updated bool
+	}{
+		{
+			name:    "Env var already up to date",
+			envVars: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}, {Name: "REGISTRY_NAME", Value: "test"}},
+			want:    []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}, {Name: "REGISTRY_NAME", Value: "test"}},
+			updated: false,
+		},
+		{
+			name:    "Env var with another value",
+			envVars: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}, {Name: "REGISTRY_NAME", Value: "old"}},
+			want:    []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}, {Name: "REGISTRY_NAME", Value: "test"}},
+			updated: true,
+		},
+		{
+			name:    "Env var missing",
+			envVars: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}},
+			want:    []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}, {Name: "REGISTRY_NAME", Value: "test"}},
+			updated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := updateEnvValue(&tt.envVars, "REGISTRY_NAME", "test")
+			if updated != tt.updated {
+				t.Errorf("updateEnvValue() = %v, want %v", updated, tt.updated)
+			}
+			if !reflect.DeepEqual(tt.envVars, tt.want) {
+				t.Errorf("updateEnvValue() env = %v, want %v", tt.envVars, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdatePVC(t *testing.T) {
+	scheme := newTestScheme()
+
+	expandable := true
+	notExpandable := false
+	expandableClass := "expandable"
+	notExpandableClass := "not-expandable"
+	storageClasses := []client.Object{
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: expandableClass}, AllowVolumeExpansion: &expandable},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: notExpandableClass}, AllowVolumeExpansion: &notExpandable},
+	}
+
+	tests := []struct {
+		name           string
+		size           string
+		storageClass   string
+		requestedClass string
+		requestedModes []corev1.PersistentVolumeAccessMode
+		wantSize       string
+		wantReason     string
+	}{
+		{
+			name:         "Volume size unchanged",
+			size:         "1Gi",
+			storageClass: expandableClass,
+			wantSize:     "1Gi",
+		},
+		{
+			name:         "Volume size increased with an expandable storage class",
+			size:         "5Gi",
+			storageClass: expandableClass,
+			wantSize:     "5Gi",
+		},
+		{
+			name:         "Volume size increased with a storage class not allowing expansion",
+			size:         "5Gi",
+			storageClass: notExpandableClass,
+			wantSize:     "1Gi",
+			wantReason:   "StorageClassNotExpandable",
+		},
+		{
+			name:         "Volume size decreased",
+			size:         "500Mi",
+			storageClass: expandableClass,
+			wantSize:     "1Gi",
+			wantReason:   "ShrinkNotSupported",
+		},
+		{
+			name:           "Storage class changed",
+			size:           "1Gi",
+			storageClass:   expandableClass,
+			requestedClass: notExpandableClass,
+			wantSize:       "1Gi",
+			wantReason:     "ImmutableSettingChanged",
+		},
+		{
+			name:           "Access modes changed",
+			size:           "1Gi",
+			storageClass:   expandableClass,
+			requestedModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			wantSize:       "1Gi",
+			wantReason:     "ImmutableSettingChanged",
+		},
+		{
+			name:           "Storage class unchanged when set explicitly",
+			size:           "1Gi",
+			storageClass:   expandableClass,
+			requestedClass: expandableClass,
+			wantSize:       "1Gi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+						RegistryVolumeSize: tt.size,
+						StorageClassName:   &tt.requestedClass,
+						AccessModes:        tt.requestedModes,
+					},
+				},
+			}
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: registry.PVCName(cr), Namespace: cr.Namespace},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					StorageClassName: &tt.storageClass,
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}
+			r := newTestReconciler(scheme, append(storageClasses, cr, pvc)...)
+
+			if err := r.updatePVC(context.TODO(), cr, pvc); err != nil {
+				t.Fatalf("updatePVC() unexpected error: %v", err)
+			}
+
+			got := &corev1.PersistentVolumeClaim{}
+			if err := r.Get(context.TODO(), client.ObjectKeyFromObject(pvc), got); err != nil {
+				t.Fatalf("failed to get PersistentVolumeClaim: %v", err)
+			}
+			if size := got.Spec.Resources.Requests[corev1.ResourceStorage]; size.Cmp(resource.MustParse(tt.wantSize)) != 0 {
+				t.Errorf("updatePVC() size = %v, want %v", size.String(), tt.wantSize)
+			}
+
+			condition := meta.FindStatusCondition(cr.Status.Conditions, typeStorageResizeBlocked)
+			if tt.wantReason == "" && condition != nil {
+				t.Errorf("updatePVC() unexpected condition %v", condition)
+			} else if tt.wantReason != "" && (condition == nil || condition.Reason != tt.wantReason) {
+				t.Errorf("updatePVC() condition = %v, want reason %v", condition, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestGetRegistryConfig(t *testing.T) {
+	scheme := newTestScheme()
+
+	optional := true
+	configMaps := []client.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "valid-config", Namespace: "test"},
+			Data:       map[string]string{"config.yml": "log:\n  level: debug\n"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "invalid-config", Namespace: "test"},
+			Data:       map[string]string{"config.yml": "http:\n  addr: :8000\n"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		ref        *corev1.ConfigMapKeySelector
+		wantLevel  string
+		wantReason string
+	}{
+		{
+			name: "No config map referenced",
+		},
+		{
+			name:      "Valid config merged",
+			ref:       &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "valid-config"}, Key: "config.yml"},
+			wantLevel: "debug",
+		},
+		{
+			name:       "Config changing the OCI registry port",
+			ref:        &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "invalid-config"}, Key: "config.yml"},
+			wantReason: "InvalidConfig",
+		},
+		{
+			name:       "Missing config map",
+			ref:        &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing-config"}, Key: "config.yml"},
+			wantReason: "ConfigMapNotFound",
+		},
+		{
+			name: "Missing optional key",
+			ref:  &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "valid-config"}, Key: "other.yml", Optional: &optional},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+			}
+			cr.Spec.OciRegistry.Config = tt.ref
+			r := newTestReconciler(scheme, configMaps...)
+
+			registryConfig, invalid, err := r.getRegistryConfig(context.TODO(), cr)
+			if err != nil {
+				t.Fatalf("getRegistryConfig() unexpected error: %v", err)
+			}
+			if tt.wantReason == "" && invalid != nil {
+				t.Errorf("getRegistryConfig() unexpected condition %v", invalid)
+			} else if tt.wantReason != "" && (invalid == nil || invalid.Reason != tt.wantReason) {
+				t.Errorf("getRegistryConfig() condition = %v, want reason %v", invalid, tt.wantReason)
+			}
+			if tt.wantReason != "" {
+				return
+			}
+			if err := registryv1alpha1.IsRegistryConfigValid(registryConfig); err != nil {
+				t.Errorf("getRegistryConfig() invalid config: %v", err)
+			}
+			if tt.wantLevel != "" && !strings.Contains(registryConfig, "level: "+tt.wantLevel) {
+				t.Errorf("getRegistryConfig() config = %v, want log level %v", registryConfig, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestGetPodTemplateOverrideError(t *testing.T) {
+	scheme := newTestScheme()
+
+	tests := []struct {
+		name       string
+		override   string
+		wantReason string
+	}{
+		{
+			name: "No override",
+		},
+		{
+			name:     "Valid override",
+			override: `{"metadata":{"annotations":{"sidecar.istio.io/inject":"false"}}}`,
+		},
+		{
+			name:       "Override not matching the pod template",
+			override:   `{"spec":{"containers":{"name":"sidecar"}}}`,
+			wantReason: "InvalidPodTemplateOverride",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+			}
+			if tt.override != "" {
+				cr.Spec.PodTemplateOverride = &apiextensionsv1.JSON{Raw: []byte(tt.override)}
+			}
+
+			invalid := getPodTemplateOverrideError(cr, config.Defaults{}, scheme, registry.LabelsForDevfileRegistry(cr))
+			if tt.wantReason == "" && invalid != nil {
+				t.Errorf("getPodTemplateOverrideError() unexpected condition %v", invalid)
+			} else if tt.wantReason != "" && (invalid == nil || invalid.Reason != tt.wantReason) {
+				t.Errorf("getPodTemplateOverrideError() condition = %v, want reason %v", invalid, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestUpdateOCIRegistryAuthVolume(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+	}
+	dep, err := registry.GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), registry.LabelsForDevfileRegistry(cr))
+	if err != nil {
+		t.Fatalf("GenerateDeployment() unexpected error: %v", err)
+	}
+	podSpec := &dep.Spec.Template.Spec
+	volumes := len(podSpec.Volumes)
+
+	cr.Spec.OciRegistry.Auth = &registryv1alpha1.DevfileRegistrySpecOCIAuth{}
+	if !updateOCIRegistryAuthVolume(cr, podSpec, findContainer(podSpec.Containers, ociContainerName)) {
+		t.Errorf("updateOCIRegistryAuthVolume() expected an update when authentication is enabled")
+	}
+	if updateOCIRegistryAuthVolume(cr, podSpec, findContainer(podSpec.Containers, ociContainerName)) {
+		t.Errorf("updateOCIRegistryAuthVolume() unexpected update when the volume is up to date")
+	}
+	if volume := findVolume(podSpec.Volumes, registry.OCIRegistryAuthVolumeName); volume == nil || volume.Secret.SecretName != registry.OCIRegistryAuthSecretName(cr) {
+		t.Errorf("updateOCIRegistryAuthVolume() volume = %v, want the generated secret", volume)
+	}
+
+	// The htpasswd file of the user is served from the generated secret
+	cr.Spec.OciRegistry.Auth.HtpasswdSecret = "my-htpasswd"
+	if updateOCIRegistryAuthVolume(cr, podSpec, findContainer(podSpec.Containers, ociContainerName)) {
+		t.Errorf("updateOCIRegistryAuthVolume() unexpected update when an htpasswd secret is set")
+	}
+
+	cr.Spec.OciRegistry.Auth = nil
+	ociContainer := findContainer(podSpec.Containers, ociContainerName)
+	if !updateOCIRegistryAuthVolume(cr, podSpec, ociContainer) {
+		t.Errorf("updateOCIRegistryAuthVolume() expected an update when authentication is disabled")
+	}
+	if len(podSpec.Volumes) != volumes || findVolume(podSpec.Volumes, registry.OCIRegistryAuthVolumeName) != nil {
+		t.Errorf("updateOCIRegistryAuthVolume() volume not removed")
+	}
+	for _, mount := range ociContainer.VolumeMounts {
+		if mount.Name == registry.OCIRegistryAuthVolumeName {
+			t.Errorf("updateOCIRegistryAuthVolume() volume mount not removed")
+		}
+	}
+}
+
+func TestUpdateOCIRegistryClientAuth(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+	}
+	dep, err := registry.GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), registry.LabelsForDevfileRegistry(cr))
+	if err != nil {
+		t.Fatalf("GenerateDeployment() unexpected error: %v", err)
+	}
+	podSpec := &dep.Spec.Template.Spec
+	indexContainer := findContainer(podSpec.Containers, indexContainerName)
+	envVars := len(indexContainer.Env)
+	volumes := len(podSpec.Volumes)
+
+	cr.Spec.OciRegistry.Auth = &registryv1alpha1.DevfileRegistrySpecOCIAuth{}
+	if !updateOCIRegistryClientAuth(cr, podSpec, indexContainer) {
+		t.Errorf("updateOCIRegistryClientAuth() expected an update when authentication is enabled")
+	}
+	if updateOCIRegistryClientAuth(cr, podSpec, indexContainer) {
+		t.Errorf("updateOCIRegistryClientAuth() unexpected update when the Docker config is up to date")
+	}
+	if len(indexContainer.Env) != envVars+1 || indexContainer.Env[envVars].Name != registry.DockerConfigEnv {
+		t.Errorf("updateOCIRegistryClientAuth() env = %v, want %s", indexContainer.Env, registry.DockerConfigEnv)
+	}
+	if volume := findVolume(podSpec.Volumes, registry.OCIRegistryClientAuthVolumeName); volume == nil || volume.Secret.SecretName != registry.OCIRegistryAuthSecretName(cr) {
+		t.Errorf("updateOCIRegistryClientAuth() volume = %v, want the generated secret", volume)
+	}
+
+	cr.Spec.OciRegistry.Auth = nil
+	if !updateOCIRegistryClientAuth(cr, podSpec, indexContainer) {
+		t.Errorf("updateOCIRegistryClientAuth() expected an update when authentication is disabled")
+	}
+	if len(indexContainer.Env) != envVars || len(podSpec.Volumes) != volumes {
+		t.Errorf("updateOCIRegistryClientAuth() Docker config not removed, env = %v", indexContainer.Env)
+	}
+	for _, mount := range indexContainer.VolumeMounts {
+		if mount.Name == registry.OCIRegistryClientAuthVolumeName {
+			t.Errorf("updateOCIRegistryClientAuth() volume mount not removed")
+		}
+	}
+}
+
+func TestUpdateOCIRegistryAuthSecret(t *testing.T) {
+	scheme := newTestScheme()
+	userHtpasswd := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-htpasswd", Namespace: "test"},
+		Data:       map[string][]byte{registry.OCIRegistryHtpasswdKey: []byte("user:$2y$05$hash\n")},
+	}
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+	}
+	cr.Spec.OciRegistry.Auth = &registryv1alpha1.DevfileRegistrySpecOCIAuth{}
+	secret := registry.GenerateOCIRegistryAuthSecret(cr, "", scheme, registry.LabelsForDevfileRegistry(cr))
+	r := newTestReconciler(scheme, userHtpasswd, secret)
+	password := string(secret.Data[registry.OCIRegistryAuthPasswordKey])
+
+	if err := r.updateOCIRegistryAuthSecret(context.TODO(), cr, secret); err != nil {
+		t.Fatalf("updateOCIRegistryAuthSecret() unexpected error: %v", err)
+	}
+	if htpasswd := string(secret.Data[registry.OCIRegistryHtpasswdKey]); strings.Count(htpasswd, "\n") != 1 {
+		t.Errorf("updateOCIRegistryAuthSecret() htpasswd = %v, want only the generated entry", htpasswd)
+	}
+
+	cr.Spec.OciRegistry.Auth.HtpasswdSecret = "my-htpasswd"
+	if err := r.updateOCIRegistryAuthSecret(context.TODO(), cr, secret); err != nil {
+		t.Fatalf("updateOCIRegistryAuthSecret() unexpected error: %v", err)
+	}
+	htpasswd, err := r.getOCIRegistryHtpasswd(context.TODO(), cr)
+	if err != nil {
+		t.Fatalf("getOCIRegistryHtpasswd() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(htpasswd, registry.DefaultOCIRegistryAuthUsername+":") || !strings.HasSuffix(htpasswd, "user:$2y$05$hash\n") {
+		t.Errorf("updateOCIRegistryAuthSecret() htpasswd = %v, want the generated entry followed by the one of the user", htpasswd)
+	}
+	if string(secret.Data[registry.OCIRegistryAuthPasswordKey]) != password {
+		t.Errorf("updateOCIRegistryAuthSecret() generated password changed")
+	}
+
+	cr.Spec.OciRegistry.Auth.HtpasswdSecret = "missing-htpasswd"
+	if err := r.updateOCIRegistryAuthSecret(context.TODO(), cr, secret); err == nil {
+		t.Errorf("updateOCIRegistryAuthSecret() expected an error when the htpasswd secret is missing")
+	}
+}
+
+func TestUpdateGitSource(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+	}
+	dep, err := registry.GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), registry.LabelsForDevfileRegistry(cr))
+	if err != nil {
+		t.Fatalf("GenerateDeployment() unexpected error: %v", err)
+	}
+	template := &dep.Spec.Template
+	volumes := len(template.Spec.Volumes)
+	env := len(template.Spec.Containers[0].Env)
+
+	cr.Spec.Source.Git = &registryv1alpha1.DevfileRegistrySpecGitSource{URL: "https://github.com/devfile/registry.git"}
+	cr.Status.SourceCommit = "1111111111111111111111111111111111111111"
+	if !updateGitSource(cr, config.Defaults{}, template, findContainer(template.Spec.Containers, indexContainerName)) {
+		t.Errorf("updateGitSource() expected an update when the Git source is set")
+	}
+	if updateGitSource(cr, config.Defaults{}, template, findContainer(template.Spec.Containers, indexContainerName)) {
+		t.Errorf("updateGitSource() unexpected update when the pod template is up to date")
+	}
+	if findContainer(template.Spec.InitContainers, registry.IndexBuilderContainerName) == nil || findVolume(template.Spec.Volumes, registry.SourceVolumeName) == nil {
+		t.Errorf("updateGitSource() index builder or source volume not added")
+	}
+
+	cr.Spec.Source.Git.Revision = "v1.0.0"
+	cr.Status.SourceCommit = "2222222222222222222222222222222222222222"
+	if !updateGitSource(cr, config.Defaults{}, template, findContainer(template.Spec.Containers, indexContainerName)) {
+		t.Errorf("updateGitSource() expected an update when the revision changes")
+	}
+	if commit := template.Annotations[registry.SourceCommitAnnotation]; commit != cr.Status.SourceCommit {
+		t.Errorf("updateGitSource() commit = %s, want %s", commit, cr.Status.SourceCommit)
+	}
+
+	cr.Spec.Source.Git = nil
+	indexContainer := findContainer(template.Spec.Containers, indexContainerName)
+	if !updateGitSource(cr, config.Defaults{}, template, indexContainer) {
+		t.Errorf("updateGitSource() expected an update when the Git source is removed")
+	}
+	if len(template.Spec.InitContainers) != 0 || len(template.Spec.Volumes) != volumes || len(indexContainer.Env) != env {
+		t.Errorf("updateGitSource() index builder, source volume or environment variables not removed")
+	}
+	for _, mount := range indexContainer.VolumeMounts {
+		if mount.Name == registry.SourceVolumeName {
+			t.Errorf("updateGitSource() volume mount not removed")
+		}
+	}
+	if _, found := template.Annotations[registry.SourceCommitAnnotation]; found {
+		t.Errorf("updateGitSource() commit annotation not removed")
+	}
+}
+
+func TestUpdateMirrorSource(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			Source: registryv1alpha1.DevfileRegistrySpecSource{
+				Git: &registryv1alpha1.DevfileRegistrySpecGitSource{URL: "https://github.com/devfile/registry.git"},
+			},
+		},
+	}
+	dep, err := registry.GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), registry.LabelsForDevfileRegistry(cr))
+	if err != nil {
+		t.Fatalf("GenerateDeployment() unexpected error: %v", err)
+	}
+	template := &dep.Spec.Template
+	indexContainer := findContainer(template.Spec.Containers, indexContainerName)
+
+	// Moving from the Git source to a mirrored registry replaces the index the devfile index server reads
+	cr.Spec.Source.Git = nil
+	cr.Spec.Source.Mirror = &registryv1alpha1.DevfileRegistrySpecMirrorSource{URL: "https://registry.devfile.io"}
+	updateGitSource(cr, config.Defaults{}, template, indexContainer)
+	if !updateMirrorSource(cr, &template.Spec, indexContainer) {
+		t.Errorf("updateMirrorSource() expected an update when the mirrored registry is set")
+	}
+	if updateGitSource(cr, config.Defaults{}, template, indexContainer) || updateMirrorSource(cr, &template.Spec, indexContainer) {
+		t.Errorf("updateMirrorSource() unexpected update when the pod template is up to date")
+	}
+	if findVolume(template.Spec.Volumes, registry.MirrorVolumeName) == nil {
+		t.Errorf("updateMirrorSource() mirror volume not added")
+	}
+	env := map[string]string{}
+	for _, envVar := range indexContainer.Env {
+		env[envVar.Name] = envVar.Value
+	}
+	for _, want := range registry.GetMirrorIndexEnv() {
+		if env[want.Name] != want.Value {
+			t.Errorf("updateMirrorSource() %s = %s, want %s", want.Name, env[want.Name], want.Value)
+		}
+	}
+	if _, found := env["DEVFILE_STACKS"]; found {
+		t.Errorf("updateMirrorSource() environment variables of the Git source not removed")
+	}
+
+	cr.Spec.Source.Mirror = nil
+	if !updateMirrorSource(cr, &template.Spec, indexContainer) {
+		t.Errorf("updateMirrorSource() expected an update when the mirrored registry is removed")
+	}
+	if findVolume(template.Spec.Volumes, registry.MirrorVolumeName) != nil {
+		t.Errorf("updateMirrorSource() mirror volume not removed")
+	}
+	for _, mount := range indexContainer.VolumeMounts {
+		if mount.Name == registry.MirrorVolumeName {
+			t.Errorf("updateMirrorSource() volume mount not removed")
+		}
+	}
+	for _, envVar := range indexContainer.Env {
+		if envVar.Name == "DEVFILE_INDEX" {
+			t.Errorf("updateMirrorSource() environment variable %s not removed", envVar.Name)
+		}
+	}
+}
+
+func TestUpdateGarbageCollectionCronJob(t *testing.T) {
+	scheme := newTestScheme()
+
+	storageEnabled := true
+	storageDisabled := false
+	deleteUntagged := true
+
+	tests := []struct {
+		name        string
+		spec        registryv1alpha1.DevfileRegistrySpec
+		wantUpdated bool
+		wantPVC     bool
+	}{
+		{
+			name: "Garbage collection unchanged",
+			spec: registryv1alpha1.DevfileRegistrySpec{
+				Storage: registryv1alpha1.DevfileRegistrySpecStorage{Enabled: &storageEnabled},
+				OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+					GarbageCollection: &registryv1alpha1.DevfileRegistrySpecGarbageCollection{Schedule: "0 3 * * *"},
+				},
+			},
+			wantUpdated: false,
+			wantPVC:     true,
+		},
+		{
+			name: "Schedule changed and untagged manifests deleted",
+			spec: registryv1alpha1.DevfileRegistrySpec{
+				Storage: registryv1alpha1.DevfileRegistrySpecStorage{Enabled: &storageEnabled},
+				OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+					GarbageCollection: &registryv1alpha1.DevfileRegistrySpecGarbageCollection{
+						Schedule:       "0 4 * * 0",
+						DeleteUntagged: &deleteUntagged,
+					},
+				},
+			},
+			wantUpdated: true,
+			wantPVC:     true,
+		},
+		{
+			name: "Storage moved to an S3 bucket",
+			spec: registryv1alpha1.DevfileRegistrySpec{
+				Storage: registryv1alpha1.DevfileRegistrySpecStorage{Enabled: &storageDisabled},
+				OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+					Storage: registryv1alpha1.DevfileRegistrySpecOCIStorage{
+						S3: &registryv1alpha1.DevfileRegistrySpecS3Storage{Bucket: "devfile-registry"},
+					},
+					GarbageCollection: &registryv1alpha1.DevfileRegistrySpecGarbageCollection{Schedule: "0 3 * * *"},
+				},
+			},
+			wantUpdated: true,
+			wantPVC:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "test"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Storage: registryv1alpha1.DevfileRegistrySpecStorage{Enabled: &storageEnabled},
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+						GarbageCollection: &registryv1alpha1.DevfileRegistrySpecGarbageCollection{Schedule: "0 3 * * *"},
+					},
+				},
+			}
+			cronJob := registry.GenerateGarbageCollectionCronJob(cr, config.Defaults{}, scheme, registry.LabelsForDevfileRegistry(cr))
+			r := newTestReconciler(scheme, cr, cronJob)
+
+			existing := &batchv1.CronJob{}
+			if err := r.Get(context.TODO(), client.ObjectKeyFromObject(cronJob), existing); err != nil {
+				t.Fatalf("failed to get CronJob: %v", err)
+			}
+			cr.Spec = tt.spec
+			if err := r.updateGarbageCollectionCronJob(context.TODO(), cr, existing.DeepCopy()); err != nil {
+				t.Fatalf("updateGarbageCollectionCronJob() unexpected error: %v", err)
+			}
+
+			got := &batchv1.CronJob{}
+			if err := r.Get(context.TODO(), client.ObjectKeyFromObject(cronJob), got); err != nil {
+				t.Fatalf("failed to get CronJob: %v", err)
+			}
+			if updated := got.ResourceVersion != existing.ResourceVersion; updated != tt.wantUpdated {
+				t.Errorf("updateGarbageCollectionCronJob() updated = %v, want %v", updated, tt.wantUpdated)
+			}
+			if got.Spec.Schedule != tt.spec.OciRegistry.GarbageCollection.Schedule {
+				t.Errorf("updateGarbageCollectionCronJob() schedule = %v, want %v", got.Spec.Schedule, tt.spec.OciRegistry.GarbageCollection.Schedule)
+			}
+			podSpec := got.Spec.JobTemplate.Spec.Template.Spec
+			if args := podSpec.Containers[0].Args; !reflect.DeepEqual(args, registry.GetGarbageCollectionArgs(cr)) {
+				t.Errorf("updateGarbageCollectionCronJob() args = %v, want %v", args, registry.GetGarbageCollectionArgs(cr))
+			}
+			volume := findVolume(podSpec.Volumes, registry.DevfileRegistryVolumeName)
+			if hasPVC := volume != nil && volume.PersistentVolumeClaim != nil; hasPVC != tt.wantPVC {
+				t.Errorf("updateGarbageCollectionCronJob() persistent volume = %v, want %v", hasPVC, tt.wantPVC)
+			}
+		})
+	}
+}
+
+func TestUpdateService(t *testing.T) {
+	scheme := newTestScheme()
+
+	tests := []struct {
+		name         string
+		currentType  corev1.ServiceType
+		exposureType string
+		oldPorts     bool
+		want         corev1.ServiceType
+	}{
+		{
+			name:         "Case 1: ClusterIP to LoadBalancer",
+			currentType:  corev1.ServiceTypeClusterIP,
+			exposureType: registry.ExposureTypeLoadBalancer,
+			want:         corev1.ServiceTypeLoadBalancer,
+		},
+		{
+			name:         "Case 2: NodePort to ClusterIP",
+			currentType:  corev1.ServiceTypeNodePort,
+			exposureType: registry.ExposureTypeIngress,
+			want:         corev1.ServiceTypeClusterIP,
+		},
+		{
+			name:         "Case 3: Unchanged NodePort",
+			currentType:  corev1.ServiceTypeNodePort,
+			exposureType: registry.ExposureTypeNodePort,
+			want:         corev1.ServiceTypeNodePort,
+		},
+		{
+			name:         "Case 4: OCI registry and metrics ports removed from a NodePort service",
+			currentType:  corev1.ServiceTypeNodePort,
+			exposureType: registry.ExposureTypeNodePort,
+			oldPorts:     true,
+			want:         corev1.ServiceTypeNodePort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: tt.exposureType},
+				},
+			}
+			svc := registry.GenerateService(cr, scheme, nil)
+			svc.Spec.Type = tt.currentType
+			if tt.currentType == corev1.ServiceTypeNodePort {
+				svc.Spec.Ports[0].NodePort = 30080
+			}
+			if tt.oldPorts {
+				// Services created before the OCI registry and metrics ports were kept off external services
+				svc.Spec.Ports = append(svc.Spec.Ports, registry.GetInternalServicePorts()...)
+			}
+			r := newTestReconciler(scheme, svc)
+
+			if err := r.updateService(context.TODO(), cr, svc); err != nil {
+				t.Fatalf("updateService() unexpected error: %v", err)
+			}
+			updated := &corev1.Service{}
+			if err := r.Get(context.TODO(), client.ObjectKeyFromObject(svc), updated); err != nil {
+				t.Fatalf("updateService() failed to get the Service: %v", err)
+			}
+			if updated.Spec.Type != tt.want {
+				t.Errorf("updateService() type = %v, want %v", updated.Spec.Type, tt.want)
+			}
+			if tt.want == corev1.ServiceTypeClusterIP && updated.Spec.Ports[0].NodePort != 0 {
+				t.Errorf("updateService() node port %d kept on a ClusterIP service", updated.Spec.Ports[0].NodePort)
+			}
+			if tt.want == corev1.ServiceTypeNodePort && updated.Spec.Ports[0].NodePort != 30080 {
+				t.Errorf("updateService() node port %d not kept on a NodePort service", updated.Spec.Ports[0].NodePort)
+			}
+			if !servicePortsEqual(updated.Spec.Ports, registry.GetServicePorts(cr)) {
+				t.Errorf("updateService() ports = %v, want %v", updated.Spec.Ports, registry.GetServicePorts(cr))
+			}
+		})
+	}
+}
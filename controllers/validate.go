@@ -18,10 +18,15 @@ package controllers
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/metrics"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -31,10 +36,27 @@ const (
 	emptyStatus            = "CR list does not contain any entries"
 )
 
-// validateDevfileRegistries validates the URLs in the CR to determine if they are still reachable.
-func validateDevfileRegistries(devfileRegistries []v1alpha1.DevfileRegistryService) (status string) {
+// validateDevfileRegistries validates the URLs in the CR to determine if they are still reachable, also returning the
+// URLs of the unreachable ones. The reachability of each registry of the list of the given kind is recorded in the
+// operator metrics.
+func validateDevfileRegistries(kind string, list metav1.Object, devfileRegistries []v1alpha1.DevfileRegistryService) (status string, unreachable map[string]bool) {
 
 	var updatedStatus []string
+	unreachable = map[string]bool{}
+
+	// Drop the registries removed from the list since the last validation, keeping the reachability of the others
+	// until they are validated again
+	entries := make([]metrics.RegistryListEntry, len(devfileRegistries))
+	for i, registry := range devfileRegistries {
+		entries[i] = metrics.RegistryListEntry{
+			Kind:      kind,
+			Namespace: list.GetNamespace(),
+			List:      list.GetName(),
+			Name:      registry.Name,
+			URL:       registry.URL,
+		}
+	}
+	metrics.DeleteStaleRegistryListEntries(kind, list.GetNamespace(), list.GetName(), entries)
 
 	if len(devfileRegistries) == 0 {
 		updatedStatus = append(updatedStatus, emptyStatus)
@@ -42,9 +64,13 @@ func validateDevfileRegistries(devfileRegistries []v1alpha1.DevfileRegistryServi
 		for i := range devfileRegistries {
 			registry := devfileRegistries[i]
 			url := registry.URL
+			start := time.Now()
 			err := v1alpha1.IsRegistryValid(registry.SkipTLSVerify, url)
+			metrics.ObserveRegistryValidation(start, err == nil)
+			metrics.SetRegistryReachable(entries[i], err == nil)
 			if err != nil {
 				updatedStatus = append(updatedStatus, fmt.Sprintf(registryUnreachable, url))
+				unreachable[url] = true
 			}
 		}
 
@@ -53,13 +79,23 @@ func validateDevfileRegistries(devfileRegistries []v1alpha1.DevfileRegistryServi
 		}
 	}
 
-	return strings.Join(updatedStatus, "")
+	return strings.Join(updatedStatus, ""), unreachable
 
 }
 
-// validateDevfileRegistriesAndUpdateCondition runs validateDevfileRegistries and updates a status condition based on the result
-func validateDevfileRegistriesAndUpdateCondition(devfileRegistries []v1alpha1.DevfileRegistryService, condition metav1.Condition, updateConditionFn func(metav1.Condition)) {
-	validateMessage := validateDevfileRegistries(devfileRegistries)
+// validateDevfileRegistriesAndUpdateCondition runs validateDevfileRegistries and updates a status condition based on the result,
+// along with the URLs of the unreachable registries. An event is emitted for each registry whose reachability changed since the
+// previous validation, previous holding the URLs of the registries it could not reach.
+func validateDevfileRegistriesAndUpdateCondition(recorder record.EventRecorder, kind string, list client.Object, previous []string, devfileRegistries []v1alpha1.DevfileRegistryService, condition metav1.Condition, updateConditionFn func(metav1.Condition, []string)) {
+	validateMessage, unreachable := validateDevfileRegistries(kind, list, devfileRegistries)
+	recordRegistryReachabilityEvents(recorder, list, previous, devfileRegistries, unreachable)
+
+	var unreachableURLs []string
+	for _, devfileRegistry := range devfileRegistries {
+		if unreachable[devfileRegistry.URL] && !slices.Contains(unreachableURLs, devfileRegistry.URL) {
+			unreachableURLs = append(unreachableURLs, devfileRegistry.URL)
+		}
+	}
 
 	condition.Message = validateMessage
 	if validateMessage != allRegistriesReachable {
@@ -68,5 +104,5 @@ func validateDevfileRegistriesAndUpdateCondition(devfileRegistries []v1alpha1.De
 		condition.Status = metav1.ConditionTrue
 	}
 
-	updateConditionFn(condition)
+	updateConditionFn(condition, unreachableURLs)
 }
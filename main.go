@@ -21,7 +21,9 @@ import (
 	"flag"
 	"os"
 
+	configv1 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -31,9 +33,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	registryv1beta1 "github.com/devfile/registry-operator/api/v1beta1"
 	"github.com/devfile/registry-operator/controllers"
+	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/webhooks"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -45,7 +51,11 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(routev1.AddToScheme(scheme))
+	utilruntime.Must(configv1.Install(scheme))
+	utilruntime.Must(gatewayv1.Install(scheme))
+	utilruntime.Must(monitoringv1.AddToScheme(scheme))
 	utilruntime.Must(registryv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(registryv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -67,6 +77,13 @@ func main() {
 	setupLog.Info(("logger set up"))
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// Rewrite the prefixes of the images of the registries, e.g. to pull them from a mirror in disconnected clusters
+	imagePrefixRewrites, err := config.ParseImagePrefixRewrites(os.Getenv("IMAGE_PREFIX_REWRITES"))
+	if err != nil {
+		setupLog.Error(err, "unable to parse the image prefix rewrites")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		LeaderElection:         enableLeaderElection,
 		Scheme:                 scheme,
@@ -79,6 +96,7 @@ func main() {
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
+		Cache: controllers.NewCacheOptions(),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -86,25 +104,33 @@ func main() {
 	}
 
 	if err = (&controllers.DevfileRegistryReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("DevfileRegistry"),
-		Scheme: mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("DevfileRegistry"),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("devfileregistry-controller"),
+		Config: &config.Provider{
+			Reader:              mgr.GetClient(),
+			ImagePrefixRewrites: imagePrefixRewrites,
+		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DevfileRegistry")
 		os.Exit(1)
 	}
 	if err = (&controllers.DevfileRegistriesListReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("DevfileRegistriesList"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("DevfileRegistriesList"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("devfileregistrieslist-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DevfileRegistriesList")
 		os.Exit(1)
 	}
 	if err = (&controllers.ClusterDevfileRegistriesListReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ClusterDevfileRegistriesList"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("ClusterDevfileRegistriesList"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("clusterdevfileregistrieslist-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterDevfileRegistriesList")
 		os.Exit(1)
@@ -112,15 +138,19 @@ func main() {
 
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		setupLog.Info("setting up webhooks")
-		if err = (&registryv1alpha1.DevfileRegistry{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = webhooks.SetupDevfileRegistryWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DevfileRegistry")
+			os.Exit(1)
+		}
+		if err = (&registryv1beta1.DevfileRegistry{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "DevfileRegistry")
 			os.Exit(1)
 		}
-		if err = (&registryv1alpha1.DevfileRegistriesList{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = webhooks.SetupDevfileRegistriesListWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "DevfileRegistriesList")
 			os.Exit(1)
 		}
-		if err = (&registryv1alpha1.ClusterDevfileRegistriesList{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = webhooks.SetupClusterDevfileRegistriesListWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterDevfileRegistriesList")
 			os.Exit(1)
 		}
@@ -17,8 +17,13 @@
 package cluster
 
 import (
+	"context"
+
+	configv1 "github.com/openshift/api/config/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
@@ -26,6 +31,21 @@ import (
 
 // IsOpenShift returns true if the operator is running on an OpenShift cluster
 func IsOpenShift() (bool, error) {
+	return isAPIGroupAvailable("route.openshift.io")
+}
+
+// IsGatewayAPIAvailable returns true if the Gateway API is installed on the cluster
+func IsGatewayAPIAvailable() (bool, error) {
+	return isAPIGroupAvailable("gateway.networking.k8s.io")
+}
+
+// IsMonitoringAPIAvailable returns true if the API of the Prometheus Operator is installed on the cluster
+func IsMonitoringAPIAvailable() (bool, error) {
+	return isAPIGroupAvailable("monitoring.coreos.com")
+}
+
+// isAPIGroupAvailable returns true if the given API group is served by the cluster
+func isAPIGroupAvailable(apiName string) (bool, error) {
 	kubeCfg, err := config.GetConfig()
 	if err != nil {
 		return false, err
@@ -38,11 +58,20 @@ func IsOpenShift() (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	if findAPIGroup(apiList.Groups, "route.openshift.io") == nil {
-		return false, nil
-	} else {
-		return true, nil
+	return findAPIGroup(apiList.Groups, apiName) != nil, nil
+}
+
+// GetOpenShiftIngressDomain returns the domain of the routes created without a host on an OpenShift cluster,
+// read from the cluster ingress configuration
+func GetOpenShiftIngressDomain(ctx context.Context, reader client.Reader) (string, error) {
+	ingress := &configv1.Ingress{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: "cluster"}, ingress); err != nil {
+		return "", err
+	}
+	if ingress.Spec.AppsDomain != "" {
+		return ingress.Spec.AppsDomain, nil
 	}
+	return ingress.Spec.Domain, nil
 }
 
 func findAPIGroup(source []metav1.APIGroup, apiName string) *metav1.APIGroup {
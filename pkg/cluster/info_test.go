@@ -0,0 +1,70 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetOpenShiftIngressDomain(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = configv1.Install(scheme)
+
+	tests := []struct {
+		name    string
+		spec    *configv1.IngressSpec
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Case 1: Cluster domain",
+			spec: &configv1.IngressSpec{Domain: "apps.example.com"},
+			want: "apps.example.com",
+		},
+		{
+			name: "Case 2: Apps domain overriding the cluster domain",
+			spec: &configv1.IngressSpec{Domain: "apps.example.com", AppsDomain: "routes.example.com"},
+			want: "routes.example.com",
+		},
+		{
+			name:    "Case 3: Missing ingress configuration",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.spec != nil {
+				builder.WithObjects(&configv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Spec: *tt.spec})
+			}
+			got, err := GetOpenShiftIngressDomain(context.TODO(), builder.Build())
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("TestGetOpenShiftIngressDomain error: unexpected error %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("TestGetOpenShiftIngressDomain error: expected %v got %v", tt.want, got)
+			}
+		})
+	}
+}
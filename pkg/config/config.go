@@ -16,17 +16,76 @@
 
 package config
 
-// ControllerCfg logic borrowed from https://github.com/devfile/devworkspace-operator/blob/master/pkg/config/config.go
-var ControllerCfg ControllerConfig
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
 
-type ControllerConfig struct {
-	isOpenShift bool
+// Operator-wide configuration, logic borrowed from https://github.com/devfile/devworkspace-operator/blob/master/pkg/config/config.go
+// It is discovered once on startup from the cluster. The defaults of the registries, which change while the operator
+// runs, are resolved on each use by a Provider instead.
+var (
+	mu              sync.RWMutex
+	isOpenShift     bool
+	isGatewayAPI    bool
+	isMonitoringAPI bool
+)
+
+// IsOpenShift returns true if the operator runs on OpenShift
+func IsOpenShift() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return isOpenShift
+}
+
+func SetIsOpenShift(openShift bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	isOpenShift = openShift
+}
+
+// IsGatewayAPIAvailable returns true if the Gateway API is installed on the cluster
+func IsGatewayAPIAvailable() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return isGatewayAPI
+}
+
+func SetIsGatewayAPIAvailable(available bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	isGatewayAPI = available
+}
+
+// IsMonitoringAPIAvailable returns true if the API of the Prometheus Operator is installed on the cluster
+func IsMonitoringAPIAvailable() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return isMonitoringAPI
 }
 
-func (c *ControllerConfig) IsOpenShift() bool {
-	return c.isOpenShift
+func SetIsMonitoringAPIAvailable(available bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	isMonitoringAPI = available
 }
 
-func (c *ControllerConfig) SetIsOpenShift(isOpenShift bool) {
-	c.isOpenShift = isOpenShift
+// ParseImagePrefixRewrites parses a comma-separated list of image prefix rewrites, each written as
+// <prefix>=<replacement>, e.g. "quay.io/devfile/=mirror.example.com/devfile/"
+func ParseImagePrefixRewrites(value string) (map[string]string, error) {
+	rewrites := map[string]string{}
+	for _, rewrite := range strings.Split(value, ",") {
+		rewrite = strings.TrimSpace(rewrite)
+		if rewrite == "" {
+			continue
+		}
+		prefix, replacement, found := strings.Cut(rewrite, "=")
+		prefix, replacement = strings.TrimSpace(prefix), strings.TrimSpace(replacement)
+		if !found || prefix == "" || replacement == "" {
+			return nil, fmt.Errorf("invalid image prefix rewrite %q, expected <prefix>=<replacement>", rewrite)
+		}
+		rewrites[prefix] = replacement
+	}
+	return rewrites, nil
 }
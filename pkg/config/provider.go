@@ -0,0 +1,114 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/cluster"
+)
+
+// Defaults are the operator-wide defaults of the registries, used for the fields left unset in a DevfileRegistry
+type Defaults struct {
+	// OperatorConfig is the spec of the RegistryOperatorConfig resource, empty if the resource does not exist
+	OperatorConfig registryv1alpha1.RegistryOperatorConfigSpec
+	// ClusterIngressClass is the name of the default IngressClass of the cluster, or "" if there is none
+	ClusterIngressClass string
+	// ClusterIngressDomain is the ingress domain discovered on the cluster, that is the apps domain on OpenShift,
+	// or "" if unknown
+	ClusterIngressDomain string
+	// EnvImagePrefixRewrites are the image prefix rewrites read from the operator environment
+	EnvImagePrefixRewrites map[string]string
+}
+
+// GetImagePrefixRewrites returns the image prefixes rewritten by the operator, mapped to their replacements.
+// Rewrites of the RegistryOperatorConfig resource take precedence over the ones of the operator environment.
+func (d Defaults) GetImagePrefixRewrites() map[string]string {
+	rewrites := map[string]string{}
+	for prefix, replacement := range d.EnvImagePrefixRewrites {
+		rewrites[prefix] = replacement
+	}
+	for prefix, replacement := range d.OperatorConfig.ImagePrefixRewrites {
+		rewrites[prefix] = replacement
+	}
+	return rewrites
+}
+
+// Provider resolves the Defaults of the registries on each call, from the RegistryOperatorConfig resource, the
+// IngressClasses and the ingress configuration of the cluster read with the given reader, usually the cache of the
+// manager, so that all its users see the current defaults
+type Provider struct {
+	Reader client.Reader
+	// ImagePrefixRewrites are the image prefix rewrites read from the operator environment
+	ImagePrefixRewrites map[string]string
+}
+
+// GetDefaults returns the current defaults of the registries. A nil provider returns the built-in defaults.
+func (p *Provider) GetDefaults(ctx context.Context) (Defaults, error) {
+	if p == nil {
+		return Defaults{}, nil
+	}
+	defaults := Defaults{EnvImagePrefixRewrites: p.ImagePrefixRewrites}
+
+	operatorConfig := &registryv1alpha1.RegistryOperatorConfig{}
+	err := p.Reader.Get(ctx, types.NamespacedName{Name: registryv1alpha1.RegistryOperatorConfigName}, operatorConfig)
+	if err != nil && !errors.IsNotFound(err) {
+		return defaults, err
+	}
+	defaults.OperatorConfig = operatorConfig.Spec
+
+	ingressClasses := &networkingv1.IngressClassList{}
+	if err := p.Reader.List(ctx, ingressClasses); err != nil {
+		return defaults, err
+	}
+	defaults.ClusterIngressClass = getDefaultIngressClass(ingressClasses.Items)
+
+	// The apps domain of an OpenShift cluster is the default ingress domain of the registries
+	if IsOpenShift() {
+		domain, err := cluster.GetOpenShiftIngressDomain(ctx, p.Reader)
+		if err != nil && !errors.IsNotFound(err) {
+			return defaults, err
+		}
+		defaults.ClusterIngressDomain = domain
+	}
+	return defaults, nil
+}
+
+// getDefaultIngressClass returns the name of the IngressClass marked as the default one, or "" if there is none.
+// Like the admission controller of Kubernetes, the most recently created one is used if several are marked.
+func getDefaultIngressClass(ingressClasses []networkingv1.IngressClass) string {
+	var defaultClass *networkingv1.IngressClass
+	for i := range ingressClasses {
+		ingressClass := &ingressClasses[i]
+		if ingressClass.Annotations[networkingv1.AnnotationIsDefaultIngressClass] != "true" {
+			continue
+		}
+		if defaultClass == nil || defaultClass.CreationTimestamp.Before(&ingressClass.CreationTimestamp) {
+			defaultClass = ingressClass
+		}
+	}
+	if defaultClass == nil {
+		return ""
+	}
+	return defaultClass.Name
+}
@@ -0,0 +1,186 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+func TestGetDefaults(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = configv1.Install(scheme)
+	_ = registryv1alpha1.AddToScheme(scheme)
+	defer SetIsOpenShift(false)
+
+	defaultClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "traefik",
+			Annotations: map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"},
+		},
+	}
+	clusterIngress := &configv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       configv1.IngressSpec{Domain: "apps.example.com"},
+	}
+
+	tests := []struct {
+		name        string
+		provider    *Provider
+		objects     []client.Object
+		isOpenShift bool
+		want        Defaults
+	}{
+		{
+			name: "Case 1: Built-in defaults without a provider",
+			want: Defaults{},
+		},
+		{
+			name:     "Case 2: Built-in defaults without RegistryOperatorConfig",
+			provider: &Provider{ImagePrefixRewrites: map[string]string{"quay.io/": "mirror.example.com/"}},
+			want:     Defaults{EnvImagePrefixRewrites: map[string]string{"quay.io/": "mirror.example.com/"}},
+		},
+		{
+			name:     "Case 3: RegistryOperatorConfig picked up",
+			provider: &Provider{},
+			objects: []client.Object{&registryv1alpha1.RegistryOperatorConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: registryv1alpha1.RegistryOperatorConfigName},
+				Spec:       registryv1alpha1.RegistryOperatorConfigSpec{IngressClass: "haproxy"},
+			}},
+			want: Defaults{OperatorConfig: registryv1alpha1.RegistryOperatorConfigSpec{IngressClass: "haproxy"}},
+		},
+		{
+			name:     "Case 4: RegistryOperatorConfig with another name ignored",
+			provider: &Provider{},
+			objects: []client.Object{&registryv1alpha1.RegistryOperatorConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Spec:       registryv1alpha1.RegistryOperatorConfigSpec{IngressClass: "haproxy"},
+			}},
+			want: Defaults{},
+		},
+		{
+			name:     "Case 5: Default IngressClass of the cluster",
+			provider: &Provider{},
+			objects:  []client.Object{defaultClass},
+			want:     Defaults{ClusterIngressClass: "traefik"},
+		},
+		{
+			name:        "Case 6: Ingress domain of an OpenShift cluster",
+			provider:    &Provider{},
+			objects:     []client.Object{clusterIngress},
+			isOpenShift: true,
+			want:        Defaults{ClusterIngressDomain: "apps.example.com"},
+		},
+		{
+			name:        "Case 7: Ingress configuration of an OpenShift cluster not found",
+			provider:    &Provider{},
+			isOpenShift: true,
+			want:        Defaults{},
+		},
+		{
+			name:     "Case 8: Ingress configuration ignored outside of OpenShift",
+			provider: &Provider{},
+			objects:  []client.Object{clusterIngress},
+			want:     Defaults{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetIsOpenShift(tt.isOpenShift)
+			if tt.provider != nil {
+				tt.provider.Reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			}
+			got, err := tt.provider.GetDefaults(context.TODO())
+			if err != nil {
+				t.Fatalf("TestGetDefaults error: unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TestGetDefaults error: defaults mismatch, expected: %+v got: %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetImagePrefixRewrites(t *testing.T) {
+	defaults := Defaults{
+		OperatorConfig: registryv1alpha1.RegistryOperatorConfigSpec{
+			ImagePrefixRewrites: map[string]string{"quay.io/": "config.example.com/"},
+		},
+		EnvImagePrefixRewrites: map[string]string{"quay.io/": "env.example.com/", "docker.io/": "env.example.com/"},
+	}
+	want := map[string]string{"quay.io/": "config.example.com/", "docker.io/": "env.example.com/"}
+	if got := defaults.GetImagePrefixRewrites(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TestGetImagePrefixRewrites error: rewrites mismatch, expected: %v got: %v", want, got)
+	}
+}
+
+func TestGetDefaultIngressClass(t *testing.T) {
+	defaultClass := func(name string, created time.Time) networkingv1.IngressClass {
+		return networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations:       map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"},
+			},
+		}
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		ingressClasses []networkingv1.IngressClass
+		want           string
+	}{
+		{
+			name:           "Case 1: No default IngressClass",
+			ingressClasses: []networkingv1.IngressClass{{ObjectMeta: metav1.ObjectMeta{Name: "nginx"}}},
+			want:           "",
+		},
+		{
+			name:           "Case 2: Default IngressClass",
+			ingressClasses: []networkingv1.IngressClass{{ObjectMeta: metav1.ObjectMeta{Name: "nginx"}}, defaultClass("traefik", now)},
+			want:           "traefik",
+		},
+		{
+			name:           "Case 3: Most recent default IngressClass",
+			ingressClasses: []networkingv1.IngressClass{defaultClass("haproxy", now), defaultClass("traefik", now.Add(-time.Hour))},
+			want:           "haproxy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getDefaultIngressClass(tt.ingressClasses); got != tt.want {
+				t.Errorf("TestGetDefaultIngressClass error: expected %q got %q", tt.want, got)
+			}
+		})
+	}
+}
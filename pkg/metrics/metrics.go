@@ -0,0 +1,194 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Operator-level metrics, served next to the default controller-runtime metrics of the manager
+const (
+	metricsNamespace = "devfile_registry_operator"
+
+	// Kinds of the registry lists whose entries are validated
+	KindDevfileRegistriesList        = "DevfileRegistriesList"
+	KindClusterDevfileRegistriesList = "ClusterDevfileRegistriesList"
+
+	// Results of a registry validation
+	ResultReachable   = "reachable"
+	ResultUnreachable = "unreachable"
+
+	// Reasons of a blocked deployment
+	BlockedReasonExposure            = "Exposure"
+	BlockedReasonRegistryConfig      = "RegistryConfig"
+	BlockedReasonPodTemplateOverride = "PodTemplateOverride"
+)
+
+var (
+	registryReachable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "registry_list_entry_reachable",
+		Help:      "Whether a devfile registry listed in a DevfileRegistriesList or ClusterDevfileRegistriesList is reachable (1) or not (0).",
+	}, []string{"kind", "namespace", "list", "registry", "url"})
+
+	registryValidationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "registry_validation_duration_seconds",
+		Help:      "Time taken to check that a devfile registry is reachable.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"result"})
+
+	registryInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "devfile_registry_info",
+		Help:      "Information about a DevfileRegistry, always 1.",
+	}, []string{"namespace", "name", "url", "devfile_index_image", "oci_registry_image", "registry_viewer_image"})
+
+	deploymentBlocked = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "deployment_blocked_total",
+		Help:      "Number of reconciles in which the deployment of a DevfileRegistry was blocked.",
+	}, []string{"namespace", "name", "reason"})
+
+	readinessTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "readiness_timeouts_total",
+		Help:      "Number of times a DevfileRegistry server did not become ready in time.",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(
+		registryReachable,
+		registryValidationDuration,
+		registryInfo,
+		deploymentBlocked,
+		readinessTimeouts,
+	)
+}
+
+// RegistryListEntry identifies a devfile registry listed in a DevfileRegistriesList or ClusterDevfileRegistriesList
+type RegistryListEntry struct {
+	Kind      string
+	Namespace string
+	List      string
+	Name      string
+	URL       string
+}
+
+// SetRegistryReachable records whether a devfile registry listed in a registry list is reachable
+func SetRegistryReachable(entry RegistryListEntry, reachable bool) {
+	value := 0.0
+	if reachable {
+		value = 1
+	}
+	registryReachable.WithLabelValues(entry.Kind, entry.Namespace, entry.List, entry.Name, entry.URL).Set(value)
+}
+
+// DeleteRegistryList removes the reachability of all the entries of a registry list, e.g. when the list is deleted
+func DeleteRegistryList(kind, namespace, list string) {
+	registryReachable.DeletePartialMatch(prometheus.Labels{"kind": kind, "namespace": namespace, "list": list})
+}
+
+// DeleteStaleRegistryListEntries removes the reachability of the entries of a registry list that are not in the given
+// entries, e.g. the registries removed from the list since its last validation. The reachability of the given entries
+// is kept until it is validated again.
+func DeleteStaleRegistryListEntries(kind, namespace, list string, entries []RegistryListEntry) {
+	current := map[RegistryListEntry]bool{}
+	for _, entry := range entries {
+		current[entry] = true
+	}
+
+	// The series cannot be deleted while they are collected
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		registryReachable.Collect(metrics)
+		close(metrics)
+	}()
+	var stale []prometheus.Labels
+	for metric := range metrics {
+		m := &dto.Metric{}
+		if err := metric.Write(m); err != nil {
+			continue
+		}
+		labels := prometheus.Labels{}
+		for _, label := range m.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		entry := RegistryListEntry{
+			Kind:      labels["kind"],
+			Namespace: labels["namespace"],
+			List:      labels["list"],
+			Name:      labels["registry"],
+			URL:       labels["url"],
+		}
+		if entry.Kind == kind && entry.Namespace == namespace && entry.List == list && !current[entry] {
+			stale = append(stale, labels)
+		}
+	}
+	for _, labels := range stale {
+		registryReachable.Delete(labels)
+	}
+}
+
+// ObserveRegistryValidation records the time taken to validate a devfile registry started at start
+func ObserveRegistryValidation(start time.Time, reachable bool) {
+	result := ResultReachable
+	if !reachable {
+		result = ResultUnreachable
+	}
+	registryValidationDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
+
+// RegistryInfo describes a deployed DevfileRegistry
+type RegistryInfo struct {
+	Namespace           string
+	Name                string
+	URL                 string
+	DevfileIndexImage   string
+	OCIRegistryImage    string
+	RegistryViewerImage string
+}
+
+// SetRegistryInfo records the information about a DevfileRegistry, replacing what was recorded before
+func SetRegistryInfo(info RegistryInfo) {
+	registryInfo.DeletePartialMatch(prometheus.Labels{"namespace": info.Namespace, "name": info.Name})
+	registryInfo.WithLabelValues(info.Namespace, info.Name, info.URL, info.DevfileIndexImage, info.OCIRegistryImage,
+		info.RegistryViewerImage).Set(1)
+}
+
+// DeleteRegistry removes the metrics of a deleted DevfileRegistry
+func DeleteRegistry(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	registryInfo.DeletePartialMatch(labels)
+	deploymentBlocked.DeletePartialMatch(labels)
+	readinessTimeouts.DeletePartialMatch(labels)
+}
+
+// IncDeploymentBlocked counts a reconcile in which the deployment of a DevfileRegistry was blocked
+func IncDeploymentBlocked(namespace, name, reason string) {
+	deploymentBlocked.WithLabelValues(namespace, name, reason).Inc()
+}
+
+// IncReadinessTimeout counts a DevfileRegistry server that did not become ready in time
+func IncReadinessTimeout(namespace, name string) {
+	readinessTimeouts.WithLabelValues(namespace, name).Inc()
+}
@@ -0,0 +1,142 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRegistryReachable(t *testing.T) {
+	devfileRegistries := []RegistryListEntry{
+		{Kind: KindDevfileRegistriesList, Namespace: "test-namespace", List: "test-list", Name: "community", URL: "https://registry.devfile.io"},
+		{Kind: KindDevfileRegistriesList, Namespace: "test-namespace", List: "test-list", Name: "private", URL: "http://private.example.com"},
+	}
+	clusterRegistry := RegistryListEntry{Kind: KindClusterDevfileRegistriesList, List: "test-list", Name: "community", URL: "https://registry.devfile.io"}
+
+	SetRegistryReachable(devfileRegistries[0], true)
+	SetRegistryReachable(devfileRegistries[1], false)
+	SetRegistryReachable(clusterRegistry, true)
+
+	expected := `
+# HELP devfile_registry_operator_registry_list_entry_reachable Whether a devfile registry listed in a DevfileRegistriesList or ClusterDevfileRegistriesList is reachable (1) or not (0).
+# TYPE devfile_registry_operator_registry_list_entry_reachable gauge
+devfile_registry_operator_registry_list_entry_reachable{kind="ClusterDevfileRegistriesList",list="test-list",namespace="",registry="community",url="https://registry.devfile.io"} 1
+devfile_registry_operator_registry_list_entry_reachable{kind="DevfileRegistriesList",list="test-list",namespace="test-namespace",registry="community",url="https://registry.devfile.io"} 1
+devfile_registry_operator_registry_list_entry_reachable{kind="DevfileRegistriesList",list="test-list",namespace="test-namespace",registry="private",url="http://private.example.com"} 0
+`
+	if err := testutil.CollectAndCompare(registryReachable, strings.NewReader(expected)); err != nil {
+		t.Errorf("TestRegistryReachable error: %v", err)
+	}
+
+	// Deleting a namespaced list should keep the entries of the cluster list with the same name
+	DeleteRegistryList(KindDevfileRegistriesList, "test-namespace", "test-list")
+	if count := testutil.CollectAndCount(registryReachable); count != 1 {
+		t.Errorf("TestRegistryReachable error: expected 1 series after deleting the list, got %d", count)
+	}
+	DeleteRegistryList(KindClusterDevfileRegistriesList, "", "test-list")
+	if count := testutil.CollectAndCount(registryReachable); count != 0 {
+		t.Errorf("TestRegistryReachable error: expected no series after deleting the lists, got %d", count)
+	}
+}
+
+func TestDeleteStaleRegistryListEntries(t *testing.T) {
+	community := RegistryListEntry{Kind: KindDevfileRegistriesList, Namespace: "test-namespace", List: "stale-list", Name: "community", URL: "https://registry.devfile.io"}
+	private := RegistryListEntry{Kind: KindDevfileRegistriesList, Namespace: "test-namespace", List: "stale-list", Name: "private", URL: "http://private.example.com"}
+	moved := RegistryListEntry{Kind: KindDevfileRegistriesList, Namespace: "test-namespace", List: "stale-list", Name: "private", URL: "http://moved.example.com"}
+	otherList := RegistryListEntry{Kind: KindDevfileRegistriesList, Namespace: "test-namespace", List: "other-list", Name: "private", URL: "http://private.example.com"}
+	defer DeleteRegistryList(KindDevfileRegistriesList, "test-namespace", "stale-list")
+	defer DeleteRegistryList(KindDevfileRegistriesList, "test-namespace", "other-list")
+
+	SetRegistryReachable(community, true)
+	SetRegistryReachable(private, false)
+	SetRegistryReachable(otherList, true)
+
+	// The series of the entries still listed are kept while they are validated again
+	DeleteStaleRegistryListEntries(KindDevfileRegistriesList, "test-namespace", "stale-list", []RegistryListEntry{community, moved})
+
+	expected := `
+# HELP devfile_registry_operator_registry_list_entry_reachable Whether a devfile registry listed in a DevfileRegistriesList or ClusterDevfileRegistriesList is reachable (1) or not (0).
+# TYPE devfile_registry_operator_registry_list_entry_reachable gauge
+devfile_registry_operator_registry_list_entry_reachable{kind="DevfileRegistriesList",list="other-list",namespace="test-namespace",registry="private",url="http://private.example.com"} 1
+devfile_registry_operator_registry_list_entry_reachable{kind="DevfileRegistriesList",list="stale-list",namespace="test-namespace",registry="community",url="https://registry.devfile.io"} 1
+`
+	if err := testutil.CollectAndCompare(registryReachable, strings.NewReader(expected)); err != nil {
+		t.Errorf("TestDeleteStaleRegistryListEntries error: %v", err)
+	}
+}
+
+func TestObserveRegistryValidation(t *testing.T) {
+	ObserveRegistryValidation(time.Now(), true)
+	ObserveRegistryValidation(time.Now(), false)
+	ObserveRegistryValidation(time.Now().Add(-time.Minute), false)
+
+	if count := testutil.CollectAndCount(registryValidationDuration); count != 2 {
+		t.Errorf("TestObserveRegistryValidation error: expected a histogram per result, got %d", count)
+	}
+}
+
+func TestRegistryMetrics(t *testing.T) {
+	SetRegistryInfo(RegistryInfo{
+		Namespace:           "test-namespace",
+		Name:                "test-name",
+		URL:                 "http://old.example.com",
+		DevfileIndexImage:   "quay.io/devfile/devfile-index:next",
+		OCIRegistryImage:    "quay.io/devfile/oci-registry:next",
+		RegistryViewerImage: "quay.io/devfile/registry-viewer:next",
+	})
+	// The info of the registry should be replaced rather than added to
+	SetRegistryInfo(RegistryInfo{
+		Namespace:         "test-namespace",
+		Name:              "test-name",
+		URL:               "http://test.example.com",
+		DevfileIndexImage: "quay.io/devfile/devfile-index:next",
+		OCIRegistryImage:  "quay.io/devfile/oci-registry:next",
+	})
+	IncDeploymentBlocked("test-namespace", "test-name", BlockedReasonExposure)
+	IncDeploymentBlocked("test-namespace", "test-name", BlockedReasonExposure)
+	IncReadinessTimeout("test-namespace", "test-name")
+
+	expected := `
+# HELP devfile_registry_operator_devfile_registry_info Information about a DevfileRegistry, always 1.
+# TYPE devfile_registry_operator_devfile_registry_info gauge
+devfile_registry_operator_devfile_registry_info{devfile_index_image="quay.io/devfile/devfile-index:next",name="test-name",namespace="test-namespace",oci_registry_image="quay.io/devfile/oci-registry:next",registry_viewer_image="",url="http://test.example.com"} 1
+# HELP devfile_registry_operator_deployment_blocked_total Number of reconciles in which the deployment of a DevfileRegistry was blocked.
+# TYPE devfile_registry_operator_deployment_blocked_total counter
+devfile_registry_operator_deployment_blocked_total{name="test-name",namespace="test-namespace",reason="Exposure"} 2
+# HELP devfile_registry_operator_readiness_timeouts_total Number of times a DevfileRegistry server did not become ready in time.
+# TYPE devfile_registry_operator_readiness_timeouts_total counter
+devfile_registry_operator_readiness_timeouts_total{name="test-name",namespace="test-namespace"} 1
+`
+	if err := testutil.CollectAndCompare(registryInfo, strings.NewReader(expected), "devfile_registry_operator_devfile_registry_info"); err != nil {
+		t.Errorf("TestRegistryMetrics error: %v", err)
+	}
+	if err := testutil.CollectAndCompare(deploymentBlocked, strings.NewReader(expected), "devfile_registry_operator_deployment_blocked_total"); err != nil {
+		t.Errorf("TestRegistryMetrics error: %v", err)
+	}
+	if err := testutil.CollectAndCompare(readinessTimeouts, strings.NewReader(expected), "devfile_registry_operator_readiness_timeouts_total"); err != nil {
+		t.Errorf("TestRegistryMetrics error: %v", err)
+	}
+
+	DeleteRegistry("test-namespace", "test-name")
+	if count := testutil.CollectAndCount(registryInfo) + testutil.CollectAndCount(deploymentBlocked) + testutil.CollectAndCount(readinessTimeouts); count != 0 {
+		t.Errorf("TestRegistryMetrics error: expected no series after deleting the registry, got %d", count)
+	}
+}
@@ -0,0 +1,160 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/util"
+)
+
+const (
+	// Default number of snapshots of the registry volume kept by the scheduled backups
+	DefaultBackupRetention = int32(7)
+
+	// BackupScheduledAtAnnotation is set on the scheduled snapshots of the registry volume to the time they were
+	// scheduled at, in the RFC 3339 format
+	BackupScheduledAtAnnotation = "registry.devfile.io/backup-scheduled-at"
+
+	// RestoredFromAnnotation is set on the registry PVC to the source it was provisioned from, so that a new source
+	// set in the DevfileRegistry CR is restored once
+	RestoredFromAnnotation = "registry.devfile.io/restored-from"
+
+	backupLabel            = "devfileregistry_backup"
+	volumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+	volumeSnapshotKind     = "VolumeSnapshot"
+)
+
+// VolumeSnapshotGVK is the group, version and kind of the CSI volume snapshots. The snapshot CRDs are not installed
+// on every cluster, so the operator handles the snapshots as unstructured objects.
+var VolumeSnapshotGVK = schema.GroupVersionKind{Group: volumeSnapshotAPIGroup, Version: "v1", Kind: volumeSnapshotKind}
+
+// IsBackupEnabled returns true if snapshots of the devfile registry's persistent volume are scheduled
+func IsBackupEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return IsStorageEnabled(cr) && cr.Spec.Storage.Backup != nil
+}
+
+// GetBackupRetention returns the number of snapshots of the registry volume to keep
+// Default: 7
+func GetBackupRetention(cr *registryv1alpha1.DevfileRegistry) int {
+	if backup := cr.Spec.Storage.Backup; backup != nil && backup.Retention != nil {
+		return int(*backup.Retention)
+	}
+	return int(DefaultBackupRetention)
+}
+
+// GetBackupLabels returns the labels of the scheduled snapshots of the registry volume
+func GetBackupLabels(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	labels := LabelsForDevfileRegistry(cr)
+	labels[backupLabel] = "true"
+	return labels
+}
+
+// GetNextBackupTime returns the first time a snapshot of the registry volume is scheduled at after the given time
+func GetNextBackupTime(cr *registryv1alpha1.DevfileRegistry, after time.Time) (time.Time, error) {
+	if cr.Spec.Storage.Backup == nil {
+		return time.Time{}, nil
+	}
+	schedule, err := util.ParseSchedule(cr.Spec.Storage.Backup.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(after), nil
+}
+
+// GenerateVolumeSnapshot returns a snapshot of the registry volume scheduled at the given time. The snapshot is not
+// owned by the DevfileRegistry CR, so that the backups outlive the registry.
+func GenerateVolumeSnapshot(cr *registryv1alpha1.DevfileRegistry, scheduledAt time.Time) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	snapshot.SetName(VolumeSnapshotName(cr, scheduledAt))
+	snapshot.SetNamespace(cr.Namespace)
+	snapshot.SetLabels(GetBackupLabels(cr))
+	snapshot.SetAnnotations(map[string]string{BackupScheduledAtAnnotation: scheduledAt.UTC().Format(time.RFC3339)})
+
+	_ = unstructured.SetNestedField(snapshot.Object, PVCName(cr), "spec", "source", "persistentVolumeClaimName")
+	if className := cr.Spec.Storage.Backup.VolumeSnapshotClassName; className != nil {
+		_ = unstructured.SetNestedField(snapshot.Object, *className, "spec", "volumeSnapshotClassName")
+	}
+	return snapshot
+}
+
+// GetBackupScheduledAt returns the time the given snapshot was scheduled at, or the zero time if it is not a
+// scheduled snapshot
+func GetBackupScheduledAt(snapshot *unstructured.Unstructured) time.Time {
+	scheduledAt, err := time.Parse(time.RFC3339, snapshot.GetAnnotations()[BackupScheduledAtAnnotation])
+	if err != nil {
+		return time.Time{}
+	}
+	return scheduledAt
+}
+
+// SortBackups sorts the given snapshots from the oldest to the most recent
+func SortBackups(snapshots []unstructured.Unstructured) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return GetBackupScheduledAt(&snapshots[i]).Before(GetBackupScheduledAt(&snapshots[j]))
+	})
+}
+
+// GetExpiredBackups returns the snapshots exceeding the retention of the DevfileRegistry CR, the oldest first
+func GetExpiredBackups(cr *registryv1alpha1.DevfileRegistry, snapshots []unstructured.Unstructured) []unstructured.Unstructured {
+	retention := GetBackupRetention(cr)
+	if len(snapshots) <= retention {
+		return nil
+	}
+	sorted := append([]unstructured.Unstructured{}, snapshots...)
+	SortBackups(sorted)
+	return sorted[:len(sorted)-retention]
+}
+
+// GetRestoreSource returns the source the registry volume is provisioned from, as recorded in RestoredFromAnnotation,
+// or an empty string if none is set
+func GetRestoreSource(cr *registryv1alpha1.DevfileRegistry) string {
+	if dataSource := getPVCDataSource(cr); dataSource != nil {
+		return dataSource.Kind + "/" + dataSource.Name
+	}
+	return ""
+}
+
+// getPVCDataSource returns the data source of the registry PVC
+// Default: nil
+func getPVCDataSource(cr *registryv1alpha1.DevfileRegistry) *corev1.TypedLocalObjectReference {
+	restoreFrom := cr.Spec.Storage.RestoreFrom
+	switch {
+	case restoreFrom == nil:
+		return nil
+	case restoreFrom.VolumeSnapshot != "":
+		apiGroup := volumeSnapshotAPIGroup
+		return &corev1.TypedLocalObjectReference{
+			APIGroup: &apiGroup,
+			Kind:     volumeSnapshotKind,
+			Name:     restoreFrom.VolumeSnapshot,
+		}
+	case restoreFrom.PersistentVolumeClaim != "":
+		return &corev1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: restoreFrom.PersistentVolumeClaim,
+		}
+	}
+	return nil
+}
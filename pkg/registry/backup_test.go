@@ -0,0 +1,164 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+func TestGeneratePVCRestoreSource(t *testing.T) {
+	storageEnabled := true
+
+	tests := []struct {
+		name           string
+		restoreFrom    *registryv1alpha1.DevfileRegistrySpecStorageRestore
+		wantKind       string
+		wantName       string
+		wantAnnotation string
+	}{
+		{
+			name:        "Case 1: Empty volume",
+			restoreFrom: nil,
+		},
+		{
+			name:           "Case 2: Restored from a volume snapshot",
+			restoreFrom:    &registryv1alpha1.DevfileRegistrySpecStorageRestore{VolumeSnapshot: "devfile-registry-20240131-0300"},
+			wantKind:       "VolumeSnapshot",
+			wantName:       "devfile-registry-20240131-0300",
+			wantAnnotation: "VolumeSnapshot/devfile-registry-20240131-0300",
+		},
+		{
+			name:           "Case 3: Cloned from another registry volume",
+			restoreFrom:    &registryv1alpha1.DevfileRegistrySpecStorageRestore{PersistentVolumeClaim: "staging-devfile-registry"},
+			wantKind:       "PersistentVolumeClaim",
+			wantName:       "staging-devfile-registry",
+			wantAnnotation: "PersistentVolumeClaim/staging-devfile-registry",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+						Enabled:     &storageEnabled,
+						RestoreFrom: tt.restoreFrom,
+					},
+				},
+			}
+			pvc, err := GeneratePVC(cr, runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+			if err != nil {
+				t.Fatalf("TestGeneratePVCRestoreSource error: unexpected error %v", err)
+			}
+
+			if tt.wantKind == "" {
+				if pvc.Spec.DataSource != nil {
+					t.Errorf("TestGeneratePVCRestoreSource error: unexpected data source %v", pvc.Spec.DataSource)
+				}
+			} else if pvc.Spec.DataSource == nil || pvc.Spec.DataSource.Kind != tt.wantKind || pvc.Spec.DataSource.Name != tt.wantName {
+				t.Errorf("TestGeneratePVCRestoreSource error: data source mismatch, expected: %s/%s got: %v", tt.wantKind, tt.wantName, pvc.Spec.DataSource)
+			}
+			if got := pvc.Annotations[RestoredFromAnnotation]; got != tt.wantAnnotation {
+				t.Errorf("TestGeneratePVCRestoreSource error: annotation mismatch, expected: %q got: %q", tt.wantAnnotation, got)
+			}
+		})
+	}
+}
+
+func TestGenerateVolumeSnapshot(t *testing.T) {
+	className := "csi-snapclass"
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+				Backup: &registryv1alpha1.DevfileRegistrySpecStorageBackup{
+					Schedule:                "0 3 * * *",
+					VolumeSnapshotClassName: &className,
+				},
+			},
+		},
+	}
+	scheduledAt := time.Date(2024, 1, 31, 3, 0, 0, 0, time.UTC)
+
+	snapshot := GenerateVolumeSnapshot(cr, scheduledAt)
+	if snapshot.GetName() != "test-devfile-registry-20240131-0300" {
+		t.Errorf("TestGenerateVolumeSnapshot error: unexpected name %s", snapshot.GetName())
+	}
+	if len(snapshot.GetOwnerReferences()) > 0 {
+		t.Errorf("TestGenerateVolumeSnapshot error: snapshot is owned by %v", snapshot.GetOwnerReferences())
+	}
+	if source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName"); source != PVCName(cr) {
+		t.Errorf("TestGenerateVolumeSnapshot error: unexpected source %s", source)
+	}
+	if class, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName"); class != className {
+		t.Errorf("TestGenerateVolumeSnapshot error: unexpected volume snapshot class %s", class)
+	}
+	if got := GetBackupScheduledAt(snapshot); !got.Equal(scheduledAt) {
+		t.Errorf("TestGenerateVolumeSnapshot error: scheduled time mismatch, expected: %v got: %v", scheduledAt, got)
+	}
+}
+
+func TestGetExpiredBackups(t *testing.T) {
+	retention := int32(2)
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+				Backup: &registryv1alpha1.DevfileRegistrySpecStorageBackup{Schedule: "0 3 * * *", Retention: &retention},
+			},
+		},
+	}
+	day := func(d int) unstructured.Unstructured {
+		return *GenerateVolumeSnapshot(cr, time.Date(2024, 1, d, 3, 0, 0, 0, time.UTC))
+	}
+
+	tests := []struct {
+		name      string
+		snapshots []unstructured.Unstructured
+		want      []string
+	}{
+		{
+			name:      "Case 1: Within the retention",
+			snapshots: []unstructured.Unstructured{day(1), day(2)},
+			want:      nil,
+		},
+		{
+			name:      "Case 2: Oldest snapshots expired, in any order",
+			snapshots: []unstructured.Unstructured{day(4), day(1), day(3), day(2)},
+			want:      []string{"test-devfile-registry-20240101-0300", "test-devfile-registry-20240102-0300"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, snapshot := range GetExpiredBackups(cr, tt.snapshots) {
+				got = append(got, snapshot.GetName())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TestGetExpiredBackups error: expired snapshots mismatch, expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -17,29 +17,60 @@
 package registry
 
 import (
+	"crypto/sha256"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 )
 
-// GenerateRegistryConfigMap returns a configmap that is used to configure the devfile registry
-func GenerateRegistryConfigMap(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.ConfigMap {
+// RegistryConfigHashAnnotation is set on the registry pods to the hash of the OCI registry configuration they
+// were started with, so that the pods are rolled out when the configuration changes
+const RegistryConfigHashAnnotation = "registry.devfile.io/registry-config-hash"
+
+// GenerateRegistryConfigMap returns a configmap that is used to configure the devfile registry, with the given
+// OCI registry configuration
+func GenerateRegistryConfigMap(cr *registryv1alpha1.DevfileRegistry, registryConfig string, scheme *runtime.Scheme, labels map[string]string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: generateObjectMeta(ConfigMapName(cr), cr.Namespace, labels),
+		Data:       GetRegistryConfigMapData(cr, registryConfig),
+	}
+
+	UpdateUserMetadata(cm, GetUserLabels(cr, labels), GetConfigMapAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, cm, scheme)
+	return cm
+}
+
+// GetRegistryConfigMapData returns the data of the registry config map, with the given OCI registry configuration
+func GetRegistryConfigMapData(cr *registryv1alpha1.DevfileRegistry, registryConfig string) map[string]string {
 	configMapData := make(map[string]string, 0)
 
-	registryConfig := `
+	viewerEnvfile := fmt.Sprintf(`
+NEXT_PUBLIC_ANALYTICS_WRITE_KEY=%s
+DEVFILE_REGISTRIES=[{"name":"%s","url":"http://localhost:8080","fqdn":"%s"}]`,
+		cr.Spec.Telemetry.RegistryViewerWriteKey, cr.ObjectMeta.Name, cr.Status.URL)
+
+	configMapData["registry-config.yml"] = registryConfig
+	configMapData[".env.registry-viewer"] = viewerEnvfile
+	return configMapData
+}
+
+// GetDefaultRegistryConfig returns the configuration of the OCI registry generated by the operator
+func GetDefaultRegistryConfig(cr *registryv1alpha1.DevfileRegistry) string {
+	return fmt.Sprintf(`
 version: 0.1
 log:
   fields:
     service: registry
 storage:
   cache:
-    blobdescriptor: inmemory
-  filesystem:
-    rootdirectory: /var/lib/registry
+    blobdescriptor: inmemory%s
 http:
   addr: :5000
   headers:
@@ -48,22 +79,65 @@ http:
     addr: :5001
     prometheus:
       enabled: true
-      path: /metrics`
+      path: /metrics%s`, getOCIRegistryStorageConfig(cr), getOCIRegistryAuthConfig(cr))
+}
 
-	viewerEnvfile := fmt.Sprintf(`
-NEXT_PUBLIC_ANALYTICS_WRITE_KEY=%s
-DEVFILE_REGISTRIES=[{"name":"%s","url":"http://localhost:8080","fqdn":"%s"}]`,
-		cr.Spec.Telemetry.RegistryViewerWriteKey, cr.ObjectMeta.Name, cr.Status.URL)
+// MergeRegistryConfig deep-merges the user OCI registry configuration over the default one: mappings are merged
+// key by key and any other value set by the user replaces the default value. As the OCI registry only accepts one
+// storage driver, a storage driver set by the user replaces the default one.
+func MergeRegistryConfig(defaultConfig string, userConfig string) (string, error) {
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(defaultConfig), &config); err != nil {
+		return "", err
+	}
+	overlay := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(userConfig), &overlay); err != nil {
+		return "", err
+	}
 
-	configMapData["registry-config.yml"] = registryConfig
-	configMapData[".env.registry-viewer"] = viewerEnvfile
+	userStorage, _ := overlay["storage"].(map[string]interface{})
+	defaultStorage, _ := config["storage"].(map[string]interface{})
+	if len(registryv1alpha1.GetRegistryConfigStorageDrivers(userStorage)) > 0 {
+		for _, driver := range registryv1alpha1.GetRegistryConfigStorageDrivers(defaultStorage) {
+			delete(defaultStorage, driver)
+		}
+	}
+	mergeRegistryConfigValues(config, overlay)
 
-	cm := &corev1.ConfigMap{
-		ObjectMeta: generateObjectMeta(ConfigMapName(cr), cr.Namespace, labels),
-		Data:       configMapData,
+	merged, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
 	}
+	return string(merged), nil
+}
 
-	// Set DevfileRegistry instance as the owner and controller
-	_ = ctrl.SetControllerReference(cr, cm, scheme)
-	return cm
+// GetRegistryConfigHash returns the hash of the given OCI registry configuration
+func GetRegistryConfigHash(registryConfig string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(registryConfig)))
+}
+
+// SetRegistryConfigHash sets the hash of the given OCI registry configuration on the pod template,
+// returns true if it changed
+func SetRegistryConfigHash(template *corev1.PodTemplateSpec, registryConfig string) bool {
+	hash := GetRegistryConfigHash(registryConfig)
+	if template.Annotations[RegistryConfigHashAnnotation] == hash {
+		return false
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[RegistryConfigHashAnnotation] = hash
+	return true
+}
+
+func mergeRegistryConfigValues(values map[string]interface{}, overlay map[string]interface{}) {
+	for key, value := range overlay {
+		section, isSection := value.(map[string]interface{})
+		defaultSection, hasDefaultSection := values[key].(map[string]interface{})
+		if isSection && hasDefaultSection {
+			mergeRegistryConfigValues(defaultSection, section)
+			continue
+		}
+		values[key] = value
+	}
 }
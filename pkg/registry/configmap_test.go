@@ -0,0 +1,113 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestMergeRegistryConfig(t *testing.T) {
+	defaultConfig := GetDefaultRegistryConfig(&registryv1alpha1.DevfileRegistry{})
+
+	tests := []struct {
+		name       string
+		userConfig string
+		want       map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "Case 1: Sections merged key by key",
+			userConfig: "log:\n  level: debug\nstorage:\n  delete:\n    enabled: true\nhttp:\n  headers:\n    Access-Control-Allow-Origin: ['*']\n",
+			want: map[string]interface{}{
+				"version": 0.1,
+				"log": map[string]interface{}{
+					"level":  "debug",
+					"fields": map[string]interface{}{"service": "registry"},
+				},
+				"storage": map[string]interface{}{
+					"cache":      map[string]interface{}{"blobdescriptor": "inmemory"},
+					"filesystem": map[string]interface{}{"rootdirectory": "/var/lib/registry"},
+					"delete":     map[string]interface{}{"enabled": true},
+				},
+				"http": map[string]interface{}{
+					"addr": ":5000",
+					"headers": map[string]interface{}{
+						"X-Content-Type-Options":      []interface{}{"nosniff"},
+						"Access-Control-Allow-Origin": []interface{}{"*"},
+					},
+					"debug": map[string]interface{}{
+						"addr":       ":5001",
+						"prometheus": map[string]interface{}{"enabled": true, "path": "/metrics"},
+					},
+				},
+			},
+		},
+		{
+			name:       "Case 2: Storage driver replaced",
+			userConfig: "storage:\n  inmemory: {}\n",
+			want: map[string]interface{}{
+				"cache":    map[string]interface{}{"blobdescriptor": "inmemory"},
+				"inmemory": map[string]interface{}{},
+			},
+		},
+		{
+			name:       "Case 3: Invalid YAML",
+			userConfig: "storage: [",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeRegistryConfig(defaultConfig, tt.userConfig)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("TestMergeRegistryConfig error: unexpected error %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			config := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(merged), &config); err != nil {
+				t.Fatalf("TestMergeRegistryConfig error: invalid merged config: %v", err)
+			}
+			got := config
+			if _, ok := tt.want["version"]; !ok {
+				got = config["storage"].(map[string]interface{})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TestMergeRegistryConfig error: config mismatch, expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetRegistryConfigHash(t *testing.T) {
+	template := &corev1.PodTemplateSpec{}
+	if !SetRegistryConfigHash(template, "version: 0.1") {
+		t.Errorf("TestSetRegistryConfigHash error: hash not set")
+	}
+	if SetRegistryConfigHash(template, "version: 0.1") {
+		t.Errorf("TestSetRegistryConfigHash error: hash changed for the same config")
+	}
+	if !SetRegistryConfigHash(template, "version: 0.1\nlog:\n  level: debug") {
+		t.Errorf("TestSetRegistryConfigHash error: hash not changed with the config")
+	}
+}
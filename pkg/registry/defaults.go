@@ -18,9 +18,11 @@ package registry
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -30,22 +32,50 @@ const (
 	DefaultDevfileIndexImage   = "quay.io/devfile/devfile-index:next"
 	DefaultRegistryViewerImage = "quay.io/devfile/registry-viewer:next"
 	DefaultOCIRegistryImage    = "quay.io/devfile/oci-registry:next"
+	DefaultIndexBuilderImage   = "quay.io/devfile/registry-index-builder:next"
+
+	// Environment variables of the operator overriding the default images, set by OLM to the pinned related images
+	DevfileIndexImageEnvVar   = "RELATED_IMAGE_DEVFILE_INDEX"
+	RegistryViewerImageEnvVar = "RELATED_IMAGE_REGISTRY_VIEWER"
+	OCIRegistryImageEnvVar    = "RELATED_IMAGE_OCI_REGISTRY"
+	IndexBuilderImageEnvVar   = "RELATED_IMAGE_INDEX_BUILDER"
 
 	// Default image pull policies
 	DefaultDevfileIndexImagePullPolicy   = corev1.PullAlways
 	DefaultRegistryViewerImagePullPolicy = corev1.PullAlways
 	DefaultOCIRegistryImagePullPolicy    = corev1.PullAlways
+	DefaultIndexBuilderImagePullPolicy   = corev1.PullIfNotPresent
 
 	// Default memory limits
 	DefaultDevfileIndexMemoryLimit   = "256Mi"
 	DefaultRegistryViewerMemoryLimit = "256Mi"
 	DefaultOCIRegistryMemoryLimit    = "256Mi"
 
+	// Default CPU limits
+	DefaultDevfileIndexCPULimit   = "500m"
+	DefaultRegistryViewerCPULimit = "500m"
+	DefaultOCIRegistryCPULimit    = "500m"
+
+	// Default memory requests
+	DefaultDevfileIndexMemoryRequest   = "64Mi"
+	DefaultRegistryViewerMemoryRequest = "64Mi"
+	DefaultOCIRegistryMemoryRequest    = "64Mi"
+
+	// Default CPU requests
+	DefaultDevfileIndexCPURequest   = "250m"
+	DefaultRegistryViewerCPURequest = "250m"
+	DefaultOCIRegistryCPURequest    = "100m"
+
 	// Defaults/constants for devfile registry storages
 	DefaultDevfileRegistryVolumeSize = "1Gi"
 	DevfileRegistryVolumeEnabled     = false
 	DevfileRegistryVolumeName        = "devfile-registry-storage"
 
+	// Defaults/constants for high-availability mode
+	DefaultReplicas                       = int32(1)
+	DefaultPodDisruptionBudgetUnavailable = 1
+	DefaultPodAntiAffinityWeight          = int32(100)
+
 	DevfileRegistryTLSEnabled       = true
 	DevfileRegistryTelemetryEnabled = false
 
@@ -58,7 +88,9 @@ const (
 	DevfileIndexMetricsPort     = 7071
 	OCIMetricsPortName          = "oci-registry-metrics"
 	OCIMetricsPort              = 5001
+	OCIServerPortName           = "oci-registry"
 	OCIServerPort               = 5000
+	RegistryViewerPortName      = "registry-viewer"
 	RegistryViewerPort          = 3000
 
 	// Default kubernetes-only fields
@@ -74,21 +106,24 @@ const (
 )
 
 // GetRegistryViewerImage returns the container image for the registry viewer to be deployed on the Devfile Registry.
-// Default: "quay.io/devfile/registry-viewer:next"
-func GetRegistryViewerImage(cr *registryv1alpha1.DevfileRegistry) string {
+// Default: the registryViewer image of the RegistryOperatorConfig, $RELATED_IMAGE_REGISTRY_VIEWER, or
+// "quay.io/devfile/registry-viewer:next" if unset
+func GetRegistryViewerImage(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) string {
 	if cr.Spec.RegistryViewer.Image != "" {
-		return cr.Spec.RegistryViewer.Image
+		return RewriteImage(defaults, cr.Spec.RegistryViewer.Image)
 	} else if cr.Spec.RegistryViewerImage != "" {
-		return cr.Spec.RegistryViewerImage
+		return RewriteImage(defaults, cr.Spec.RegistryViewerImage)
 	}
-	return DefaultRegistryViewerImage
+	return getDefaultImage(defaults, defaults.OperatorConfig.RegistryViewer.Image, RegistryViewerImageEnvVar, DefaultRegistryViewerImage)
 }
 
 // GetRegistryViewerImagePullPolicy returns the image pull policy for the registry viewer container.
-// Default: "Always"
-func GetRegistryViewerImagePullPolicy(cr *registryv1alpha1.DevfileRegistry) corev1.PullPolicy {
+// Default: the image pull policy of the RegistryOperatorConfig, or "Always" if unset
+func GetRegistryViewerImagePullPolicy(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) corev1.PullPolicy {
 	if cr.Spec.RegistryViewer.ImagePullPolicy != "" {
 		return cr.Spec.RegistryViewer.ImagePullPolicy
+	} else if policy := defaults.OperatorConfig.RegistryViewer.ImagePullPolicy; policy != "" {
+		return policy
 	}
 	return DefaultRegistryViewerImagePullPolicy
 }
@@ -100,22 +135,40 @@ func GetRegistryViewerMemoryLimit(cr *registryv1alpha1.DevfileRegistry) resource
 	return getDevfileRegistrySpecContainer(cr.Spec.RegistryViewer.MemoryLimit, DefaultRegistryViewerMemoryLimit)
 }
 
+// GetRegistryViewerResources returns the compute resource requests and limits for the registry viewer container.
+// Default: requests {cpu: "250m", memory: "64Mi"}, limits {cpu: "500m", memory: GetRegistryViewerMemoryLimit}
+func GetRegistryViewerResources(cr *registryv1alpha1.DevfileRegistry) corev1.ResourceRequirements {
+	return getDevfileRegistrySpecContainerResources(cr.Spec.RegistryViewer.Resources, corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultRegistryViewerCPURequest),
+			corev1.ResourceMemory: resource.MustParse(DefaultRegistryViewerMemoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultRegistryViewerCPULimit),
+			corev1.ResourceMemory: GetRegistryViewerMemoryLimit(cr),
+		},
+	})
+}
+
 // GetOCIRegistryImage returns the container image for the OCI registry to be deployed on the Devfile Registry.
-// Default: "quay.io/devfile/oci-registry:next"
-func GetOCIRegistryImage(cr *registryv1alpha1.DevfileRegistry) string {
+// Default: the ociRegistry image of the RegistryOperatorConfig, $RELATED_IMAGE_OCI_REGISTRY, or
+// "quay.io/devfile/oci-registry:next" if unset
+func GetOCIRegistryImage(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) string {
 	if cr.Spec.OciRegistry.Image != "" {
-		return cr.Spec.OciRegistry.Image
+		return RewriteImage(defaults, cr.Spec.OciRegistry.Image)
 	} else if cr.Spec.OciRegistryImage != "" {
-		return cr.Spec.OciRegistryImage
+		return RewriteImage(defaults, cr.Spec.OciRegistryImage)
 	}
-	return DefaultOCIRegistryImage
+	return getDefaultImage(defaults, defaults.OperatorConfig.OciRegistry.Image, OCIRegistryImageEnvVar, DefaultOCIRegistryImage)
 }
 
 // GetOCIRegistryImagePullPolicy returns the image pull policy for the OCI registry container.
-// Default: "Always"
-func GetOCIRegistryImagePullPolicy(cr *registryv1alpha1.DevfileRegistry) corev1.PullPolicy {
+// Default: the image pull policy of the RegistryOperatorConfig, or "Always" if unset
+func GetOCIRegistryImagePullPolicy(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) corev1.PullPolicy {
 	if cr.Spec.OciRegistry.ImagePullPolicy != "" {
 		return cr.Spec.OciRegistry.ImagePullPolicy
+	} else if policy := defaults.OperatorConfig.OciRegistry.ImagePullPolicy; policy != "" {
+		return policy
 	}
 	return DefaultOCIRegistryImagePullPolicy
 }
@@ -127,22 +180,40 @@ func GetOCIRegistryMemoryLimit(cr *registryv1alpha1.DevfileRegistry) resource.Qu
 	return getDevfileRegistrySpecContainer(cr.Spec.OciRegistry.MemoryLimit, DefaultOCIRegistryMemoryLimit)
 }
 
+// GetOCIRegistryResources returns the compute resource requests and limits for the OCI registry container.
+// Default: requests {cpu: "100m", memory: "64Mi"}, limits {cpu: "500m", memory: GetOCIRegistryMemoryLimit}
+func GetOCIRegistryResources(cr *registryv1alpha1.DevfileRegistry) corev1.ResourceRequirements {
+	return getDevfileRegistrySpecContainerResources(cr.Spec.OciRegistry.Resources, corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultOCIRegistryCPURequest),
+			corev1.ResourceMemory: resource.MustParse(DefaultOCIRegistryMemoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultOCIRegistryCPULimit),
+			corev1.ResourceMemory: GetOCIRegistryMemoryLimit(cr),
+		},
+	})
+}
+
 // GetDevfileIndexImage returns the container image for the devfile index server to be deployed on the Devfile Registry.
-// Default: "quay.io/devfile/devfile-index:next"
-func GetDevfileIndexImage(cr *registryv1alpha1.DevfileRegistry) string {
+// Default: the devfileIndex image of the RegistryOperatorConfig, $RELATED_IMAGE_DEVFILE_INDEX, or
+// "quay.io/devfile/devfile-index:next" if unset
+func GetDevfileIndexImage(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) string {
 	if cr.Spec.DevfileIndex.Image != "" {
-		return cr.Spec.DevfileIndex.Image
+		return RewriteImage(defaults, cr.Spec.DevfileIndex.Image)
 	} else if cr.Spec.DevfileIndexImage != "" {
-		return cr.Spec.DevfileIndexImage
+		return RewriteImage(defaults, cr.Spec.DevfileIndexImage)
 	}
-	return DefaultDevfileIndexImage
+	return getDefaultImage(defaults, defaults.OperatorConfig.DevfileIndex.Image, DevfileIndexImageEnvVar, DefaultDevfileIndexImage)
 }
 
 // GetDevfileIndexImagePullPolicy returns the image pull policy for the devfile index container.
-// Default: "Always"
-func GetDevfileIndexImagePullPolicy(cr *registryv1alpha1.DevfileRegistry) corev1.PullPolicy {
+// Default: the image pull policy of the RegistryOperatorConfig, or "Always" if unset
+func GetDevfileIndexImagePullPolicy(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) corev1.PullPolicy {
 	if cr.Spec.DevfileIndex.ImagePullPolicy != "" {
 		return cr.Spec.DevfileIndex.ImagePullPolicy
+	} else if policy := defaults.OperatorConfig.DevfileIndex.ImagePullPolicy; policy != "" {
+		return policy
 	}
 	return DefaultDevfileIndexImagePullPolicy
 }
@@ -154,13 +225,58 @@ func GetDevfileIndexMemoryLimit(cr *registryv1alpha1.DevfileRegistry) resource.Q
 	return getDevfileRegistrySpecContainer(cr.Spec.DevfileIndex.MemoryLimit, DefaultDevfileIndexMemoryLimit)
 }
 
-func getDevfileRegistryVolumeSize(cr *registryv1alpha1.DevfileRegistry) string {
+// GetDevfileIndexResources returns the compute resource requests and limits for the devfile index container.
+// Default: requests {cpu: "250m", memory: "64Mi"}, limits {cpu: "500m", memory: GetDevfileIndexMemoryLimit}
+func GetDevfileIndexResources(cr *registryv1alpha1.DevfileRegistry) corev1.ResourceRequirements {
+	return getDevfileRegistrySpecContainerResources(cr.Spec.DevfileIndex.Resources, corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultDevfileIndexCPURequest),
+			corev1.ResourceMemory: resource.MustParse(DefaultDevfileIndexMemoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultDevfileIndexCPULimit),
+			corev1.ResourceMemory: GetDevfileIndexMemoryLimit(cr),
+		},
+	})
+}
+
+// GetDevfileRegistryVolumeSize returns the size requested for the devfile registry's persistent volume.
+// Default: 1Gi
+func GetDevfileRegistryVolumeSize(cr *registryv1alpha1.DevfileRegistry) string {
 	if cr.Spec.Storage.RegistryVolumeSize != "" {
 		return cr.Spec.Storage.RegistryVolumeSize
 	}
 	return DefaultDevfileRegistryVolumeSize
 }
 
+// getDevfileRegistryVolumeAccessModes returns the access modes requested for the devfile registry's persistent volume.
+// Default: ["ReadWriteOnce"]
+func getDevfileRegistryVolumeAccessModes(cr *registryv1alpha1.DevfileRegistry) []corev1.PersistentVolumeAccessMode {
+	if len(cr.Spec.Storage.AccessModes) > 0 {
+		return cr.Spec.Storage.AccessModes
+	}
+	return []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+}
+
+// getDevfileRegistryVolumeStorageClassName returns the storage class requested for the devfile registry's persistent volume.
+// Default: nil, the default storage class of the cluster is used
+func getDevfileRegistryVolumeStorageClassName(cr *registryv1alpha1.DevfileRegistry) *string {
+	if cr.Spec.Storage.StorageClassName != nil && *cr.Spec.Storage.StorageClassName != "" {
+		return cr.Spec.Storage.StorageClassName
+	}
+	return nil
+}
+
+// isStorageShareable returns true if the devfile registry's persistent volume can be mounted by pods on different nodes
+func isStorageShareable(cr *registryv1alpha1.DevfileRegistry) bool {
+	for _, accessMode := range getDevfileRegistryVolumeAccessModes(cr) {
+		if accessMode == corev1.ReadWriteMany {
+			return true
+		}
+	}
+	return false
+}
+
 func GetDevfileRegistryVolumeSource(cr *registryv1alpha1.DevfileRegistry) corev1.VolumeSource {
 	if IsStorageEnabled(cr) {
 		return corev1.VolumeSource{
@@ -173,15 +289,58 @@ func GetDevfileRegistryVolumeSource(cr *registryv1alpha1.DevfileRegistry) corev1
 	return corev1.VolumeSource{}
 }
 
+// GetReplicas returns the number of registry pods to deploy. If persistent storage is enabled with a volume that
+// cannot be shared between nodes, a single pod is deployed regardless of the number of replicas requested.
+// Default: 1
+func GetReplicas(cr *registryv1alpha1.DevfileRegistry) int32 {
+	if IsReplicasLimitedByStorage(cr) {
+		return DefaultReplicas
+	}
+	return getRequestedReplicas(cr)
+}
+
+// IsReplicasLimitedByStorage returns true if more than one replica is requested in the DevfileRegistry CR, but
+// persistent storage is enabled with a volume that cannot be shared between nodes.
+func IsReplicasLimitedByStorage(cr *registryv1alpha1.DevfileRegistry) bool {
+	return getRequestedReplicas(cr) > 1 && IsStorageEnabled(cr) && !isStorageShareable(cr)
+}
+
+// IsHighAvailabilityEnabled returns true if more than one registry pod is deployed
+func IsHighAvailabilityEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return GetReplicas(cr) > 1
+}
+
+func getRequestedReplicas(cr *registryv1alpha1.DevfileRegistry) int32 {
+	if cr.Spec.Replicas != nil {
+		return *cr.Spec.Replicas
+	}
+	return DefaultReplicas
+}
+
 // GetK8sIngressClass returns ingress class used for the k8s ingress class field.
-// Default: "nginx"
-func GetK8sIngressClass(cr *registryv1alpha1.DevfileRegistry) string {
+// Default: the ingress class of the RegistryOperatorConfig, the default IngressClass of the cluster, or "nginx" if unset
+func GetK8sIngressClass(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) string {
 	if cr.Spec.K8s.IngressClass != "" {
 		return cr.Spec.K8s.IngressClass
+	} else if ingressClass := defaults.OperatorConfig.IngressClass; ingressClass != "" {
+		return ingressClass
+	} else if ingressClass := defaults.ClusterIngressClass; ingressClass != "" {
+		return ingressClass
 	}
 	return DefaultK8sIngressClass
 }
 
+// GetIngressDomain returns the ingress domain of the devfile registry.
+// Default: the ingress domain of the RegistryOperatorConfig, the apps domain of the cluster on OpenShift, or "" if unset
+func GetIngressDomain(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) string {
+	if cr.Spec.K8s.IngressDomain != "" {
+		return cr.Spec.K8s.IngressDomain
+	} else if ingressDomain := defaults.OperatorConfig.IngressDomain; ingressDomain != "" {
+		return ingressDomain
+	}
+	return defaults.ClusterIngressDomain
+}
+
 // GetHostnameOverride returns hostname override used to override the hostname and domain of a devfile registry
 // Default: ""
 func GetHostnameOverride(cr *registryv1alpha1.DevfileRegistry) string {
@@ -222,10 +381,12 @@ func IsStorageEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
 }
 
 // IsTLSEnabled returns true if tls.enabled is set in the DevfileRegistry CR
-// If it's not set, it returns true by default.
-func IsTLSEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+// If it's not set, it returns tlsEnabled of the RegistryOperatorConfig, or true by default.
+func IsTLSEnabled(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) bool {
 	if cr.Spec.TLS.Enabled != nil {
 		return *cr.Spec.TLS.Enabled
+	} else if enabled := defaults.OperatorConfig.TLSEnabled; enabled != nil {
+		return *enabled
 	}
 	return DevfileRegistryTLSEnabled
 }
@@ -258,6 +419,34 @@ func getDevfileRegistrySpecContainer(quantity string, defaultValue string) resou
 	return resource.MustParse(defaultValue)
 }
 
+// getDevfileRegistrySpecContainerResources sets the requests and limits given for a container on top of its defaults.
+// Defaults conflicting with a given value are adjusted to keep the requests within the limits.
+func getDevfileRegistrySpecContainerResources(resources *corev1.ResourceRequirements, defaults corev1.ResourceRequirements) corev1.ResourceRequirements {
+	if resources == nil {
+		return defaults
+	}
+
+	for name, quantity := range resources.Requests {
+		defaults.Requests[name] = quantity.DeepCopy()
+		if limit, found := defaults.Limits[name]; found && limit.Cmp(quantity) < 0 {
+			if _, set := resources.Limits[name]; !set {
+				defaults.Limits[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	for name, quantity := range resources.Limits {
+		defaults.Limits[name] = quantity.DeepCopy()
+		if request, found := defaults.Requests[name]; found && request.Cmp(quantity) > 0 {
+			if _, set := resources.Requests[name]; !set {
+				defaults.Requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	defaults.Claims = resources.Claims
+
+	return defaults
+}
+
 // getAppName returns app name of a devfile registry
 // truncated to 63 characters max, if `DevfileRegistry.NameOverride`
 // is set it will return the override name truncated to 63 characters max
@@ -299,11 +488,57 @@ func getAppFullName(cr *registryv1alpha1.DevfileRegistry) string {
 	return truncateName(DefaultAppName)
 }
 
-// IsIngressSkipped returns true if no Ingress is set in the DevfileRegistry CR.
+// IsIngressSkipped returns true if no ingress domain is set in the DevfileRegistry CR or the RegistryOperatorConfig,
+// and none is discovered on the cluster.
 // If cr does not exist return true by default as no Ingress resource should be created
-func IsIngressSkipped(cr *registryv1alpha1.DevfileRegistry) bool {
+func IsIngressSkipped(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) bool {
 	if cr != nil {
-		return cr.Spec.K8s.IngressDomain == ""
+		return GetIngressDomain(cr, defaults) == ""
 	}
 	return true
 }
+
+// GetIndexBuilderImage returns the container image building the registry index from a Git source.
+// Default: the indexBuilder image of the RegistryOperatorConfig, $RELATED_IMAGE_INDEX_BUILDER, or
+// "quay.io/devfile/registry-index-builder:next" if unset
+func GetIndexBuilderImage(defaults config.Defaults) string {
+	return getDefaultImage(defaults, defaults.OperatorConfig.IndexBuilder.Image, IndexBuilderImageEnvVar, DefaultIndexBuilderImage)
+}
+
+// GetIndexBuilderImagePullPolicy returns the image pull policy of the container building the registry index.
+// Default: the image pull policy of the RegistryOperatorConfig, or "IfNotPresent" if unset
+func GetIndexBuilderImagePullPolicy(defaults config.Defaults) corev1.PullPolicy {
+	if policy := defaults.OperatorConfig.IndexBuilder.ImagePullPolicy; policy != "" {
+		return policy
+	}
+	return DefaultIndexBuilderImagePullPolicy
+}
+
+// getDefaultImage returns the image set in the RegistryOperatorConfig, in the given environment variable of the
+// operator, or the given default image if both are unset, with its prefix rewritten
+func getDefaultImage(defaults config.Defaults, configImage string, envVar string, defaultImage string) string {
+	if configImage != "" {
+		return RewriteImage(defaults, configImage)
+	}
+	if image := os.Getenv(envVar); image != "" {
+		return RewriteImage(defaults, image)
+	}
+	return RewriteImage(defaults, defaultImage)
+}
+
+// RewriteImage replaces the longest prefix of the given image matching one of the image prefix rewrites of the
+// operator, so that the images of the registries are pulled from a mirror. Returns the image unchanged if no
+// prefix matches.
+func RewriteImage(defaults config.Defaults, image string) string {
+	rewrites := defaults.GetImagePrefixRewrites()
+	longest := ""
+	for prefix := range rewrites {
+		if strings.HasPrefix(image, prefix) && len(prefix) > len(longest) {
+			longest = prefix
+		}
+	}
+	if longest == "" {
+		return image
+	}
+	return rewrites[longest] + strings.TrimPrefix(image, longest)
+}
@@ -22,9 +22,12 @@ import (
 	"testing"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func TestIsTLSEnabled(t *testing.T) {
@@ -68,7 +71,7 @@ func TestIsTLSEnabled(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tlsSetting := IsTLSEnabled(&tt.cr)
+			tlsSetting := IsTLSEnabled(&tt.cr, config.Defaults{})
 			if tlsSetting != tt.want {
 				t.Errorf("TestIsTLSEnabled error: tls value mismatch, expected: %v got: %v", tt.want, tlsSetting)
 			}
@@ -244,8 +247,10 @@ func TestGetOCIRegistryMemoryLimit(t *testing.T) {
 			name: "Case 1: Memory Limit size set in DevfileRegistry CR",
 			cr: registryv1alpha1.DevfileRegistry{
 				Spec: registryv1alpha1.DevfileRegistrySpec{
-					OciRegistry: registryv1alpha1.DevfileRegistrySpecContainer{
-						MemoryLimit: "5Gi",
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+						DevfileRegistrySpecContainer: registryv1alpha1.DevfileRegistrySpecContainer{
+							MemoryLimit: "5Gi",
+						},
 					},
 				},
 			},
@@ -255,7 +260,7 @@ func TestGetOCIRegistryMemoryLimit(t *testing.T) {
 			name: "Case 2:  Memory Limit size not set in DevfileRegistry CR",
 			cr: registryv1alpha1.DevfileRegistry{
 				Spec: registryv1alpha1.DevfileRegistrySpec{
-					OciRegistry: registryv1alpha1.DevfileRegistrySpecContainer{},
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{},
 				},
 			},
 			want: resource.MustParse(DefaultOCIRegistryMemoryLimit),
@@ -337,7 +342,7 @@ func TestGetDevfileRegistryVolumeSize(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			volSize := getDevfileRegistryVolumeSize(&tt.cr)
+			volSize := GetDevfileRegistryVolumeSize(&tt.cr)
 			if volSize != tt.want {
 				t.Errorf("TestGetDevfileRegistryVolumeSize error: storage size mismatch, expected: %v got: %v", tt.want, volSize)
 			}
@@ -436,6 +441,255 @@ func Test_getDevfileRegistrySpecContainer(t *testing.T) {
 	}
 }
 
+func TestGetDevfileIndexResources(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   registryv1alpha1.DevfileRegistry
+		want corev1.ResourceRequirements
+	}{
+		{
+			name: "Case 1: Resources not set in DevfileRegistry CR",
+			cr:   registryv1alpha1.DevfileRegistry{},
+			want: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(DefaultDevfileIndexCPURequest),
+					corev1.ResourceMemory: resource.MustParse(DefaultDevfileIndexMemoryRequest),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(DefaultDevfileIndexCPULimit),
+					corev1.ResourceMemory: resource.MustParse(DefaultDevfileIndexMemoryLimit),
+				},
+			},
+		},
+		{
+			name: "Case 2: Memory Limit set in DevfileRegistry CR",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					DevfileIndex: registryv1alpha1.DevfileRegistrySpecContainer{
+						MemoryLimit: "1Gi",
+					},
+				},
+			},
+			want: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(DefaultDevfileIndexCPURequest),
+					corev1.ResourceMemory: resource.MustParse(DefaultDevfileIndexMemoryRequest),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(DefaultDevfileIndexCPULimit),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+		{
+			name: "Case 3: Resources set in DevfileRegistry CR take precedence over Memory Limit",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					DevfileIndex: registryv1alpha1.DevfileRegistrySpecContainer{
+						MemoryLimit: "1Gi",
+						Resources: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("1"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("2Gi"),
+							},
+						},
+					},
+				},
+			},
+			want: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse(DefaultDevfileIndexMemoryRequest),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+		},
+		{
+			name: "Case 4: Limit set in DevfileRegistry CR below the default request",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					DevfileIndex: registryv1alpha1.DevfileRegistrySpecContainer{
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("100m"),
+							},
+						},
+					},
+				},
+			},
+			want: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse(DefaultDevfileIndexMemoryRequest),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse(DefaultDevfileIndexMemoryLimit),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetDevfileIndexResources(&tt.cr)
+			if !equality.Semantic.DeepEqual(result, tt.want) {
+				t.Errorf("TestGetDevfileIndexResources error: resources mismatch, expected: %v got: %v", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestGetOCIRegistryResources(t *testing.T) {
+	cr := registryv1alpha1.DevfileRegistry{
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+				DevfileRegistrySpecContainer: registryv1alpha1.DevfileRegistrySpecContainer{
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+					},
+				},
+			},
+		},
+	}
+	want := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultOCIRegistryCPURequest),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultOCIRegistryCPULimit),
+			corev1.ResourceMemory: resource.MustParse(DefaultOCIRegistryMemoryLimit),
+		},
+	}
+
+	result := GetOCIRegistryResources(&cr)
+	if !equality.Semantic.DeepEqual(result, want) {
+		t.Errorf("TestGetOCIRegistryResources error: resources mismatch, expected: %v got: %v", want, result)
+	}
+}
+
+func TestGetRegistryViewerResources(t *testing.T) {
+	cr := registryv1alpha1.DevfileRegistry{
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			RegistryViewer: registryv1alpha1.DevfileRegistrySpecContainer{
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("512Mi"),
+					},
+				},
+			},
+		},
+	}
+	want := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultRegistryViewerCPURequest),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultRegistryViewerCPULimit),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+
+	result := GetRegistryViewerResources(&cr)
+	if !equality.Semantic.DeepEqual(result, want) {
+		t.Errorf("TestGetRegistryViewerResources error: resources mismatch, expected: %v got: %v", want, result)
+	}
+}
+
+func TestGetReplicas(t *testing.T) {
+	storageEnabled := true
+	one := int32(1)
+	three := int32(3)
+
+	tests := []struct {
+		name        string
+		cr          registryv1alpha1.DevfileRegistry
+		want        int32
+		wantLimited bool
+		wantHA      bool
+	}{
+		{
+			name: "Case 1: Replicas not set in DevfileRegistry CR",
+			cr:   registryv1alpha1.DevfileRegistry{},
+			want: DefaultReplicas,
+		},
+		{
+			name: "Case 2: Single replica set in DevfileRegistry CR",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Replicas: &one,
+				},
+			},
+			want: 1,
+		},
+		{
+			name: "Case 3: Multiple replicas set in DevfileRegistry CR",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Replicas: &three,
+				},
+			},
+			want:   3,
+			wantHA: true,
+		},
+		{
+			name: "Case 4: Multiple replicas set in DevfileRegistry CR with storage enabled",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Replicas: &three,
+					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+						Enabled: &storageEnabled,
+					},
+				},
+			},
+			want:        1,
+			wantLimited: true,
+		},
+		{
+			name: "Case 5: Multiple replicas set in DevfileRegistry CR with ReadWriteMany storage enabled",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Replicas: &three,
+					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+						Enabled:     &storageEnabled,
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+					},
+				},
+			},
+			want:   3,
+			wantHA: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas := GetReplicas(&tt.cr)
+			if replicas != tt.want {
+				t.Errorf("TestGetReplicas error: replicas mismatch, expected: %v got: %v", tt.want, replicas)
+			}
+			limited := IsReplicasLimitedByStorage(&tt.cr)
+			if limited != tt.wantLimited {
+				t.Errorf("TestGetReplicas error: limited by storage mismatch, expected: %v got: %v", tt.wantLimited, limited)
+			}
+			ha := IsHighAvailabilityEnabled(&tt.cr)
+			if ha != tt.wantHA {
+				t.Errorf("TestGetReplicas error: high-availability mismatch, expected: %v got: %v", tt.wantHA, ha)
+			}
+			affinity := GetAffinity(&tt.cr)
+			if (affinity != nil) != tt.wantHA {
+				t.Errorf("TestGetReplicas error: affinity mismatch, expected set: %v got: %v", tt.wantHA, affinity)
+			}
+		})
+	}
+}
+
 func TestGetK8sIngressClass(t *testing.T) {
 	tests := []struct {
 		name string
@@ -465,7 +719,7 @@ func TestGetK8sIngressClass(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := GetK8sIngressClass(&tt.cr)
+			result := GetK8sIngressClass(&tt.cr, config.Defaults{})
 			if result != tt.want {
 				t.Errorf("func TestGetK8sIngressClass(t *testing.T) {\n error: enablement value mismatch, expected: %v got: %v", tt.want, result)
 			}
@@ -750,7 +1004,7 @@ func TestIsIngressSkipped(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ingressSkipped := IsIngressSkipped(tt.cr)
+			ingressSkipped := IsIngressSkipped(tt.cr, config.Defaults{})
 			if ingressSkipped != tt.want {
 				t.Errorf("TestIsIngressSkipped error: value mismatch, expected: %v got: %v", tt.want, ingressSkipped)
 			}
@@ -758,3 +1012,263 @@ func TestIsIngressSkipped(t *testing.T) {
 	}
 
 }
+
+func TestGetDefaultImages(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		rewrites string
+		cr       registryv1alpha1.DevfileRegistry
+		want     []string
+	}{
+		{
+			name: "Case 1: Hard-coded default images",
+			want: []string{DefaultDevfileIndexImage, DefaultOCIRegistryImage, DefaultRegistryViewerImage, DefaultIndexBuilderImage},
+		},
+		{
+			name: "Case 2: Related images of the operator",
+			env: map[string]string{
+				DevfileIndexImageEnvVar:   "quay.io/devfile/devfile-index@sha256:1111",
+				OCIRegistryImageEnvVar:    "quay.io/devfile/oci-registry@sha256:2222",
+				RegistryViewerImageEnvVar: "quay.io/devfile/registry-viewer@sha256:3333",
+				IndexBuilderImageEnvVar:   "quay.io/devfile/registry-index-builder@sha256:4444",
+			},
+			want: []string{
+				"quay.io/devfile/devfile-index@sha256:1111",
+				"quay.io/devfile/oci-registry@sha256:2222",
+				"quay.io/devfile/registry-viewer@sha256:3333",
+				"quay.io/devfile/registry-index-builder@sha256:4444",
+			},
+		},
+		{
+			name: "Case 3: Related images rewritten to a mirror",
+			env: map[string]string{
+				DevfileIndexImageEnvVar: "quay.io/devfile/devfile-index@sha256:1111",
+			},
+			rewrites: "quay.io/=mirror.example.com/quay/, quay.io/devfile/=mirror.example.com/devfile/,registry.access.redhat.com/=mirror.example.com/",
+			want: []string{
+				"mirror.example.com/devfile/devfile-index@sha256:1111",
+				"mirror.example.com/devfile/oci-registry:next",
+				"mirror.example.com/devfile/registry-viewer:next",
+				"mirror.example.com/devfile/registry-index-builder:next",
+			},
+		},
+		{
+			name: "Case 4: Images of the DevfileRegistry CR rewritten to a mirror",
+			env: map[string]string{
+				DevfileIndexImageEnvVar: "quay.io/devfile/devfile-index@sha256:1111",
+			},
+			rewrites: "quay.io/=mirror.example.com/quay/",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					DevfileIndex: registryv1alpha1.DevfileRegistrySpecContainer{Image: "quay.io/test/devfile-index:next"},
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+						DevfileRegistrySpecContainer: registryv1alpha1.DevfileRegistrySpecContainer{Image: "docker.io/library/registry:2"},
+					},
+					RegistryViewer: registryv1alpha1.DevfileRegistrySpecContainer{Image: "quay.io/test/registry-viewer:next"},
+				},
+			},
+			want: []string{
+				"mirror.example.com/quay/test/devfile-index:next",
+				"docker.io/library/registry:2",
+				"mirror.example.com/quay/test/registry-viewer:next",
+				"mirror.example.com/quay/devfile/registry-index-builder:next",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, envVar := range []string{DevfileIndexImageEnvVar, OCIRegistryImageEnvVar, RegistryViewerImageEnvVar, IndexBuilderImageEnvVar} {
+				t.Setenv(envVar, tt.env[envVar])
+			}
+			rewrites, err := config.ParseImagePrefixRewrites(tt.rewrites)
+			if err != nil {
+				t.Fatalf("TestGetDefaultImages error: unexpected error %v", err)
+			}
+			defaults := config.Defaults{EnvImagePrefixRewrites: rewrites}
+
+			got := []string{GetDevfileIndexImage(&tt.cr, defaults), GetOCIRegistryImage(&tt.cr, defaults), GetRegistryViewerImage(&tt.cr, defaults), GetIndexBuilderImage(defaults)}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TestGetDefaultImages error: images mismatch, expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := config.ParseImagePrefixRewrites("quay.io/devfile/"); err == nil {
+		t.Errorf("TestGetDefaultImages error: expected an error for a rewrite without replacement")
+	}
+}
+
+func TestGetOperatorConfigDefaults(t *testing.T) {
+	tlsDisabled := false
+	tlsEnabled := true
+	operatorConfig := registryv1alpha1.RegistryOperatorConfigSpec{
+		DevfileIndex:        registryv1alpha1.RegistryOperatorConfigContainer{Image: "quay.io/config/devfile-index:next", ImagePullPolicy: corev1.PullIfNotPresent},
+		OciRegistry:         registryv1alpha1.RegistryOperatorConfigContainer{Image: "quay.io/config/oci-registry:next", ImagePullPolicy: corev1.PullNever},
+		RegistryViewer:      registryv1alpha1.RegistryOperatorConfigContainer{ImagePullPolicy: corev1.PullIfNotPresent},
+		IndexBuilder:        registryv1alpha1.RegistryOperatorConfigContainer{Image: "quay.io/config/registry-index-builder:next", ImagePullPolicy: corev1.PullAlways},
+		ImagePrefixRewrites: map[string]string{"quay.io/config/": "mirror.example.com/config/"},
+		IngressClass:        "traefik",
+		TLSEnabled:          &tlsDisabled,
+	}
+
+	tests := []struct {
+		name                  string
+		cr                    registryv1alpha1.DevfileRegistry
+		wantImages            []string
+		wantImagePullPolicies []corev1.PullPolicy
+		wantIngressClass      string
+		wantTLS               bool
+	}{
+		{
+			name: "Case 1: Defaults of the RegistryOperatorConfig",
+			wantImages: []string{
+				"mirror.example.com/config/devfile-index:next",
+				"mirror.example.com/config/oci-registry:next",
+				"mirror.example.com/devfile/registry-viewer:next",
+				"mirror.example.com/config/registry-index-builder:next",
+			},
+			wantImagePullPolicies: []corev1.PullPolicy{corev1.PullIfNotPresent, corev1.PullNever, corev1.PullIfNotPresent, corev1.PullAlways},
+			wantIngressClass:      "traefik",
+			wantTLS:               false,
+		},
+		{
+			name: "Case 2: DevfileRegistry CR takes precedence over the RegistryOperatorConfig",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					DevfileIndex:   registryv1alpha1.DevfileRegistrySpecContainer{Image: "quay.io/test/devfile-index:next", ImagePullPolicy: corev1.PullAlways},
+					RegistryViewer: registryv1alpha1.DevfileRegistrySpecContainer{ImagePullPolicy: corev1.PullNever},
+					K8s:            registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressClass: "nginx"},
+					TLS:            registryv1alpha1.DevfileRegistrySpecTLS{Enabled: &tlsEnabled},
+				},
+			},
+			wantImages: []string{
+				"mirror.example.com/test/devfile-index:next",
+				"mirror.example.com/config/oci-registry:next",
+				"mirror.example.com/devfile/registry-viewer:next",
+				"mirror.example.com/config/registry-index-builder:next",
+			},
+			wantImagePullPolicies: []corev1.PullPolicy{corev1.PullAlways, corev1.PullNever, corev1.PullNever, corev1.PullAlways},
+			wantIngressClass:      "nginx",
+			wantTLS:               true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaults := config.Defaults{
+				OperatorConfig:         operatorConfig,
+				EnvImagePrefixRewrites: map[string]string{"quay.io/": "mirror.example.com/"},
+			}
+
+			gotImages := []string{GetDevfileIndexImage(&tt.cr, defaults), GetOCIRegistryImage(&tt.cr, defaults), GetRegistryViewerImage(&tt.cr, defaults), GetIndexBuilderImage(defaults)}
+			if !reflect.DeepEqual(gotImages, tt.wantImages) {
+				t.Errorf("TestGetOperatorConfigDefaults error: images mismatch, expected: %v got: %v", tt.wantImages, gotImages)
+			}
+			gotImagePullPolicies := []corev1.PullPolicy{GetDevfileIndexImagePullPolicy(&tt.cr, defaults), GetOCIRegistryImagePullPolicy(&tt.cr, defaults),
+				GetRegistryViewerImagePullPolicy(&tt.cr, defaults), GetIndexBuilderImagePullPolicy(defaults)}
+			if !reflect.DeepEqual(gotImagePullPolicies, tt.wantImagePullPolicies) {
+				t.Errorf("TestGetOperatorConfigDefaults error: image pull policies mismatch, expected: %v got: %v", tt.wantImagePullPolicies, gotImagePullPolicies)
+			}
+			if got := GetK8sIngressClass(&tt.cr, defaults); got != tt.wantIngressClass {
+				t.Errorf("TestGetOperatorConfigDefaults error: ingress class mismatch, expected: %v got: %v", tt.wantIngressClass, got)
+			}
+			if got := IsTLSEnabled(&tt.cr, defaults); got != tt.wantTLS {
+				t.Errorf("TestGetOperatorConfigDefaults error: tls enabled mismatch, expected: %v got: %v", tt.wantTLS, got)
+			}
+		})
+	}
+}
+
+func TestGetIngressDomain(t *testing.T) {
+	tests := []struct {
+		name           string
+		cr             registryv1alpha1.DevfileRegistry
+		operatorDomain string
+		clusterDomain  string
+		want           string
+	}{
+		{
+			name: "Case 1: Ingress domain set in the DevfileRegistry CR",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					K8s: registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressDomain: "cr.example.com"},
+				},
+			},
+			operatorDomain: "operator.example.com",
+			clusterDomain:  "apps.example.com",
+			want:           "cr.example.com",
+		},
+		{
+			name:           "Case 2: Ingress domain of the RegistryOperatorConfig",
+			operatorDomain: "operator.example.com",
+			clusterDomain:  "apps.example.com",
+			want:           "operator.example.com",
+		},
+		{
+			name:          "Case 3: Apps domain of the cluster",
+			clusterDomain: "apps.example.com",
+			want:          "apps.example.com",
+		},
+		{
+			name: "Case 4: No ingress domain",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaults := config.Defaults{
+				OperatorConfig:       registryv1alpha1.RegistryOperatorConfigSpec{IngressDomain: tt.operatorDomain},
+				ClusterIngressDomain: tt.clusterDomain,
+			}
+
+			if got := GetIngressDomain(&tt.cr, defaults); got != tt.want {
+				t.Errorf("TestGetIngressDomain error: ingress domain mismatch, expected: %v got: %v", tt.want, got)
+			}
+			if got := IsIngressSkipped(&tt.cr, defaults); got != (tt.want == "") {
+				t.Errorf("TestGetIngressDomain error: ingress skipped mismatch, expected: %v got: %v", tt.want == "", got)
+			}
+		})
+	}
+}
+
+func TestGetK8sIngressClassDefaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		operatorClass string
+		clusterClass  string
+		want          string
+	}{
+		{
+			name:          "Case 1: Ingress class of the RegistryOperatorConfig",
+			operatorClass: "traefik",
+			clusterClass:  "haproxy",
+			want:          "traefik",
+		},
+		{
+			name:         "Case 2: Default IngressClass of the cluster",
+			clusterClass: "haproxy",
+			want:         "haproxy",
+		},
+		{
+			name: "Case 3: No default IngressClass in the cluster",
+			want: DefaultK8sIngressClass,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaults := config.Defaults{
+				OperatorConfig:      registryv1alpha1.RegistryOperatorConfigSpec{IngressClass: tt.operatorClass},
+				ClusterIngressClass: tt.clusterClass,
+			}
+
+			cr := &registryv1alpha1.DevfileRegistry{}
+			if got := GetK8sIngressClass(cr, defaults); got != tt.want {
+				t.Errorf("TestGetK8sIngressClassDefaults error: ingress class mismatch, expected: %v got: %v", tt.want, got)
+			}
+			ingress := GenerateIngress(cr, defaults, "registry.example.com", runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+			if ingress.Spec.IngressClassName == nil || *ingress.Spec.IngressClassName != tt.want {
+				t.Errorf("TestGetK8sIngressClassDefaults error: ingress class of the ingress mismatch, expected: %v got: %v", tt.want, ingress.Spec.IngressClassName)
+			}
+		})
+	}
+}
@@ -21,17 +21,16 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
 )
 
-func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *appsv1.Deployment {
-	replicas := int32(1)
+func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, scheme *runtime.Scheme, labels map[string]string) (*appsv1.Deployment, error) {
+	replicas := GetReplicas(cr)
 	allowPrivilegeEscalation := false
 	runAsNonRoot := true
 	runAsUser := int64(1001)
@@ -50,10 +49,15 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
+					NodeSelector:              GetNodeSelector(cr),
+					Tolerations:               GetTolerations(cr),
+					Affinity:                  GetAffinity(cr),
+					TopologySpreadConstraints: GetTopologySpreadConstraints(cr),
+					PriorityClassName:         GetPriorityClassName(cr),
 					Containers: []corev1.Container{
 						{
-							Image:           GetDevfileIndexImage(cr),
-							ImagePullPolicy: GetDevfileIndexImagePullPolicy(cr),
+							Image:           GetDevfileIndexImage(cr, defaults),
+							ImagePullPolicy: GetDevfileIndexImagePullPolicy(cr, defaults),
 							Name:            "devfile-registry",
 							SecurityContext: &corev1.SecurityContext{
 								AllowPrivilegeEscalation: &allowPrivilegeEscalation,
@@ -68,40 +72,10 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 							Ports: []corev1.ContainerPort{{
 								ContainerPort: DevfileIndexPort,
 							}},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("250m"),
-									corev1.ResourceMemory: resource.MustParse("64Mi"),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("500m"),
-									corev1.ResourceMemory: resource.MustParse("256Mi"),
-								},
-							},
-							LivenessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path:   "/health",
-										Port:   intstr.FromInt(DevfileIndexPort),
-										Scheme: corev1.URISchemeHTTP,
-									},
-								},
-								InitialDelaySeconds: 15,
-								PeriodSeconds:       10,
-								TimeoutSeconds:      3,
-							},
-							ReadinessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path:   "/health",
-										Port:   intstr.FromInt(DevfileIndexPort),
-										Scheme: corev1.URISchemeHTTP,
-									},
-								},
-								InitialDelaySeconds: 15,
-								PeriodSeconds:       10,
-								TimeoutSeconds:      3,
-							},
+							Resources:      GetDevfileIndexResources(cr),
+							StartupProbe:   GetDevfileIndexStartupProbe(cr),
+							LivenessProbe:  GetDevfileIndexLivenessProbe(cr),
+							ReadinessProbe: GetDevfileIndexReadinessProbe(cr),
 							Env: []corev1.EnvVar{
 								{
 									Name:  "REGISTRY_NAME",
@@ -114,8 +88,8 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 							},
 						},
 						{
-							Image:           GetOCIRegistryImage(cr),
-							ImagePullPolicy: GetOCIRegistryImagePullPolicy(cr),
+							Image:           GetOCIRegistryImage(cr, defaults),
+							ImagePullPolicy: GetOCIRegistryImagePullPolicy(cr, defaults),
 							Name:            "oci-registry",
 							SecurityContext: &corev1.SecurityContext{
 								AllowPrivilegeEscalation: &allowPrivilegeEscalation,
@@ -127,44 +101,15 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 									Type: "RuntimeDefault",
 								},
 							},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("100m"),
-									corev1.ResourceMemory: resource.MustParse("64Mi"),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("500m"),
-									corev1.ResourceMemory: resource.MustParse("256Mi"),
-								},
-							},
-							LivenessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path:   "/v2",
-										Port:   intstr.FromInt(OCIServerPort),
-										Scheme: corev1.URISchemeHTTP,
-									},
-								},
-								InitialDelaySeconds: 30,
-								PeriodSeconds:       10,
-								TimeoutSeconds:      3,
-							},
-							ReadinessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path:   "/v2",
-										Port:   intstr.FromInt(OCIServerPort),
-										Scheme: corev1.URISchemeHTTP,
-									},
-								},
-								InitialDelaySeconds: 3,
-								PeriodSeconds:       10,
-								TimeoutSeconds:      3,
-							},
+							Resources:      GetOCIRegistryResources(cr),
+							StartupProbe:   GetOCIRegistryStartupProbe(cr),
+							LivenessProbe:  GetOCIRegistryLivenessProbe(cr),
+							ReadinessProbe: GetOCIRegistryReadinessProbe(cr),
+							Env:            GetOCIRegistryStorageEnv(cr),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      DevfileRegistryVolumeName,
-									MountPath: "/var/lib/registry",
+									MountPath: ociRegistryRootDirectory,
 								},
 								{
 									Name:      "config",
@@ -203,21 +148,9 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 
 	// Set Registry Viewer if headless is false, else run headless mode
 	if !IsHeadlessEnabled(cr) {
-		dep.Spec.Template.Spec.Containers[0].StartupProbe = &corev1.Probe{
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path:   "/viewer",
-					Port:   intstr.FromInt(RegistryViewerPort),
-					Scheme: corev1.URISchemeHTTP,
-				},
-			},
-			InitialDelaySeconds: 30,
-			PeriodSeconds:       10,
-			TimeoutSeconds:      20,
-		}
 		dep.Spec.Template.Spec.Containers = append(dep.Spec.Template.Spec.Containers, corev1.Container{
-			Image:           GetRegistryViewerImage(cr),
-			ImagePullPolicy: GetRegistryViewerImagePullPolicy(cr),
+			Image:           GetRegistryViewerImage(cr, defaults),
+			ImagePullPolicy: GetRegistryViewerImagePullPolicy(cr, defaults),
 			Name:            "registry-viewer",
 			SecurityContext: &corev1.SecurityContext{
 				AllowPrivilegeEscalation: &allowPrivilegeEscalation,
@@ -229,40 +162,10 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 					Type: "RuntimeDefault",
 				},
 			},
-			Resources: corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceCPU:    resource.MustParse("250m"),
-					corev1.ResourceMemory: resource.MustParse("64Mi"),
-				},
-				Limits: corev1.ResourceList{
-					corev1.ResourceCPU:    resource.MustParse("500m"),
-					corev1.ResourceMemory: resource.MustParse("256Mi"),
-				},
-			},
-			LivenessProbe: &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path:   "/viewer",
-						Port:   intstr.FromInt(RegistryViewerPort),
-						Scheme: corev1.URISchemeHTTP,
-					},
-				},
-				InitialDelaySeconds: 15,
-				PeriodSeconds:       10,
-				TimeoutSeconds:      20,
-			},
-			ReadinessProbe: &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path:   "/viewer",
-						Port:   intstr.FromInt(RegistryViewerPort),
-						Scheme: corev1.URISchemeHTTP,
-					},
-				},
-				InitialDelaySeconds: 15,
-				PeriodSeconds:       10,
-				TimeoutSeconds:      20,
-			},
+			Resources:      GetRegistryViewerResources(cr),
+			StartupProbe:   GetRegistryViewerStartupProbe(cr),
+			LivenessProbe:  GetRegistryViewerLivenessProbe(cr),
+			ReadinessProbe: GetRegistryViewerReadinessProbe(cr),
 			Env: []corev1.EnvVar{
 				{
 					Name:  "NEXT_PUBLIC_ANALYTICS_WRITE_KEY",
@@ -289,6 +192,34 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 		})
 	}
 
+	// Mount the htpasswd file of the OCI registry if authentication is enabled, and the Docker config file with the
+	// credential of the devfile index server, which reads the stacks from the OCI registry
+	if authVolume := GetOCIRegistryAuthVolume(cr); authVolume != nil {
+		podSpec := &dep.Spec.Template.Spec
+		podSpec.Volumes = append(podSpec.Volumes, *authVolume, *GetOCIRegistryClientAuthVolume(cr))
+		podSpec.Containers[1].VolumeMounts = append(podSpec.Containers[1].VolumeMounts, GetOCIRegistryAuthVolumeMount())
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, GetOCIRegistryClientAuthVolumeMount())
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, GetOCIRegistryClientAuthEnv())
+	}
+
+	// Build the registry index from the Git source in an init container, and serve it instead of the one of the image
+	if IsGitSourceEnabled(cr) {
+		podSpec := &dep.Spec.Template.Spec
+		podSpec.Volumes = append(podSpec.Volumes, GetSourceVolume())
+		podSpec.InitContainers = append(podSpec.InitContainers, GenerateIndexBuilderContainer(cr, defaults))
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, GetSourceIndexEnv()...)
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, GetSourceVolumeMount())
+		SetSourceCommit(&dep.Spec.Template, cr.Status.SourceCommit)
+	}
+
+	// Serve the index of the stacks mirrored by the operator instead of the one of the image
+	if IsMirrorEnabled(cr) {
+		podSpec := &dep.Spec.Template.Spec
+		podSpec.Volumes = append(podSpec.Volumes, GetMirrorVolume(cr))
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, GetMirrorIndexEnv()...)
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, GetMirrorVolumeMount())
+	}
+
 	// Enables podspec security context if storage is enabled
 	if IsStorageEnabled(cr) {
 		dep.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
@@ -299,7 +230,21 @@ func GenerateDeployment(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Sc
 		}
 	}
 
+	UpdateUserMetadata(dep, GetUserLabels(cr, labels), GetDeploymentAnnotations(cr))
+	UpdateUserMetadata(&dep.Spec.Template.ObjectMeta, GetUserLabels(cr, labels), GetPodAnnotations(cr))
+
+	// Apply the pod template override last so it takes precedence over the generated fields
+	if hash := GetPodTemplateOverrideHash(cr); hash != "" {
+		if err := ApplyPodTemplateOverride(cr, &dep.Spec.Template, labels); err != nil {
+			return nil, err
+		}
+		if dep.Annotations == nil {
+			dep.Annotations = map[string]string{}
+		}
+		dep.Annotations[PodTemplateOverrideHashAnnotation] = hash
+	}
+
 	// Set DevfileRegistry instance as the owner and controller
 	_ = ctrl.SetControllerReference(cr, dep, scheme)
-	return dep
+	return dep, nil
 }
@@ -0,0 +1,117 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+// Exposure types of a devfile registry
+const (
+	ExposureTypeRoute        = "Route"
+	ExposureTypeIngress      = "Ingress"
+	ExposureTypeGateway      = "Gateway"
+	ExposureTypeLoadBalancer = "LoadBalancer"
+	ExposureTypeNodePort     = "NodePort"
+	ExposureTypeClusterIP    = "ClusterIP"
+)
+
+// registryViewerPath is the path the registry viewer is served on by the devfile index
+const registryViewerPath = "/viewer"
+
+// GetExposureType returns how the devfile registry is exposed.
+// Default: Gateway if a gateway is set, Route on OpenShift without an ingress domain in the CR, Ingress otherwise
+func GetExposureType(cr *registryv1alpha1.DevfileRegistry) string {
+	if cr.Spec.Exposure.Type != "" {
+		return cr.Spec.Exposure.Type
+	}
+	if cr.Spec.Exposure.Gateway != nil {
+		return ExposureTypeGateway
+	}
+	if config.IsOpenShift() && cr.Spec.K8s.IngressDomain == "" {
+		return ExposureTypeRoute
+	}
+	return ExposureTypeIngress
+}
+
+// IsServiceExposure returns true if the devfile registry is only exposed with its service, without a Route,
+// an Ingress or an HTTPRoute
+func IsServiceExposure(cr *registryv1alpha1.DevfileRegistry) bool {
+	switch GetExposureType(cr) {
+	case ExposureTypeLoadBalancer, ExposureTypeNodePort, ExposureTypeClusterIP:
+		return true
+	}
+	return false
+}
+
+// GetServiceType returns the type of the devfile registry service, LoadBalancer or NodePort when the registry is
+// exposed with its service.
+// Default: ClusterIP
+func GetServiceType(cr *registryv1alpha1.DevfileRegistry) corev1.ServiceType {
+	switch GetExposureType(cr) {
+	case ExposureTypeLoadBalancer:
+		return corev1.ServiceTypeLoadBalancer
+	case ExposureTypeNodePort:
+		return corev1.ServiceTypeNodePort
+	}
+	return corev1.ServiceTypeClusterIP
+}
+
+// IsServiceExternal returns true if the registry service is reachable from outside of the cluster, that is if it is
+// of the LoadBalancer or NodePort type
+func IsServiceExternal(cr *registryv1alpha1.DevfileRegistry) bool {
+	return GetServiceType(cr) != corev1.ServiceTypeClusterIP
+}
+
+// GetServiceURL returns the URL of the given port inside the cluster, from the DNS name of the registry service, or
+// of the internal service for the ports kept off an external registry service
+func GetServiceURL(cr *registryv1alpha1.DevfileRegistry, port int) string {
+	serviceName := ServiceName(cr)
+	if IsServiceExternal(cr) && port != DevfileIndexPort && port != RegistryViewerPort {
+		serviceName = InternalServiceName(cr)
+	}
+	return fmt.Sprintf("http://%s.%s.svc:%d", serviceName, cr.Namespace, port)
+}
+
+// GetLoadBalancerURL returns the URL of the given port from the address assigned to the LoadBalancer service of the
+// registry, or "" if none is assigned yet
+func GetLoadBalancerURL(svc *corev1.Service, port int) string {
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		host := ingress.Hostname
+		if host == "" {
+			host = ingress.IP
+		}
+		if host != "" {
+			return fmt.Sprintf("http://%s:%d", host, port)
+		}
+	}
+	return ""
+}
+
+// GetRegistryViewerURL returns the URL of the registry viewer served by the devfile index at the given URL, or "" in
+// headless mode
+func GetRegistryViewerURL(cr *registryv1alpha1.DevfileRegistry, indexURL string) string {
+	if IsHeadlessEnabled(cr) || indexURL == "" {
+		return ""
+	}
+	return indexURL + registryViewerPath
+}
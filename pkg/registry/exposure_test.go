@@ -0,0 +1,300 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetExposureType(t *testing.T) {
+	defer config.SetIsOpenShift(false)
+
+	tests := []struct {
+		name        string
+		cr          registryv1alpha1.DevfileRegistry
+		isOpenShift bool
+		want        string
+		wantService corev1.ServiceType
+	}{
+		{
+			name:        "Case 1: Ingress by default on Kubernetes",
+			want:        ExposureTypeIngress,
+			wantService: corev1.ServiceTypeClusterIP,
+		},
+		{
+			name:        "Case 2: Route by default on OpenShift",
+			isOpenShift: true,
+			want:        ExposureTypeRoute,
+			wantService: corev1.ServiceTypeClusterIP,
+		},
+		{
+			name: "Case 3: Ingress on OpenShift with an ingress domain",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					K8s: registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressDomain: "my-domain"},
+				},
+			},
+			isOpenShift: true,
+			want:        ExposureTypeIngress,
+			wantService: corev1.ServiceTypeClusterIP,
+		},
+		{
+			name: "Case 4: Gateway by default with a gateway",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+						Gateway: &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway"},
+					},
+				},
+			},
+			want:        ExposureTypeGateway,
+			wantService: corev1.ServiceTypeClusterIP,
+		},
+		{
+			name: "Case 5: LoadBalancer",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: ExposureTypeLoadBalancer},
+				},
+			},
+			isOpenShift: true,
+			want:        ExposureTypeLoadBalancer,
+			wantService: corev1.ServiceTypeLoadBalancer,
+		},
+		{
+			name: "Case 6: NodePort",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: ExposureTypeNodePort},
+				},
+			},
+			want:        ExposureTypeNodePort,
+			wantService: corev1.ServiceTypeNodePort,
+		},
+		{
+			name: "Case 7: ClusterIP",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: ExposureTypeClusterIP},
+				},
+			},
+			want:        ExposureTypeClusterIP,
+			wantService: corev1.ServiceTypeClusterIP,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetIsOpenShift(tt.isOpenShift)
+			if got := GetExposureType(&tt.cr); got != tt.want {
+				t.Errorf("TestGetExposureType error: exposure type mismatch, expected: %v got: %v", tt.want, got)
+			}
+			if got := GetServiceType(&tt.cr); got != tt.wantService {
+				t.Errorf("TestGetExposureType error: service type mismatch, expected: %v got: %v", tt.wantService, got)
+			}
+		})
+	}
+}
+
+func TestGetServiceURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		exposureType string
+		port         int
+		want         string
+	}{
+		{
+			name: "Case 1: Devfile index port",
+			port: DevfileIndexPort,
+			want: "http://test-name-devfile-registry.test-namespace.svc:8080",
+		},
+		{
+			name: "Case 2: OCI registry port on the registry service",
+			port: OCIServerPort,
+			want: "http://test-name-devfile-registry.test-namespace.svc:5000",
+		},
+		{
+			name:         "Case 3: OCI registry port on the internal service with a LoadBalancer",
+			exposureType: ExposureTypeLoadBalancer,
+			port:         OCIServerPort,
+			want:         "http://test-name-devfile-registry-internal.test-namespace.svc:5000",
+		},
+		{
+			name:         "Case 4: Devfile index port on the registry service with a NodePort",
+			exposureType: ExposureTypeNodePort,
+			port:         DevfileIndexPort,
+			want:         "http://test-name-devfile-registry.test-namespace.svc:8080",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: tt.exposureType},
+				},
+			}
+			if got := GetServiceURL(cr, tt.port); got != tt.want {
+				t.Errorf("TestGetServiceURL error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetLoadBalancerURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ingress []corev1.LoadBalancerIngress
+		want    string
+	}{
+		{
+			name: "Case 1: No address assigned",
+			want: "",
+		},
+		{
+			name:    "Case 2: IP address",
+			ingress: []corev1.LoadBalancerIngress{{IP: "192.0.2.10"}},
+			want:    "http://192.0.2.10:8080",
+		},
+		{
+			name:    "Case 3: Hostname preferred over the IP address",
+			ingress: []corev1.LoadBalancerIngress{{IP: "192.0.2.10", Hostname: "lb.example.com"}},
+			want:    "http://lb.example.com:8080",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: tt.ingress}}}
+			if got := GetLoadBalancerURL(svc, DevfileIndexPort); got != tt.want {
+				t.Errorf("TestGetLoadBalancerURL error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetServicePorts(t *testing.T) {
+	headless := true
+	tests := []struct {
+		name         string
+		headless     *bool
+		exposureType string
+		want         []int32
+	}{
+		{
+			name: "Case 1: Registry viewer port",
+			want: []int32{DevfileIndexPort, DevfileIndexMetricsPort, OCIMetricsPort, OCIServerPort, RegistryViewerPort},
+		},
+		{
+			name:     "Case 2: No registry viewer port in headless mode",
+			headless: &headless,
+			want:     []int32{DevfileIndexPort, DevfileIndexMetricsPort, OCIMetricsPort, OCIServerPort},
+		},
+		{
+			name:         "Case 3: No OCI registry and metrics ports with a LoadBalancer",
+			exposureType: ExposureTypeLoadBalancer,
+			want:         []int32{DevfileIndexPort, RegistryViewerPort},
+		},
+		{
+			name:         "Case 4: Only the devfile index port with a headless NodePort",
+			headless:     &headless,
+			exposureType: ExposureTypeNodePort,
+			want:         []int32{DevfileIndexPort},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Headless: tt.headless,
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: tt.exposureType},
+				},
+			}
+			ports := GetServicePorts(cr)
+			if len(ports) != len(tt.want) {
+				t.Fatalf("TestGetServicePorts error: expected %d ports, got: %v", len(tt.want), ports)
+			}
+			for i := range ports {
+				if ports[i].Port != tt.want[i] {
+					t.Errorf("TestGetServicePorts error: port %d expected: %v got: %v", i, tt.want[i], ports[i].Port)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateInternalService(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			Exposure: registryv1alpha1.DevfileRegistrySpecExposure{Type: ExposureTypeLoadBalancer},
+		},
+	}
+	labels := LabelsForDevfileRegistry(cr)
+	svc := GenerateInternalService(cr, runtime.NewScheme(), labels)
+	if svc.Name != InternalServiceName(cr) {
+		t.Errorf("TestGenerateInternalService error: name expected: %v got: %v", InternalServiceName(cr), svc.Name)
+	}
+	if svc.Spec.Type != corev1.ServiceTypeClusterIP {
+		t.Errorf("TestGenerateInternalService error: type expected: %v got: %v", corev1.ServiceTypeClusterIP, svc.Spec.Type)
+	}
+	if !reflect.DeepEqual(svc.Spec.Selector, labels) {
+		t.Errorf("TestGenerateInternalService error: selector expected: %v got: %v", labels, svc.Spec.Selector)
+	}
+	if !reflect.DeepEqual(svc.Spec.Ports, GetInternalServicePorts()) {
+		t.Errorf("TestGenerateInternalService error: ports expected: %v got: %v", GetInternalServicePorts(), svc.Spec.Ports)
+	}
+}
+
+func TestGetRegistryViewerURL(t *testing.T) {
+	headless := true
+	tests := []struct {
+		name     string
+		headless *bool
+		indexURL string
+		want     string
+	}{
+		{
+			name:     "Case 1: Viewer served by the devfile index",
+			indexURL: "https://registry.example.com",
+			want:     "https://registry.example.com/viewer",
+		},
+		{
+			name:     "Case 2: No viewer in headless mode",
+			headless: &headless,
+			indexURL: "https://registry.example.com",
+			want:     "",
+		},
+		{
+			name: "Case 3: Devfile index URL not known yet",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{Spec: registryv1alpha1.DevfileRegistrySpec{Headless: tt.headless}}
+			if got := GetRegistryViewerURL(cr, tt.indexURL); got != tt.want {
+				t.Errorf("TestGetRegistryViewerURL error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
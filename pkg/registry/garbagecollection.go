@@ -0,0 +1,233 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+const (
+	// Results of the garbage collection of the OCI registry storage reported in the DevfileRegistry status
+	GarbageCollectionRunning   = "Running"
+	GarbageCollectionSucceeded = "Succeeded"
+	GarbageCollectionFailed    = "Failed"
+
+	// Label set on the garbage collection pods instead of the registry selector labels, so that the registry
+	// service and disruption budget never select them
+	garbageCollectionJobLabel = "devfileregistry_job"
+	garbageCollectionJobName  = "garbage-collection"
+
+	ociRegistryConfigFile = "/etc/docker/registry/config.yml"
+)
+
+// IsGarbageCollectionScheduled returns true if a garbage collection schedule is set in the DevfileRegistry CR
+func IsGarbageCollectionScheduled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return cr.Spec.OciRegistry.GarbageCollection != nil
+}
+
+// IsGarbageCollectionEnabled returns true if the garbage collection of the OCI registry storage is scheduled and
+// the storage outlives the registry pods. Without persistent storage or a bucket, each registry pod has its own
+// empty volume that no other pod can reach.
+func IsGarbageCollectionEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return IsGarbageCollectionScheduled(cr) && (IsStorageEnabled(cr) || IsOCIRegistryS3StorageEnabled(cr))
+}
+
+// isGarbageCollectionDeleteUntagged returns true if the garbage collection also removes the untagged manifests
+// Default: false
+func isGarbageCollectionDeleteUntagged(cr *registryv1alpha1.DevfileRegistry) bool {
+	if gc := cr.Spec.OciRegistry.GarbageCollection; gc != nil && gc.DeleteUntagged != nil {
+		return *gc.DeleteUntagged
+	}
+	return false
+}
+
+// GetGarbageCollectionSchedule returns the cron schedule of the garbage collection of the OCI registry storage
+func GetGarbageCollectionSchedule(cr *registryv1alpha1.DevfileRegistry) string {
+	if gc := cr.Spec.OciRegistry.GarbageCollection; gc != nil {
+		return gc.Schedule
+	}
+	return ""
+}
+
+// GetGarbageCollectionArgs returns the arguments of the registry command collecting the garbage of the OCI registry
+// storage, with the configuration mounted from the registry config map
+func GetGarbageCollectionArgs(cr *registryv1alpha1.DevfileRegistry) []string {
+	args := []string{"garbage-collect"}
+	if isGarbageCollectionDeleteUntagged(cr) {
+		args = append(args, "--delete-untagged")
+	}
+	return append(args, ociRegistryConfigFile)
+}
+
+// GetGarbageCollectionPodLabels returns the labels of the garbage collection pods
+func GetGarbageCollectionPodLabels(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return map[string]string{"devfileregistry_cr": cr.Name, garbageCollectionJobLabel: garbageCollectionJobName}
+}
+
+// GetGarbageCollectionResult returns the result of the last garbage collection run by the given CronJob, or
+// an empty string if it never ran
+func GetGarbageCollectionResult(cronJob *batchv1.CronJob) string {
+	switch {
+	case len(cronJob.Status.Active) > 0:
+		return GarbageCollectionRunning
+	case cronJob.Status.LastScheduleTime == nil:
+		return ""
+	case cronJob.Status.LastSuccessfulTime != nil && !cronJob.Status.LastSuccessfulTime.Before(cronJob.Status.LastScheduleTime):
+		return GarbageCollectionSucceeded
+	}
+	return GarbageCollectionFailed
+}
+
+// GenerateGarbageCollectionCronJob returns a CronJob collecting the garbage of the OCI registry storage on the
+// schedule set in the DevfileRegistry CR. The jobs run the OCI registry image against the registry volume and
+// config map.
+func GenerateGarbageCollectionCronJob(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, scheme *runtime.Scheme, labels map[string]string) *batchv1.CronJob {
+	backoffLimit := int32(0)
+	allowPrivilegeEscalation := false
+	runAsNonRoot := true
+	runAsUser := int64(1001)
+	runAsGroup := int64(2001)
+	fsGroup := int64(3001)
+	podLabels := GetGarbageCollectionPodLabels(cr)
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: generateObjectMeta(GarbageCollectionCronJobName(cr), cr.Namespace, labels),
+		Spec: batchv1.CronJobSpec{
+			Schedule:          GetGarbageCollectionSchedule(cr),
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: podLabels,
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy:     corev1.RestartPolicyNever,
+							NodeSelector:      GetNodeSelector(cr),
+							Tolerations:       GetTolerations(cr),
+							Affinity:          getGarbageCollectionAffinity(cr, labels),
+							PriorityClassName: GetPriorityClassName(cr),
+							Containers: []corev1.Container{
+								{
+									Image:           GetOCIRegistryImage(cr, defaults),
+									ImagePullPolicy: GetOCIRegistryImagePullPolicy(cr, defaults),
+									Name:            "garbage-collection",
+									Command:         []string{"registry"},
+									Args:            GetGarbageCollectionArgs(cr),
+									SecurityContext: &corev1.SecurityContext{
+										AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+										RunAsNonRoot:             &runAsNonRoot,
+										Capabilities: &corev1.Capabilities{
+											Drop: []corev1.Capability{"ALL"},
+										},
+										SeccompProfile: &corev1.SeccompProfile{
+											Type: "RuntimeDefault",
+										},
+									},
+									Resources: GetOCIRegistryResources(cr),
+									Env:       GetOCIRegistryStorageEnv(cr),
+									VolumeMounts: []corev1.VolumeMount{
+										{
+											Name:      DevfileRegistryVolumeName,
+											MountPath: ociRegistryRootDirectory,
+										},
+										{
+											Name:      "config",
+											MountPath: "/etc/docker/registry",
+											ReadOnly:  true,
+										},
+									},
+								},
+							},
+							Volumes: []corev1.Volume{
+								{
+									Name:         DevfileRegistryVolumeName,
+									VolumeSource: GetDevfileRegistryVolumeSource(cr),
+								},
+								{
+									Name: "config",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: ConfigMapName(cr),
+											},
+											Items: []corev1.KeyToPath{
+												{
+													Key:  "registry-config.yml",
+													Path: "config.yml",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Same security context as the registry pods, so that the jobs can write to the registry volume
+	if IsStorageEnabled(cr) {
+		cronJob.Spec.JobTemplate.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
+			RunAsNonRoot: &runAsNonRoot,
+			RunAsUser:    &runAsUser,
+			RunAsGroup:   &runAsGroup,
+			FSGroup:      &fsGroup,
+		}
+	}
+
+	UpdateUserMetadata(cronJob, GetUserLabels(cr, labels), GetCommonAnnotations(cr))
+	UpdateUserMetadata(&cronJob.Spec.JobTemplate.Spec.Template.ObjectMeta, GetUserLabels(cr, podLabels), GetPodAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, cronJob, scheme)
+	return cronJob
+}
+
+// getGarbageCollectionAffinity returns the affinity of the garbage collection pods, the one set in the DevfileRegistry
+// CR without the spreading of the registry pods. A persistent volume that cannot be shared between nodes is only
+// mounted by pods on the node of the registry pod.
+func getGarbageCollectionAffinity(cr *registryv1alpha1.DevfileRegistry, selectorLabels map[string]string) *corev1.Affinity {
+	affinity := cr.Spec.Scheduling.Affinity.DeepCopy()
+	if !IsStorageEnabled(cr) || isStorageShareable(cr) {
+		return affinity
+	}
+
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.PodAffinity == nil {
+		affinity.PodAffinity = &corev1.PodAffinity{}
+	}
+	affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution, corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: selectorLabels,
+		},
+		TopologyKey: corev1.LabelHostname,
+	})
+	return affinity
+}
@@ -0,0 +1,180 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+func TestGenerateGarbageCollectionCronJob(t *testing.T) {
+	storageEnabled := true
+	deleteUntagged := true
+	garbageCollection := &registryv1alpha1.DevfileRegistrySpecGarbageCollection{Schedule: "0 3 * * *"}
+
+	tests := []struct {
+		name         string
+		cr           registryv1alpha1.DevfileRegistry
+		wantArgs     []string
+		wantAffinity bool
+	}{
+		{
+			name: "Case 1: Persistent storage that cannot be shared between nodes",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Storage:     registryv1alpha1.DevfileRegistrySpecStorage{Enabled: &storageEnabled},
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{GarbageCollection: garbageCollection},
+				},
+			},
+			wantArgs:     []string{"garbage-collect", "/etc/docker/registry/config.yml"},
+			wantAffinity: true,
+		},
+		{
+			name: "Case 2: Persistent storage shared between nodes, deleting untagged manifests",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Storage: registryv1alpha1.DevfileRegistrySpecStorage{
+						Enabled:     &storageEnabled,
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+					},
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+						GarbageCollection: &registryv1alpha1.DevfileRegistrySpecGarbageCollection{
+							Schedule:       "0 3 * * *",
+							DeleteUntagged: &deleteUntagged,
+						},
+					},
+				},
+			},
+			wantArgs:     []string{"garbage-collect", "--delete-untagged", "/etc/docker/registry/config.yml"},
+			wantAffinity: false,
+		},
+		{
+			name: "Case 3: S3 storage",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+						Storage: registryv1alpha1.DevfileRegistrySpecOCIStorage{
+							S3: &registryv1alpha1.DevfileRegistrySpecS3Storage{Bucket: "devfile-registry"},
+						},
+						GarbageCollection: garbageCollection,
+					},
+				},
+			},
+			wantArgs:     []string{"garbage-collect", "/etc/docker/registry/config.yml"},
+			wantAffinity: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selectorLabels := LabelsForDevfileRegistry(&tt.cr)
+			cronJob := GenerateGarbageCollectionCronJob(&tt.cr, config.Defaults{}, runtime.NewScheme(), selectorLabels)
+
+			if cronJob.Spec.Schedule != "0 3 * * *" {
+				t.Errorf("TestGenerateGarbageCollectionCronJob error: schedule mismatch, got: %s", cronJob.Spec.Schedule)
+			}
+			if cronJob.Spec.ConcurrencyPolicy != batchv1.ForbidConcurrent {
+				t.Errorf("TestGenerateGarbageCollectionCronJob error: concurrent garbage collections are allowed")
+			}
+
+			podTemplate := cronJob.Spec.JobTemplate.Spec.Template
+			if args := podTemplate.Spec.Containers[0].Args; !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("TestGenerateGarbageCollectionCronJob error: args mismatch, expected: %v got: %v", tt.wantArgs, args)
+			}
+
+			// The registry service and disruption budget must never select the garbage collection pods
+			if labels.SelectorFromSet(selectorLabels).Matches(labels.Set(podTemplate.Labels)) {
+				t.Errorf("TestGenerateGarbageCollectionCronJob error: pod labels %v match the registry selector", podTemplate.Labels)
+			}
+
+			hasAffinity := podTemplate.Spec.Affinity != nil && podTemplate.Spec.Affinity.PodAffinity != nil
+			if hasAffinity != tt.wantAffinity {
+				t.Errorf("TestGenerateGarbageCollectionCronJob error: pod affinity to the registry pods expected: %t got: %t", tt.wantAffinity, hasAffinity)
+			}
+		})
+	}
+}
+
+func TestGetGarbageCollectionResult(t *testing.T) {
+	scheduled := metav1.NewTime(time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC))
+	previous := metav1.NewTime(scheduled.Add(-24 * time.Hour))
+	succeeded := metav1.NewTime(scheduled.Add(time.Minute))
+
+	tests := []struct {
+		name   string
+		status batchv1.CronJobStatus
+		want   string
+	}{
+		{
+			name:   "Case 1: Never scheduled",
+			status: batchv1.CronJobStatus{},
+			want:   "",
+		},
+		{
+			name: "Case 2: Running",
+			status: batchv1.CronJobStatus{
+				Active:             []corev1.ObjectReference{{Name: "devfile-registry-gc-28400000"}},
+				LastScheduleTime:   &scheduled,
+				LastSuccessfulTime: &previous,
+			},
+			want: GarbageCollectionRunning,
+		},
+		{
+			name: "Case 3: Succeeded",
+			status: batchv1.CronJobStatus{
+				LastScheduleTime:   &scheduled,
+				LastSuccessfulTime: &succeeded,
+			},
+			want: GarbageCollectionSucceeded,
+		},
+		{
+			name: "Case 4: Failed after a previous success",
+			status: batchv1.CronJobStatus{
+				LastScheduleTime:   &scheduled,
+				LastSuccessfulTime: &previous,
+			},
+			want: GarbageCollectionFailed,
+		},
+		{
+			name: "Case 5: Failed without any success",
+			status: batchv1.CronJobStatus{
+				LastScheduleTime: &scheduled,
+			},
+			want: GarbageCollectionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetGarbageCollectionResult(&batchv1.CronJob{Status: tt.status})
+			if got != tt.want {
+				t.Errorf("TestGetGarbageCollectionResult error: result mismatch, expected: %s got: %s", tt.want, got)
+			}
+		})
+	}
+}
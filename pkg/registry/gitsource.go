@@ -0,0 +1,209 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+const (
+	// Keys of the secret holding the credentials of the Git source
+	GitCredentialsUsernameKey = "username"
+	GitCredentialsPasswordKey = "password"
+
+	// SourceCommitAnnotation is set on the registry pods to the commit of the Git source their index was built
+	// from, so that the pods are rolled out and the index rebuilt when a new commit is pushed
+	SourceCommitAnnotation = "registry.devfile.io/source-commit"
+
+	// Interval at which the tracked revision of the Git source is checked for new commits
+	DefaultGitSourcePollInterval = 5 * time.Minute
+
+	// Default resources of the container building the registry index
+	DefaultIndexBuilderMemoryLimit   = "512Mi"
+	DefaultIndexBuilderCPULimit      = "500m"
+	DefaultIndexBuilderMemoryRequest = "64Mi"
+	DefaultIndexBuilderCPURequest    = "100m"
+
+	IndexBuilderContainerName = "build-index"
+	SourceVolumeName          = "registry-source"
+	sourceMountPath           = "/registry-source"
+
+	// indexBuilderScript fetches the revision of the Git source, copies its registry directory to the shared
+	// volume and generates the registry index in it with the index generator of the index builder image, built by
+	// index-builder.Dockerfile. The devfile index server pushes the stacks of the index to the OCI registry when it
+	// starts.
+	indexBuilderScript = `set -e
+if [ -n "${GIT_PASSWORD}" ]; then
+  git config --global credential.helper '!f() { echo "username=${GIT_USERNAME:-git}"; echo "password=${GIT_PASSWORD}"; }; f'
+fi
+git init -q /tmp/source
+cd /tmp/source
+git fetch -q --depth 1 "${GIT_URL}" "${GIT_REVISION}"
+git -c advice.detachedHead=false checkout -q FETCH_HEAD
+echo "Building the registry index from ${GIT_URL} at $(git rev-parse HEAD)"
+cp -R "/tmp/source/${GIT_PATH}/." ` + sourceMountPath + `/
+index-generator ` + sourceMountPath + ` ` + sourceMountPath + `/index_main.json
+`
+)
+
+// IsGitSourceEnabled returns true if the registry index is built from a Git repository
+func IsGitSourceEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return cr.Spec.Source.Git != nil
+}
+
+// GetGitSourceRevision returns the revision of the Git source the registry index is built from
+// Default: "HEAD"
+func GetGitSourceRevision(cr *registryv1alpha1.DevfileRegistry) string {
+	if cr.Spec.Source.Git.Revision != "" {
+		return cr.Spec.Source.Git.Revision
+	}
+	return "HEAD"
+}
+
+// GetGitSourceCommit returns the revision of the Git source fetched to build the registry index: the commit the
+// tracked revision was resolved to, so that the index is built from the commit reported in the status.
+// Default: the tracked revision until it is resolved
+func GetGitSourceCommit(cr *registryv1alpha1.DevfileRegistry) string {
+	if cr.Status.SourceCommit != "" {
+		return cr.Status.SourceCommit
+	}
+	return GetGitSourceRevision(cr)
+}
+
+// GetIndexBuilderResources returns the resources of the container building the registry index
+// Default: requests {cpu: "100m", memory: "64Mi"}, limits {cpu: "500m", memory: "512Mi"}
+func GetIndexBuilderResources() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultIndexBuilderCPURequest),
+			corev1.ResourceMemory: resource.MustParse(DefaultIndexBuilderMemoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultIndexBuilderCPULimit),
+			corev1.ResourceMemory: resource.MustParse(DefaultIndexBuilderMemoryLimit),
+		},
+	}
+}
+
+// GenerateIndexBuilderContainer returns the init container building the registry index from the Git source
+func GenerateIndexBuilderContainer(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) corev1.Container {
+	allowPrivilegeEscalation := false
+	runAsNonRoot := true
+	git := cr.Spec.Source.Git
+
+	env := []corev1.EnvVar{
+		{Name: "GIT_URL", Value: git.URL},
+		{Name: "GIT_REVISION", Value: GetGitSourceCommit(cr)},
+		{Name: "GIT_PATH", Value: path.Clean("/" + git.Path)},
+		// The build runs under an arbitrary user, keep the Git configuration in a writable directory
+		{Name: "HOME", Value: "/tmp"},
+	}
+	if git.CredentialsSecret != "" {
+		optional := true
+		env = append(env,
+			corev1.EnvVar{Name: "GIT_USERNAME", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: git.CredentialsSecret},
+					Key:                  GitCredentialsUsernameKey,
+					Optional:             &optional,
+				},
+			}},
+			corev1.EnvVar{Name: "GIT_PASSWORD", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: git.CredentialsSecret},
+					Key:                  GitCredentialsPasswordKey,
+				},
+			}},
+		)
+	}
+
+	return corev1.Container{
+		Image:           GetIndexBuilderImage(defaults),
+		ImagePullPolicy: GetIndexBuilderImagePullPolicy(defaults),
+		Name:            IndexBuilderContainerName,
+		Command:         []string{"/bin/sh", "-c", indexBuilderScript},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			RunAsNonRoot:             &runAsNonRoot,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: "RuntimeDefault",
+			},
+		},
+		Resources:    GetIndexBuilderResources(),
+		Env:          env,
+		VolumeMounts: []corev1.VolumeMount{GetSourceVolumeMount()},
+	}
+}
+
+// GetSourceVolume returns the volume the registry index is built in and served from
+func GetSourceVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: SourceVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+// GetSourceVolumeMount returns the mount of the volume the registry index is built in and served from
+func GetSourceVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      SourceVolumeName,
+		MountPath: sourceMountPath,
+	}
+}
+
+// GetSourceIndexEnv returns the environment variables pointing the devfile index server to the registry index
+// built from the Git source, instead of the one of the devfile index image
+func GetSourceIndexEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "DEVFILE_INDEX", Value: sourceMountPath + "/index_main.json"},
+		{Name: "DEVFILE_STACKS", Value: sourceMountPath + "/stacks"},
+		{Name: "DEVFILE_SAMPLES", Value: sourceMountPath + "/samples"},
+	}
+}
+
+// SetSourceCommit sets the commit of the Git source the registry index is built from on the pod template,
+// removing it if empty. Returns true if it changed.
+func SetSourceCommit(template *corev1.PodTemplateSpec, commit string) bool {
+	current, found := template.Annotations[SourceCommitAnnotation]
+	if commit == "" {
+		if !found {
+			return false
+		}
+		delete(template.Annotations, SourceCommitAnnotation)
+		return true
+	}
+	if current == commit {
+		return false
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[SourceCommitAnnotation] = commit
+	return true
+}
@@ -0,0 +1,165 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGenerateDeploymentGitSource(t *testing.T) {
+	commit := "1111111111111111111111111111111111111111"
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-test", Namespace: "test"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			Source: registryv1alpha1.DevfileRegistrySpecSource{
+				Git: &registryv1alpha1.DevfileRegistrySpecGitSource{
+					URL:               "https://github.com/devfile/registry.git",
+					Path:              "../registry/",
+					CredentialsSecret: "git-credentials",
+				},
+			},
+		},
+		Status: registryv1alpha1.DevfileRegistryStatus{SourceCommit: commit},
+	}
+
+	dep, err := GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+	if err != nil {
+		t.Fatalf("TestGenerateDeploymentGitSource error: unexpected error generating the deployment: %v", err)
+	}
+	podSpec := dep.Spec.Template.Spec
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != IndexBuilderContainerName {
+		t.Fatalf("TestGenerateDeploymentGitSource error: index builder init container not set")
+	}
+	env := map[string]corev1.EnvVar{}
+	for _, envVar := range podSpec.InitContainers[0].Env {
+		env[envVar.Name] = envVar
+	}
+	if env["GIT_REVISION"].Value != commit {
+		t.Errorf("TestGenerateDeploymentGitSource error: revision mismatch, expected the resolved commit %s got: %s", commit, env["GIT_REVISION"].Value)
+	}
+	if env["GIT_PATH"].Value != "/registry" {
+		t.Errorf("TestGenerateDeploymentGitSource error: path not confined to the repository, got: %s", env["GIT_PATH"].Value)
+	}
+	if password := env["GIT_PASSWORD"].ValueFrom; password == nil || password.SecretKeyRef.Name != "git-credentials" {
+		t.Errorf("TestGenerateDeploymentGitSource error: credentials not read from the secret")
+	}
+
+	indexContainer := podSpec.Containers[0]
+	mounted := false
+	for _, mount := range indexContainer.VolumeMounts {
+		mounted = mounted || mount.Name == SourceVolumeName
+	}
+	if !mounted {
+		t.Errorf("TestGenerateDeploymentGitSource error: source volume not mounted in the devfile index container")
+	}
+	for _, want := range GetSourceIndexEnv() {
+		found := false
+		for _, envVar := range indexContainer.Env {
+			found = found || envVar == want
+		}
+		if !found {
+			t.Errorf("TestGenerateDeploymentGitSource error: environment variable %s not set", want.Name)
+		}
+	}
+	if got := dep.Spec.Template.Annotations[SourceCommitAnnotation]; got != commit {
+		t.Errorf("TestGenerateDeploymentGitSource error: commit annotation mismatch, expected: %s got: %s", commit, got)
+	}
+
+	cr.Spec.Source.Git = nil
+	dep, err = GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+	if err != nil {
+		t.Fatalf("TestGenerateDeploymentGitSource error: unexpected error generating the deployment: %v", err)
+	}
+	if len(dep.Spec.Template.Spec.InitContainers) != 0 {
+		t.Errorf("TestGenerateDeploymentGitSource error: unexpected init containers without a Git source")
+	}
+	if _, found := dep.Spec.Template.Annotations[SourceCommitAnnotation]; found {
+		t.Errorf("TestGenerateDeploymentGitSource error: unexpected commit annotation without a Git source")
+	}
+}
+
+func TestGetGitSourceCommit(t *testing.T) {
+	tests := []struct {
+		name     string
+		revision string
+		commit   string
+		want     string
+	}{
+		{
+			name: "Case 1: Default branch until resolved",
+			want: "HEAD",
+		},
+		{
+			name:     "Case 2: Tracked revision until resolved",
+			revision: "v1.0.0",
+			want:     "v1.0.0",
+		},
+		{
+			name:     "Case 3: Resolved commit",
+			revision: "main",
+			commit:   "1111111111111111111111111111111111111111",
+			want:     "1111111111111111111111111111111111111111",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Source: registryv1alpha1.DevfileRegistrySpecSource{
+						Git: &registryv1alpha1.DevfileRegistrySpecGitSource{URL: "https://github.com/devfile/registry.git", Revision: tt.revision},
+					},
+				},
+				Status: registryv1alpha1.DevfileRegistryStatus{SourceCommit: tt.commit},
+			}
+			if got := GetGitSourceCommit(cr); got != tt.want {
+				t.Errorf("TestGetGitSourceCommit error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetSourceCommit(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		commit      string
+		want        bool
+	}{
+		{name: "Case 1: No commit", commit: "", want: false},
+		{name: "Case 2: New commit", commit: "abc", want: true},
+		{name: "Case 3: Same commit", annotations: map[string]string{SourceCommitAnnotation: "abc"}, commit: "abc", want: false},
+		{name: "Case 4: Changed commit", annotations: map[string]string{SourceCommitAnnotation: "abc"}, commit: "def", want: true},
+		{name: "Case 5: Removed commit", annotations: map[string]string{SourceCommitAnnotation: "abc"}, commit: "", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := SetSourceCommit(template, tt.commit); got != tt.want {
+				t.Errorf("TestSetSourceCommit error: changed mismatch, expected: %t got: %t", tt.want, got)
+			}
+			if got := template.Annotations[SourceCommitAnnotation]; got != tt.commit {
+				t.Errorf("TestSetSourceCommit error: annotation mismatch, expected: %q got: %q", tt.commit, got)
+			}
+		})
+	}
+}
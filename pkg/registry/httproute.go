@@ -0,0 +1,120 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+// IsGatewayEnabled returns true if the registry is exposed with an HTTPRoute attached to a Gateway
+func IsGatewayEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return GetExposureType(cr) == ExposureTypeGateway
+}
+
+// GetGatewayHostname returns the hostname of the HTTPRoute exposing the registry.
+// Default: the hostname of the registry in its ingress domain, or "" if no ingress domain is known
+func GetGatewayHostname(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) string {
+	if cr.Spec.Exposure.Gateway != nil && cr.Spec.Exposure.Gateway.Hostname != "" {
+		return cr.Spec.Exposure.Gateway.Hostname
+	}
+	if GetIngressDomain(cr, defaults) == "" {
+		return ""
+	}
+	return GetDevfileRegistryIngress(cr, defaults)
+}
+
+// GenerateHTTPRoute returns an HTTPRoute exposing the devfile registry index through the Gateway set in the
+// DevfileRegistry CR. The fields defaulted by the Gateway API are set, so that the generated spec can be compared
+// with the one of an existing HTTPRoute.
+func GenerateHTTPRoute(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, scheme *runtime.Scheme, labels map[string]string) *gatewayv1.HTTPRoute {
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: generateObjectMeta(IngressName(cr), cr.Namespace, labels),
+		Spec:       GetHTTPRouteSpec(cr, defaults),
+	}
+
+	UpdateUserMetadata(httpRoute, GetUserLabels(cr, labels), GetCommonAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, httpRoute, scheme)
+	return httpRoute
+}
+
+// GetHTTPRouteSpec returns the spec of the HTTPRoute exposing the devfile registry index
+func GetHTTPRouteSpec(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) gatewayv1.HTTPRouteSpec {
+	gateway := cr.Spec.Exposure.Gateway
+	if gateway == nil {
+		return gatewayv1.HTTPRouteSpec{}
+	}
+
+	group := gatewayv1.Group(gatewayv1.GroupName)
+	kind := gatewayv1.Kind("Gateway")
+	parentRef := gatewayv1.ParentReference{
+		Group: &group,
+		Kind:  &kind,
+		Name:  gatewayv1.ObjectName(gateway.Name),
+	}
+	if gateway.Namespace != "" {
+		namespace := gatewayv1.Namespace(gateway.Namespace)
+		parentRef.Namespace = &namespace
+	}
+	if gateway.SectionName != "" {
+		sectionName := gatewayv1.SectionName(gateway.SectionName)
+		parentRef.SectionName = &sectionName
+	}
+
+	pathType := gatewayv1.PathMatchPathPrefix
+	path := "/"
+	serviceGroup := gatewayv1.Group("")
+	serviceKind := gatewayv1.Kind("Service")
+	port := gatewayv1.PortNumber(DevfileIndexPort)
+	weight := int32(1)
+
+	spec := gatewayv1.HTTPRouteSpec{
+		CommonRouteSpec: gatewayv1.CommonRouteSpec{
+			ParentRefs: []gatewayv1.ParentReference{parentRef},
+		},
+		Rules: []gatewayv1.HTTPRouteRule{
+			{
+				Matches: []gatewayv1.HTTPRouteMatch{
+					{Path: &gatewayv1.HTTPPathMatch{Type: &pathType, Value: &path}},
+				},
+				BackendRefs: []gatewayv1.HTTPBackendRef{
+					{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Group: &serviceGroup,
+								Kind:  &serviceKind,
+								Name:  gatewayv1.ObjectName(ServiceName(cr)),
+								Port:  &port,
+							},
+							Weight: &weight,
+						},
+					},
+				},
+			},
+		},
+	}
+	if hostname := GetGatewayHostname(cr, defaults); hostname != "" {
+		spec.Hostnames = []gatewayv1.Hostname{gatewayv1.Hostname(hostname)}
+	}
+	return spec
+}
@@ -0,0 +1,123 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGetGatewayHostname(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   registryv1alpha1.DevfileRegistry
+		want string
+	}{
+		{
+			name: "Case 1: Hostname set",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					K8s: registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressDomain: "my-domain"},
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+						Gateway: &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway", Hostname: "registry.example.com"},
+					},
+				},
+			},
+			want: "registry.example.com",
+		},
+		{
+			name: "Case 2: Hostname defaulted from the ingress domain",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					K8s: registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressDomain: "my-domain"},
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+						Gateway: &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway"},
+					},
+				},
+			},
+			want: "test-name-devfile-registry-test-namespace.my-domain",
+		},
+		{
+			name: "Case 3: No hostname nor ingress domain",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+						Gateway: &registryv1alpha1.DevfileRegistrySpecGateway{Name: "gateway"},
+					},
+				},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetGatewayHostname(&tt.cr, config.Defaults{}); got != tt.want {
+				t.Errorf("TestGetGatewayHostname error: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateHTTPRoute(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = registryv1alpha1.AddToScheme(scheme)
+
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+				Gateway: &registryv1alpha1.DevfileRegistrySpecGateway{
+					Name:        "gateway",
+					Namespace:   "gateway-system",
+					SectionName: "https",
+					Hostname:    "registry.example.com",
+				},
+			},
+		},
+	}
+
+	httpRoute := GenerateHTTPRoute(cr, config.Defaults{}, scheme, map[string]string{"app": "test"})
+
+	if httpRoute.Name != IngressName(cr) || httpRoute.Namespace != cr.Namespace {
+		t.Errorf("TestGenerateHTTPRoute error: unexpected name %s/%s", httpRoute.Namespace, httpRoute.Name)
+	}
+	if !metav1.IsControlledBy(httpRoute, cr) {
+		t.Errorf("TestGenerateHTTPRoute error: HTTPRoute not controlled by the DevfileRegistry")
+	}
+	if len(httpRoute.Spec.ParentRefs) != 1 {
+		t.Fatalf("TestGenerateHTTPRoute error: got %d parent refs, want 1", len(httpRoute.Spec.ParentRefs))
+	}
+	parentRef := httpRoute.Spec.ParentRefs[0]
+	if parentRef.Name != "gateway" || *parentRef.Namespace != "gateway-system" || *parentRef.SectionName != "https" {
+		t.Errorf("TestGenerateHTTPRoute error: unexpected parent ref %v", parentRef)
+	}
+	if len(httpRoute.Spec.Hostnames) != 1 || httpRoute.Spec.Hostnames[0] != gatewayv1.Hostname("registry.example.com") {
+		t.Errorf("TestGenerateHTTPRoute error: unexpected hostnames %v", httpRoute.Spec.Hostnames)
+	}
+	backendRef := httpRoute.Spec.Rules[0].BackendRefs[0]
+	if backendRef.Name != gatewayv1.ObjectName(ServiceName(cr)) || *backendRef.Port != gatewayv1.PortNumber(DevfileIndexPort) {
+		t.Errorf("TestGenerateHTTPRoute error: unexpected backend ref %v", backendRef)
+	}
+}
@@ -20,16 +20,19 @@ import (
 	"fmt"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-func GenerateIngress(cr *registryv1alpha1.DevfileRegistry, host string, scheme *runtime.Scheme, labels map[string]string) *networkingv1.Ingress {
+func GenerateIngress(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, host string, scheme *runtime.Scheme, labels map[string]string) *networkingv1.Ingress {
 	pathTypeImplementationSpecific := networkingv1.PathTypeImplementationSpecific
+	ingressClass := GetK8sIngressClass(cr, defaults)
 	ingress := &networkingv1.Ingress{
 		ObjectMeta: generateObjectMeta(IngressName(cr), cr.Namespace, labels),
 		Spec: networkingv1.IngressSpec{
+			IngressClassName: &ingressClass,
 			Rules: []networkingv1.IngressRule{
 				{
 					Host: host,
@@ -57,7 +60,7 @@ func GenerateIngress(cr *registryv1alpha1.DevfileRegistry, host string, scheme *
 		},
 	}
 
-	if IsTLSEnabled(cr) && cr.Spec.TLS.SecretName != "" {
+	if IsTLSEnabled(cr, defaults) && cr.Spec.TLS.SecretName != "" {
 		ingress.Spec.TLS = []networkingv1.IngressTLS{
 			{
 				Hosts:      []string{host},
@@ -66,13 +69,15 @@ func GenerateIngress(cr *registryv1alpha1.DevfileRegistry, host string, scheme *
 		}
 	}
 
+	UpdateUserMetadata(ingress, GetUserLabels(cr, labels), GetIngressAnnotations(cr))
+
 	// Set DevfileRegistry instance as the owner and controller
 	_ = ctrl.SetControllerReference(cr, ingress, scheme)
 	return ingress
 }
 
-func GetDevfileRegistryIngress(cr *registryv1alpha1.DevfileRegistry) string {
-	return GetHostname(cr) + "." + cr.Spec.K8s.IngressDomain
+func GetDevfileRegistryIngress(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) string {
+	return GetHostname(cr) + "." + GetIngressDomain(cr, defaults)
 }
 
 func GetHostname(cr *registryv1alpha1.DevfileRegistry) string {
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -47,7 +48,7 @@ func TestGetDevfileRegistryIngress(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ingress := GetDevfileRegistryIngress(&tt.cr)
+			ingress := GetDevfileRegistryIngress(&tt.cr, config.Defaults{})
 			if ingress != tt.want {
 				t.Errorf("expected: %v got: %v", tt.want, ingress)
 			}
@@ -0,0 +1,170 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+const (
+	// UserLabelsAnnotation lists the keys of the user defined labels set on a generated resource,
+	// so that they can be removed once they are no longer defined on the DevfileRegistry
+	UserLabelsAnnotation = "registry.devfile.io/user-labels"
+	// UserAnnotationsAnnotation lists the keys of the user defined annotations set on a generated resource,
+	// so that they can be removed once they are no longer defined on the DevfileRegistry
+	UserAnnotationsAnnotation = "registry.devfile.io/user-annotations"
+)
+
+// GetUserLabels returns the user defined labels to set on the generated resources, without the ones
+// conflicting with the given selector labels
+func GetUserLabels(cr *registryv1alpha1.DevfileRegistry, selectorLabels map[string]string) map[string]string {
+	labels := map[string]string{}
+	for key, value := range cr.Spec.CommonLabels {
+		if _, isSelectorLabel := selectorLabels[key]; !isSelectorLabel {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// GetDeploymentAnnotations returns the user defined annotations of the registry deployment
+func GetDeploymentAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(cr.Spec.CommonAnnotations, cr.Spec.Annotations.Deployment)
+}
+
+// GetPodAnnotations returns the user defined annotations of the registry pods
+func GetPodAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(cr.Spec.CommonAnnotations, cr.Spec.Annotations.Pod)
+}
+
+// GetServiceAnnotations returns the user defined annotations of the registry service
+func GetServiceAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(cr.Spec.CommonAnnotations, cr.Spec.Annotations.Service)
+}
+
+// GetConfigMapAnnotations returns the user defined annotations of the registry config map
+func GetConfigMapAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(cr.Spec.CommonAnnotations, cr.Spec.Annotations.ConfigMap)
+}
+
+// GetPVCAnnotations returns the user defined annotations of the registry persistent volume claim
+func GetPVCAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(cr.Spec.CommonAnnotations, cr.Spec.Annotations.PersistentVolumeClaim)
+}
+
+// GetIngressAnnotations returns the user defined annotations of the registry ingress
+func GetIngressAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(cr.Spec.CommonAnnotations, cr.Spec.Annotations.Ingress)
+}
+
+// GetRouteAnnotations returns the user defined annotations of the registry route
+func GetRouteAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(cr.Spec.CommonAnnotations, cr.Spec.Annotations.Route)
+}
+
+// GetCommonAnnotations returns the user defined annotations of the generated resources
+// without resource specific annotations
+func GetCommonAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(cr.Spec.CommonAnnotations, nil)
+}
+
+// UpdateUserMetadata sets the given user defined labels and annotations on the object, and removes the ones
+// previously set but no longer defined. Labels and annotations not set by the user are left untouched.
+// Returns true if the object metadata changed.
+func UpdateUserMetadata(obj metav1.Object, labels map[string]string, annotations map[string]string) bool {
+	currentAnnotations := obj.GetAnnotations()
+
+	newLabels := updateUserKeys(obj.GetLabels(), currentAnnotations[UserLabelsAnnotation], labels)
+	newAnnotations := updateUserKeys(currentAnnotations, currentAnnotations[UserAnnotationsAnnotation], annotations)
+	setKeysAnnotation(newAnnotations, UserLabelsAnnotation, labels)
+	setKeysAnnotation(newAnnotations, UserAnnotationsAnnotation, annotations)
+	if len(newAnnotations) == 0 {
+		newAnnotations = nil
+	}
+
+	updated := false
+	if !mapsEqual(obj.GetLabels(), newLabels) {
+		obj.SetLabels(newLabels)
+		updated = true
+	}
+	if !mapsEqual(currentAnnotations, newAnnotations) {
+		obj.SetAnnotations(newAnnotations)
+		updated = true
+	}
+	return updated
+}
+
+// updateUserKeys returns a copy of current without the keys listed in previousKeys and with the desired values set
+func updateUserKeys(current map[string]string, previousKeys string, desired map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, value := range current {
+		result[key] = value
+	}
+	if previousKeys != "" {
+		for _, key := range strings.Split(previousKeys, ",") {
+			delete(result, key)
+		}
+	}
+	for key, value := range desired {
+		result[key] = value
+	}
+	return result
+}
+
+// setKeysAnnotation records the keys of values in the given annotation, or removes it if values is empty
+func setKeysAnnotation(annotations map[string]string, annotation string, values map[string]string) {
+	if len(values) == 0 {
+		delete(annotations, annotation)
+		return
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	annotations[annotation] = strings.Join(keys, ",")
+}
+
+// mergeAnnotations returns the common annotations overridden by the resource specific ones
+func mergeAnnotations(common map[string]string, specific map[string]string) map[string]string {
+	annotations := map[string]string{}
+	for key, value := range common {
+		annotations[key] = value
+	}
+	for key, value := range specific {
+		annotations[key] = value
+	}
+	return annotations
+}
+
+// mapsEqual returns true if both maps have the same content, a nil map being equal to an empty one
+func mapsEqual(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, found := b[key]; !found || other != value {
+			return false
+		}
+	}
+	return true
+}
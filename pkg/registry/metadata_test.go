@@ -0,0 +1,172 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetUserLabels(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-test"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			CommonLabels: map[string]string{"app": "other", "cost-center": "1234"},
+		},
+	}
+	want := map[string]string{"cost-center": "1234"}
+
+	labels := GetUserLabels(cr, LabelsForDevfileRegistry(cr))
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("TestGetUserLabels error: labels mismatch, expected: %v got: %v", want, labels)
+	}
+}
+
+func TestGetServiceAnnotations(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			CommonAnnotations: map[string]string{"owner": "team-a", "tier": "backend"},
+			Annotations: registryv1alpha1.DevfileRegistrySpecAnnotations{
+				Service: map[string]string{"tier": "frontend"},
+			},
+		},
+	}
+	want := map[string]string{"owner": "team-a", "tier": "frontend"}
+
+	annotations := GetServiceAnnotations(cr)
+	if !reflect.DeepEqual(annotations, want) {
+		t.Errorf("TestGetServiceAnnotations error: annotations mismatch, expected: %v got: %v", want, annotations)
+	}
+}
+
+func TestUpdateUserMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		meta            metav1.ObjectMeta
+		labels          map[string]string
+		annotations     map[string]string
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+		wantUpdated     bool
+	}{
+		{
+			name:        "Case 1: No user metadata",
+			meta:        metav1.ObjectMeta{Labels: map[string]string{"app": "devfileregistry"}},
+			wantLabels:  map[string]string{"app": "devfileregistry"},
+			wantUpdated: false,
+		},
+		{
+			name:        "Case 2: User metadata added",
+			meta:        metav1.ObjectMeta{Labels: map[string]string{"app": "devfileregistry"}},
+			labels:      map[string]string{"cost-center": "1234", "team": "devfile"},
+			annotations: map[string]string{"owner": "team-a"},
+			wantLabels:  map[string]string{"app": "devfileregistry", "cost-center": "1234", "team": "devfile"},
+			wantAnnotations: map[string]string{
+				"owner":                   "team-a",
+				UserLabelsAnnotation:      "cost-center,team",
+				UserAnnotationsAnnotation: "owner",
+			},
+			wantUpdated: true,
+		},
+		{
+			name: "Case 3: User metadata already up to date",
+			meta: metav1.ObjectMeta{
+				Labels: map[string]string{"app": "devfileregistry", "cost-center": "1234"},
+				Annotations: map[string]string{
+					"owner":                   "team-a",
+					UserLabelsAnnotation:      "cost-center",
+					UserAnnotationsAnnotation: "owner",
+				},
+			},
+			labels:      map[string]string{"cost-center": "1234"},
+			annotations: map[string]string{"owner": "team-a"},
+			wantLabels:  map[string]string{"app": "devfileregistry", "cost-center": "1234"},
+			wantAnnotations: map[string]string{
+				"owner":                   "team-a",
+				UserLabelsAnnotation:      "cost-center",
+				UserAnnotationsAnnotation: "owner",
+			},
+			wantUpdated: false,
+		},
+		{
+			name: "Case 4: User metadata removed, other metadata kept",
+			meta: metav1.ObjectMeta{
+				Labels: map[string]string{"app": "devfileregistry", "cost-center": "1234"},
+				Annotations: map[string]string{
+					"owner":                       "team-a",
+					"openshift.io/host.generated": "true",
+					UserLabelsAnnotation:          "cost-center",
+					UserAnnotationsAnnotation:     "owner",
+				},
+			},
+			wantLabels:      map[string]string{"app": "devfileregistry"},
+			wantAnnotations: map[string]string{"openshift.io/host.generated": "true"},
+			wantUpdated:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := tt.meta
+			updated := UpdateUserMetadata(&meta, tt.labels, tt.annotations)
+			if updated != tt.wantUpdated {
+				t.Errorf("TestUpdateUserMetadata error: updated mismatch, expected: %v got: %v", tt.wantUpdated, updated)
+			}
+			if !reflect.DeepEqual(meta.Labels, tt.wantLabels) {
+				t.Errorf("TestUpdateUserMetadata error: labels mismatch, expected: %v got: %v", tt.wantLabels, meta.Labels)
+			}
+			if !reflect.DeepEqual(meta.Annotations, tt.wantAnnotations) {
+				t.Errorf("TestUpdateUserMetadata error: annotations mismatch, expected: %v got: %v", tt.wantAnnotations, meta.Annotations)
+			}
+		})
+	}
+}
+
+func TestGenerateDeploymentUserMetadata(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-test"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			CommonLabels:      map[string]string{"cost-center": "1234"},
+			CommonAnnotations: map[string]string{"owner": "team-a"},
+			Annotations: registryv1alpha1.DevfileRegistrySpecAnnotations{
+				Pod: map[string]string{"sidecar.istio.io/inject": "false"},
+			},
+		},
+	}
+	labels := LabelsForDevfileRegistry(cr)
+
+	dep, err := GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), labels)
+	if err != nil {
+		t.Fatalf("TestGenerateDeploymentUserMetadata error: unexpected error generating the deployment: %v", err)
+	}
+	if !reflect.DeepEqual(dep.Spec.Selector.MatchLabels, LabelsForDevfileRegistry(cr)) {
+		t.Errorf("TestGenerateDeploymentUserMetadata error: selector labels changed to %v", dep.Spec.Selector.MatchLabels)
+	}
+	if dep.Labels["cost-center"] != "1234" || dep.Spec.Template.Labels["cost-center"] != "1234" {
+		t.Errorf("TestGenerateDeploymentUserMetadata error: common labels not set")
+	}
+	if dep.Annotations["owner"] != "team-a" || dep.Annotations["sidecar.istio.io/inject"] != "" {
+		t.Errorf("TestGenerateDeploymentUserMetadata error: unexpected deployment annotations %v", dep.Annotations)
+	}
+	if dep.Spec.Template.Annotations["owner"] != "team-a" || dep.Spec.Template.Annotations["sidecar.istio.io/inject"] != "false" {
+		t.Errorf("TestGenerateDeploymentUserMetadata error: unexpected pod annotations %v", dep.Spec.Template.Annotations)
+	}
+}
@@ -0,0 +1,175 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"time"
+
+	indexSchema "github.com/devfile/registry-support/index/generator/schema"
+	registryLibrary "github.com/devfile/registry-support/registry-library/library"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+const (
+	// Default interval at which the stacks of the mirrored devfile registry are synchronized
+	DefaultMirrorInterval = time.Hour
+
+	// Key of the mirror config map holding the index of the mirrored stacks
+	MirrorIndexKey = "index_main.json"
+
+	MirrorVolumeName = "registry-mirror"
+	mirrorMountPath  = "/registry-mirror"
+)
+
+// IsMirrorEnabled returns true if the DevfileRegistry mirrors an upstream devfile registry
+func IsMirrorEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return cr.Spec.Source.Mirror != nil
+}
+
+// GetMirrorInterval returns the interval at which the stacks of the mirrored devfile registry are synchronized
+// Default: 1h
+func GetMirrorInterval(cr *registryv1alpha1.DevfileRegistry) time.Duration {
+	if mirror := cr.Spec.Source.Mirror; mirror != nil && mirror.Interval != nil && mirror.Interval.Duration > 0 {
+		return mirror.Interval.Duration
+	}
+	return DefaultMirrorInterval
+}
+
+// GetMirrorRegistryOptions returns the options of the registry library used to read the mirrored devfile registry
+func GetMirrorRegistryOptions(cr *registryv1alpha1.DevfileRegistry) registryLibrary.RegistryOptions {
+	return registryLibrary.RegistryOptions{
+		SkipTLSVerify:  cr.Spec.Source.Mirror.SkipTLSVerify,
+		NewIndexSchema: true,
+		Telemetry:      registryLibrary.TelemetryData{Client: "registry-operator"},
+	}
+}
+
+// GetMirroredStacks returns the stacks of the given registry index that are stored in an OCI registry, and can be
+// mirrored. Samples, and stacks pulled from Git repositories, are left out.
+func GetMirroredStacks(index []indexSchema.Schema) []indexSchema.Schema {
+	stacks := []indexSchema.Schema{}
+	for _, entry := range index {
+		if entry.Type != indexSchema.StackDevfileType || len(entry.Versions) == 0 {
+			continue
+		}
+		mirrored := true
+		for _, version := range entry.Versions {
+			mirrored = mirrored && version.Links["self"] != ""
+		}
+		if mirrored {
+			stacks = append(stacks, entry)
+		}
+	}
+	return stacks
+}
+
+// GetMirrorIndex returns the registry index served for the mirrored stacks. Their resources are left out, as the
+// devfile index server would otherwise push them to the OCI registry from its own image on startup, while the
+// operator copies them from the mirrored devfile registry.
+func GetMirrorIndex(stacks []indexSchema.Schema) (string, error) {
+	index := make([]indexSchema.Schema, len(stacks))
+	for i, stack := range stacks {
+		index[i] = stack
+		index[i].Resources = nil
+		index[i].Versions = make([]indexSchema.Version, len(stack.Versions))
+		for j, version := range stack.Versions {
+			index[i].Versions[j] = version
+			index[i].Versions[j].Resources = nil
+		}
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GenerateMirrorConfigMap returns a config map holding the given index of the mirrored stacks
+func GenerateMirrorConfigMap(cr *registryv1alpha1.DevfileRegistry, index string, scheme *runtime.Scheme, labels map[string]string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: generateObjectMeta(MirrorConfigMapName(cr), cr.Namespace, labels),
+		Data:       map[string]string{MirrorIndexKey: index},
+	}
+
+	UpdateUserMetadata(cm, GetUserLabels(cr, labels), GetConfigMapAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, cm, scheme)
+	return cm
+}
+
+// GetMirrorVolume returns the volume holding the index of the mirrored stacks
+func GetMirrorVolume(cr *registryv1alpha1.DevfileRegistry) corev1.Volume {
+	return corev1.Volume{
+		Name: MirrorVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: MirrorConfigMapName(cr),
+				},
+			},
+		},
+	}
+}
+
+// GetMirrorVolumeMount returns the mount of the volume holding the index of the mirrored stacks
+func GetMirrorVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      MirrorVolumeName,
+		MountPath: mirrorMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// GetMirrorIndexEnv returns the environment variables pointing the devfile index server to the index of the
+// mirrored stacks, instead of the one of the devfile index image
+func GetMirrorIndexEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "DEVFILE_INDEX", Value: mirrorMountPath + "/" + MirrorIndexKey},
+	}
+}
+
+// IsMirrorSyncDue returns true if the stacks of the mirrored devfile registry need to be synchronized, that is if
+// they never were, the sync interval elapsed since the last sync, or one of the given registry pods became ready
+// since, its OCI registry missing the mirrored stacks if it was started after the last sync
+func IsMirrorSyncDue(cr *registryv1alpha1.DevfileRegistry, pods []corev1.Pod, now time.Time) bool {
+	lastSync := cr.Status.LastMirrorSyncTime
+	if lastSync == nil || !now.Before(lastSync.Add(GetMirrorInterval(cr))) {
+		return true
+	}
+	for i := range pods {
+		if ready, since := GetPodReadyTime(&pods[i]); ready && since.After(lastSync.Time) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPodReadyTime returns true if the given pod is ready, along with the time it became ready
+func GetPodReadyTime(pod *corev1.Pod) (bool, time.Time) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue, condition.LastTransitionTime.Time
+		}
+	}
+	return false, time.Time{}
+}
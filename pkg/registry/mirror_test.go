@@ -0,0 +1,203 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	indexSchema "github.com/devfile/registry-support/index/generator/schema"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetMirroredStacks(t *testing.T) {
+	index := []indexSchema.Schema{
+		{
+			Name: "go",
+			Type: indexSchema.StackDevfileType,
+			Versions: []indexSchema.Version{
+				{Version: "1.0.0", Links: map[string]string{"self": "devfile-catalog/go:1.0.0"}},
+				{Version: "2.0.0", Links: map[string]string{"self": "devfile-catalog/go:2.0.0"}},
+			},
+		},
+		{
+			Name:     "java-maven",
+			Type:     indexSchema.StackDevfileType,
+			Versions: []indexSchema.Version{{Version: "1.0.0"}},
+		},
+		{
+			Name: "nodejs-basic",
+			Type: indexSchema.SampleDevfileType,
+		},
+	}
+
+	stacks := GetMirroredStacks(index)
+	if len(stacks) != 1 || stacks[0].Name != "go" {
+		t.Errorf("TestGetMirroredStacks error: expected only the go stack to be mirrored, got: %v", stacks)
+	}
+}
+
+func TestGetMirrorIndex(t *testing.T) {
+	stacks := []indexSchema.Schema{
+		{
+			Name:      "go",
+			Type:      indexSchema.StackDevfileType,
+			Resources: []string{"devfile.yaml"},
+			Versions: []indexSchema.Version{
+				{
+					Version:   "1.0.0",
+					Links:     map[string]string{"self": "devfile-catalog/go:1.0.0"},
+					Resources: []string{"devfile.yaml", "archive.tar"},
+				},
+			},
+		},
+	}
+
+	data, err := GetMirrorIndex(stacks)
+	if err != nil {
+		t.Fatalf("TestGetMirrorIndex error: unexpected error %v", err)
+	}
+	index := []indexSchema.Schema{}
+	if err := json.Unmarshal([]byte(data), &index); err != nil {
+		t.Fatalf("TestGetMirrorIndex error: invalid index %v", err)
+	}
+	if len(index) != 1 || index[0].Resources != nil || index[0].Versions[0].Resources != nil {
+		t.Errorf("TestGetMirrorIndex error: resources not left out of the index, got: %s", data)
+	}
+	if index[0].Versions[0].Links["self"] != "devfile-catalog/go:1.0.0" {
+		t.Errorf("TestGetMirrorIndex error: link to the stack lost, got: %s", data)
+	}
+	if stacks[0].Versions[0].Resources == nil {
+		t.Errorf("TestGetMirrorIndex error: resources of the given stacks changed")
+	}
+
+	if data, _ := GetMirrorIndex(nil); data != "[]" {
+		t.Errorf("TestGetMirrorIndex error: expected an empty index, got: %s", data)
+	}
+}
+
+func TestGenerateDeploymentMirror(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-test", Namespace: "test"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			Source: registryv1alpha1.DevfileRegistrySpecSource{
+				Mirror: &registryv1alpha1.DevfileRegistrySpecMirrorSource{URL: "https://registry.devfile.io"},
+			},
+		},
+	}
+
+	dep, err := GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+	if err != nil {
+		t.Fatalf("TestGenerateDeploymentMirror error: unexpected error generating the deployment: %v", err)
+	}
+	podSpec := dep.Spec.Template.Spec
+	volumeFound := false
+	for _, volume := range podSpec.Volumes {
+		if volume.Name == MirrorVolumeName {
+			volumeFound = volume.ConfigMap != nil && volume.ConfigMap.Name == MirrorConfigMapName(cr)
+		}
+	}
+	if !volumeFound {
+		t.Errorf("TestGenerateDeploymentMirror error: mirror config map volume not set")
+	}
+
+	indexContainer := podSpec.Containers[0]
+	mounted := false
+	for _, mount := range indexContainer.VolumeMounts {
+		mounted = mounted || mount.Name == MirrorVolumeName
+	}
+	if !mounted {
+		t.Errorf("TestGenerateDeploymentMirror error: mirror volume not mounted in the devfile index container")
+	}
+	for _, want := range GetMirrorIndexEnv() {
+		found := false
+		for _, envVar := range indexContainer.Env {
+			found = found || envVar == want
+		}
+		if !found {
+			t.Errorf("TestGenerateDeploymentMirror error: environment variable %s not set", want.Name)
+		}
+	}
+}
+
+func TestIsMirrorSyncDue(t *testing.T) {
+	now := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	readyPod := func(since time.Time) corev1.Pod {
+		return corev1.Pod{
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(since)},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		interval *metav1.Duration
+		lastSync *metav1.Time
+		pods     []corev1.Pod
+		want     bool
+	}{
+		{
+			name: "Case 1: Never synchronized",
+			want: true,
+		},
+		{
+			name:     "Case 2: Synchronized within the default interval",
+			lastSync: &metav1.Time{Time: now.Add(-30 * time.Minute)},
+			pods:     []corev1.Pod{readyPod(now.Add(-2 * time.Hour))},
+			want:     false,
+		},
+		{
+			name:     "Case 3: Interval elapsed",
+			interval: &metav1.Duration{Duration: 15 * time.Minute},
+			lastSync: &metav1.Time{Time: now.Add(-30 * time.Minute)},
+			want:     true,
+		},
+		{
+			name:     "Case 4: Pod ready since the last sync",
+			lastSync: &metav1.Time{Time: now.Add(-30 * time.Minute)},
+			pods:     []corev1.Pod{readyPod(now.Add(-2 * time.Hour)), readyPod(now.Add(-time.Minute))},
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Source: registryv1alpha1.DevfileRegistrySpecSource{
+						Mirror: &registryv1alpha1.DevfileRegistrySpecMirrorSource{
+							URL:      "https://registry.devfile.io",
+							Interval: tt.interval,
+						},
+					},
+				},
+				Status: registryv1alpha1.DevfileRegistryStatus{LastMirrorSyncTime: tt.lastSync},
+			}
+			if got := IsMirrorSyncDue(cr, tt.pods, now); got != tt.want {
+				t.Errorf("TestIsMirrorSyncDue error: expected %v got %v", tt.want, got)
+			}
+		})
+	}
+}
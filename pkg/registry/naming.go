@@ -17,6 +17,8 @@
 package registry
 
 import (
+	"time"
+
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 )
 
@@ -37,6 +39,19 @@ func ServiceName(cr *registryv1alpha1.DevfileRegistry) string {
 	return GenericResourceName(cr)
 }
 
+// InternalServiceName returns the name of the in-cluster service of the OCI registry and metrics ports, used when
+// the registry service is exposed outside of the cluster
+func InternalServiceName(cr *registryv1alpha1.DevfileRegistry) string {
+	const suffix = "-internal"
+	appFullName := getAppFullName(cr)
+
+	if len(appFullName)+len(suffix) > maxTruncLength {
+		return truncateNameLengthN(appFullName, maxTruncLength-len(suffix)) + suffix
+	}
+
+	return appFullName + suffix
+}
+
 // ConfigMapName returns the name of the service object associated with the DevfileRegistry CR
 func ConfigMapName(cr *registryv1alpha1.DevfileRegistry) string {
 	const suffixLength = 15
@@ -61,3 +76,77 @@ func PVCName(cr *registryv1alpha1.DevfileRegistry) string {
 func IngressName(cr *registryv1alpha1.DevfileRegistry) string {
 	return GenericResourceName(cr)
 }
+
+// OCIRegistryIngressName returns the name of the Ingress or Route exposing the OCI registry of the DevfileRegistry CR
+func OCIRegistryIngressName(cr *registryv1alpha1.DevfileRegistry) string {
+	const suffix = "-oci-registry"
+	appFullName := getAppFullName(cr)
+
+	if len(appFullName)+len(suffix) > maxTruncLength {
+		return truncateNameLengthN(appFullName, maxTruncLength-len(suffix)) + suffix
+	}
+
+	return appFullName + suffix
+}
+
+// ServiceMonitorName returns the name of the ServiceMonitor object associated with the DevfileRegistry CR
+// Just returns the fully qualified app name right now, but extracting to a function to avoid relying on that assumption in the future
+func ServiceMonitorName(cr *registryv1alpha1.DevfileRegistry) string {
+	return GenericResourceName(cr)
+}
+
+// PodDisruptionBudgetName returns the name of the PodDisruptionBudget object associated with the DevfileRegistry CR
+// Just returns the fully qualified app name right now, but extracting to a function to avoid relying on that assumption in the future
+func PodDisruptionBudgetName(cr *registryv1alpha1.DevfileRegistry) string {
+	return GenericResourceName(cr)
+}
+
+// OCIRegistryAuthSecretName returns the name of the secret generated for the authentication to the OCI registry
+func OCIRegistryAuthSecretName(cr *registryv1alpha1.DevfileRegistry) string {
+	const suffix = "-oci-registry-auth"
+	appFullName := getAppFullName(cr)
+
+	if len(appFullName)+len(suffix) > maxTruncLength {
+		return truncateNameLengthN(appFullName, maxTruncLength-len(suffix)) + suffix
+	}
+
+	return appFullName + suffix
+}
+
+// MirrorConfigMapName returns the name of the config map holding the index of the mirrored stacks
+func MirrorConfigMapName(cr *registryv1alpha1.DevfileRegistry) string {
+	const suffix = "-mirror-index"
+	appFullName := getAppFullName(cr)
+
+	if len(appFullName)+len(suffix) > maxTruncLength {
+		return truncateNameLengthN(appFullName, maxTruncLength-len(suffix)) + suffix
+	}
+
+	return appFullName + suffix
+}
+
+// GarbageCollectionCronJobName returns the name of the CronJob collecting the garbage of the OCI registry storage.
+// CronJob names are limited to 52 characters, as the controller appends a suffix to the names of the jobs it creates.
+func GarbageCollectionCronJobName(cr *registryv1alpha1.DevfileRegistry) string {
+	const suffix = "-gc"
+	const maxCronJobNameLength = 52
+	appFullName := getAppFullName(cr)
+
+	if len(appFullName)+len(suffix) > maxCronJobNameLength {
+		return truncateNameLengthN(appFullName, maxCronJobNameLength-len(suffix)) + suffix
+	}
+
+	return appFullName + suffix
+}
+
+// VolumeSnapshotName returns the name of the snapshot of the registry volume scheduled at the given time
+func VolumeSnapshotName(cr *registryv1alpha1.DevfileRegistry, scheduledAt time.Time) string {
+	suffix := "-" + scheduledAt.UTC().Format("20060102-1504")
+	pvcName := PVCName(cr)
+
+	if len(pvcName)+len(suffix) > maxTruncLength {
+		return truncateNameLengthN(pvcName, maxTruncLength-len(suffix)) + suffix
+	}
+
+	return pvcName + suffix
+}
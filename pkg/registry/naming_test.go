@@ -641,3 +641,108 @@ func TestIngressName(t *testing.T) {
 		})
 	}
 }
+
+func TestOCIRegistryAuthSecretName(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   *registryv1alpha1.DevfileRegistry
+		want string
+	}{
+		{
+			name: "Case 1: Default App Full Name",
+			cr:   &registryv1alpha1.DevfileRegistry{},
+			want: "devfile-registry-oci-registry-auth",
+		},
+		{
+			name: "Case 2: Overridden Short App Full Name",
+			cr: &registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					FullnameOverride: "dr",
+				},
+			},
+			want: "dr-oci-registry-auth",
+		},
+		{
+			name: "Case 3: Overridden Long App Full Name",
+			cr: &registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					FullnameOverride: "devfile-registry-testregistry-devfile-io-k8s-prow-environment1-tf433",
+				},
+			},
+			want: "devfile-registry-testregistry-devfile-io-k8s-oci-registry-auth",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := OCIRegistryAuthSecretName(test.cr)
+			if got != test.want {
+				t.Errorf("\nGot: %v\nExpected: %v\n", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGarbageCollectionCronJobName(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   *registryv1alpha1.DevfileRegistry
+		want string
+	}{
+		{
+			name: "Case 1: Default App Full Name",
+			cr:   &registryv1alpha1.DevfileRegistry{},
+			want: "devfile-registry-gc",
+		},
+		{
+			name: "Case 2: Overridden Long App Full Name",
+			cr: &registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					FullnameOverride: "devfile-registry-testregistry-devfile-io-k8s-prow-environment1-tf433",
+				},
+			},
+			want: "devfile-registry-testregistry-devfile-io-k8s-prow-gc",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := GarbageCollectionCronJobName(test.cr)
+			if got != test.want {
+				t.Errorf("\nGot: %v\nExpected: %v\n", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMirrorConfigMapName(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   *registryv1alpha1.DevfileRegistry
+		want string
+	}{
+		{
+			name: "Case 1: Default App Full Name",
+			cr:   &registryv1alpha1.DevfileRegistry{},
+			want: "devfile-registry-mirror-index",
+		},
+		{
+			name: "Case 2: Overridden Long App Full Name",
+			cr: &registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					FullnameOverride: "devfile-registry-testregistry-devfile-io-k8s-prow-environment1-tf433",
+				},
+			},
+			want: "devfile-registry-testregistry-devfile-io-k8s-prow-mirror-index",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := MirrorConfigMapName(test.cr)
+			if got != test.want {
+				t.Errorf("\nGot: %v\nExpected: %v\n", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+const (
+	// Keys of the secret generated for the authentication to the OCI registry
+	OCIRegistryAuthUsernameKey = "username"
+	OCIRegistryAuthPasswordKey = "password"
+	OCIRegistryHtpasswdKey     = "htpasswd"
+	OCIRegistryDockerConfigKey = "config.json"
+
+	// User of the credential generated for the authentication to the OCI registry
+	DefaultOCIRegistryAuthUsername = "devfile-registry"
+
+	// OCIRegistryHtpasswdHashAnnotation is set on the registry pods to the hash of the htpasswd file the OCI registry
+	// was started with, so that the pods are rolled out when it changes
+	OCIRegistryHtpasswdHashAnnotation = "registry.devfile.io/oci-registry-htpasswd-hash"
+
+	OCIRegistryAuthVolumeName = "oci-registry-auth"
+	ociRegistryAuthMountPath  = "/auth"
+	ociRegistryAuthRealm      = "devfile-registry"
+
+	// Volume holding the Docker config file with the credential of the devfile index server, read through the
+	// standard DOCKER_CONFIG environment variable
+	OCIRegistryClientAuthVolumeName = "oci-registry-client-auth"
+	ociRegistryClientAuthMountPath  = "/oci-registry-client-auth"
+	DockerConfigEnv                 = "DOCKER_CONFIG"
+)
+
+// IsOCIRegistryAuthEnabled returns true if ociRegistry.auth is set in the DevfileRegistry CR
+func IsOCIRegistryAuthEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return cr.Spec.OciRegistry.Auth != nil
+}
+
+// GetUserHtpasswdSecretName returns the name of the secret of the user holding an htpasswd file for the OCI registry,
+// or an empty string if there is none
+func GetUserHtpasswdSecretName(cr *registryv1alpha1.DevfileRegistry) string {
+	if !IsOCIRegistryAuthEnabled(cr) {
+		return ""
+	}
+	return cr.Spec.OciRegistry.Auth.HtpasswdSecret
+}
+
+// GetOCIRegistryAuthSecretName returns the name of the secret generated for the authentication to the OCI registry,
+// or an empty string if authentication is disabled
+func GetOCIRegistryAuthSecretName(cr *registryv1alpha1.DevfileRegistry) string {
+	if !IsOCIRegistryAuthEnabled(cr) {
+		return ""
+	}
+	return OCIRegistryAuthSecretName(cr)
+}
+
+// GenerateOCIRegistryAuthSecret returns a secret holding a random credential for the devfile index server and the
+// operator, the Docker config file of the credential and the htpasswd file of the OCI registry, which is the entry of
+// the credential followed by the given htpasswd file of the user
+func GenerateOCIRegistryAuthSecret(cr *registryv1alpha1.DevfileRegistry, userHtpasswd string, scheme *runtime.Scheme, labels map[string]string) *corev1.Secret {
+	password := make([]byte, 24)
+	_, _ = rand.Read(password)
+	encodedPassword := base64.RawURLEncoding.EncodeToString(password)
+
+	secret := &corev1.Secret{
+		ObjectMeta: generateObjectMeta(OCIRegistryAuthSecretName(cr), cr.Namespace, labels),
+		Type:       corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			OCIRegistryAuthUsernameKey: []byte(DefaultOCIRegistryAuthUsername),
+			OCIRegistryAuthPasswordKey: []byte(encodedPassword),
+		},
+	}
+	UpdateOCIRegistryAuthSecretData(cr, secret, userHtpasswd)
+
+	UpdateUserMetadata(secret, GetUserLabels(cr, labels), GetCommonAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, secret, scheme)
+	return secret
+}
+
+// UpdateOCIRegistryAuthSecretData sets the Docker config file and the htpasswd file of the OCI registry in the given
+// generated secret from its credential, followed by the given htpasswd file of the user. The htpasswd entry of the
+// credential is kept while it matches the password, as hashing it again would change the file. Returns true if the
+// secret changed.
+func UpdateOCIRegistryAuthSecretData(cr *registryv1alpha1.DevfileRegistry, secret *corev1.Secret, userHtpasswd string) bool {
+	username := string(secret.Data[OCIRegistryAuthUsernameKey])
+	password := string(secret.Data[OCIRegistryAuthPasswordKey])
+
+	entry, _, _ := strings.Cut(string(secret.Data[OCIRegistryHtpasswdKey]), "\n")
+	user, hash, _ := strings.Cut(entry, ":")
+	if user != username || bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		newHash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		entry = fmt.Sprintf("%s:%s", username, newHash)
+	}
+	htpasswd := entry + "\n"
+	if userHtpasswd = strings.TrimSpace(userHtpasswd); userHtpasswd != "" {
+		htpasswd += userHtpasswd + "\n"
+	}
+	dockerConfig := getOCIRegistryDockerConfig(cr, username, password)
+
+	if string(secret.Data[OCIRegistryHtpasswdKey]) == htpasswd && string(secret.Data[OCIRegistryDockerConfigKey]) == dockerConfig {
+		return false
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[OCIRegistryHtpasswdKey] = []byte(htpasswd)
+	secret.Data[OCIRegistryDockerConfigKey] = []byte(dockerConfig)
+	return true
+}
+
+// getOCIRegistryDockerConfig returns a Docker config file holding the given credential for the addresses the OCI
+// registry is reached at from the registry pods and from the cluster
+func getOCIRegistryDockerConfig(cr *registryv1alpha1.DevfileRegistry, username string, password string) string {
+	auth := map[string]string{"auth": base64.StdEncoding.EncodeToString([]byte(username + ":" + password))}
+	serviceHost := strings.TrimPrefix(GetServiceURL(cr, OCIServerPort), "http://")
+	config, _ := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			fmt.Sprintf("localhost:%d", OCIServerPort): auth,
+			serviceHost: auth,
+		},
+	})
+	return string(config)
+}
+
+// SetOCIRegistryHtpasswdHash sets the hash of the given htpasswd file of the OCI registry on the pod template, or
+// removes it if the file is empty, returns true if it changed
+func SetOCIRegistryHtpasswdHash(template *corev1.PodTemplateSpec, htpasswd string) bool {
+	if htpasswd == "" {
+		if _, found := template.Annotations[OCIRegistryHtpasswdHashAnnotation]; !found {
+			return false
+		}
+		delete(template.Annotations, OCIRegistryHtpasswdHashAnnotation)
+		return true
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(htpasswd)))
+	if template.Annotations[OCIRegistryHtpasswdHashAnnotation] == hash {
+		return false
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[OCIRegistryHtpasswdHashAnnotation] = hash
+	return true
+}
+
+// GetOCIRegistryAuthVolume returns the volume holding the htpasswd file of the OCI registry,
+// or nil if authentication is disabled
+func GetOCIRegistryAuthVolume(cr *registryv1alpha1.DevfileRegistry) *corev1.Volume {
+	if !IsOCIRegistryAuthEnabled(cr) {
+		return nil
+	}
+	defaultMode := corev1.SecretVolumeSourceDefaultMode
+	return &corev1.Volume{
+		Name: OCIRegistryAuthVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: GetOCIRegistryAuthSecretName(cr),
+				Items: []corev1.KeyToPath{
+					{
+						Key:  OCIRegistryHtpasswdKey,
+						Path: OCIRegistryHtpasswdKey,
+					},
+				},
+				DefaultMode: &defaultMode,
+			},
+		},
+	}
+}
+
+// GetOCIRegistryAuthVolumeMount returns the mount of the volume holding the htpasswd file of the OCI registry
+func GetOCIRegistryAuthVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      OCIRegistryAuthVolumeName,
+		MountPath: ociRegistryAuthMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// GetOCIRegistryClientAuthVolume returns the volume holding the Docker config file with the credential of the
+// devfile index server, which pushes the stacks to the OCI registry and reads them back from it, or nil if
+// authentication is disabled
+func GetOCIRegistryClientAuthVolume(cr *registryv1alpha1.DevfileRegistry) *corev1.Volume {
+	if !IsOCIRegistryAuthEnabled(cr) {
+		return nil
+	}
+	defaultMode := corev1.SecretVolumeSourceDefaultMode
+	return &corev1.Volume{
+		Name: OCIRegistryClientAuthVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: GetOCIRegistryAuthSecretName(cr),
+				Items: []corev1.KeyToPath{
+					{
+						Key:  OCIRegistryDockerConfigKey,
+						Path: OCIRegistryDockerConfigKey,
+					},
+				},
+				DefaultMode: &defaultMode,
+			},
+		},
+	}
+}
+
+// GetOCIRegistryClientAuthVolumeMount returns the mount of the volume holding the Docker config file of the devfile
+// index server
+func GetOCIRegistryClientAuthVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      OCIRegistryClientAuthVolumeName,
+		MountPath: ociRegistryClientAuthMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// GetOCIRegistryClientAuthEnv returns the environment variable pointing the devfile index server to its Docker
+// config file
+func GetOCIRegistryClientAuthEnv() corev1.EnvVar {
+	return corev1.EnvVar{Name: DockerConfigEnv, Value: ociRegistryClientAuthMountPath}
+}
+
+// getOCIRegistryAuthConfig returns the auth section of the OCI registry configuration
+func getOCIRegistryAuthConfig(cr *registryv1alpha1.DevfileRegistry) string {
+	if !IsOCIRegistryAuthEnabled(cr) {
+		return ""
+	}
+	return fmt.Sprintf(`
+auth:
+  htpasswd:
+    realm: %s
+    path: %s/%s`, ociRegistryAuthRealm, ociRegistryAuthMountPath, OCIRegistryHtpasswdKey)
+}
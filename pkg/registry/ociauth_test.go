@@ -0,0 +1,241 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	"golang.org/x/crypto/bcrypt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerateOCIRegistryAuthSecret(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-test", Namespace: "test"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+				Auth: &registryv1alpha1.DevfileRegistrySpecOCIAuth{},
+			},
+		},
+	}
+
+	secret := GenerateOCIRegistryAuthSecret(cr, "user:$2y$05$hash", runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+	if secret.Name != GetOCIRegistryAuthSecretName(cr) {
+		t.Errorf("TestGenerateOCIRegistryAuthSecret error: name mismatch, expected: %s got: %s", GetOCIRegistryAuthSecretName(cr), secret.Name)
+	}
+
+	username := string(secret.Data[OCIRegistryAuthUsernameKey])
+	password := string(secret.Data[OCIRegistryAuthPasswordKey])
+	entries := strings.Split(strings.TrimSpace(string(secret.Data[OCIRegistryHtpasswdKey])), "\n")
+	if len(entries) != 2 || entries[1] != "user:$2y$05$hash" {
+		t.Errorf("TestGenerateOCIRegistryAuthSecret error: htpasswd entries of the user not kept, got: %v", entries)
+	}
+	user, hash, found := strings.Cut(entries[0], ":")
+	if !found || user != username {
+		t.Fatalf("TestGenerateOCIRegistryAuthSecret error: htpasswd entry not set for user %s", username)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		t.Errorf("TestGenerateOCIRegistryAuthSecret error: htpasswd entry does not match the password: %v", err)
+	}
+
+	dockerConfig := struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(secret.Data[OCIRegistryDockerConfigKey], &dockerConfig); err != nil {
+		t.Fatalf("TestGenerateOCIRegistryAuthSecret error: invalid Docker config: %v", err)
+	}
+	wantAuth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	if auth := dockerConfig.Auths["localhost:5000"].Auth; auth != wantAuth {
+		t.Errorf("TestGenerateOCIRegistryAuthSecret error: Docker config auth mismatch, expected: %s got: %s", wantAuth, auth)
+	}
+
+	other := GenerateOCIRegistryAuthSecret(cr, "", runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+	if string(other.Data[OCIRegistryAuthPasswordKey]) == password {
+		t.Errorf("TestGenerateOCIRegistryAuthSecret error: password is not random")
+	}
+}
+
+func TestUpdateOCIRegistryAuthSecretData(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-test", Namespace: "test"}}
+	cr.Spec.OciRegistry.Auth = &registryv1alpha1.DevfileRegistrySpecOCIAuth{}
+	secret := GenerateOCIRegistryAuthSecret(cr, "", runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+	entry, _, _ := strings.Cut(string(secret.Data[OCIRegistryHtpasswdKey]), "\n")
+
+	// Case 1: the htpasswd entry of the generated credential is not hashed again
+	if UpdateOCIRegistryAuthSecretData(cr, secret, "") {
+		t.Errorf("TestUpdateOCIRegistryAuthSecretData error: unexpected update of an up to date secret")
+	}
+
+	// Case 2: the htpasswd file of the user is appended to the entry of the generated credential
+	if !UpdateOCIRegistryAuthSecretData(cr, secret, "user:$2y$05$hash\n") {
+		t.Errorf("TestUpdateOCIRegistryAuthSecretData error: expected an update when the htpasswd file of the user changes")
+	}
+	if want := entry + "\nuser:$2y$05$hash\n"; string(secret.Data[OCIRegistryHtpasswdKey]) != want {
+		t.Errorf("TestUpdateOCIRegistryAuthSecretData error: htpasswd mismatch, expected: %s got: %s", want, secret.Data[OCIRegistryHtpasswdKey])
+	}
+
+	// Case 3: the entry is hashed again once the password changed
+	secret.Data[OCIRegistryAuthPasswordKey] = []byte("other-password")
+	if !UpdateOCIRegistryAuthSecretData(cr, secret, "") {
+		t.Errorf("TestUpdateOCIRegistryAuthSecretData error: expected an update when the password changes")
+	}
+	_, hash, _ := strings.Cut(strings.TrimSpace(string(secret.Data[OCIRegistryHtpasswdKey])), ":")
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte("other-password")); err != nil {
+		t.Errorf("TestUpdateOCIRegistryAuthSecretData error: htpasswd entry does not match the password: %v", err)
+	}
+}
+
+func TestGetOCIRegistryAuthSecretName(t *testing.T) {
+	tests := []struct {
+		name string
+		auth *registryv1alpha1.DevfileRegistrySpecOCIAuth
+		want string
+	}{
+		{
+			name: "Case 1: Authentication disabled",
+			want: "",
+		},
+		{
+			name: "Case 2: Generated secret",
+			auth: &registryv1alpha1.DevfileRegistrySpecOCIAuth{},
+			want: "devfileregistry-test-devfile-registry-oci-registry-auth",
+		},
+		{
+			name: "Case 3: Htpasswd secret of the user, served from the generated secret",
+			auth: &registryv1alpha1.DevfileRegistrySpecOCIAuth{HtpasswdSecret: "my-htpasswd"},
+			want: "devfileregistry-test-devfile-registry-oci-registry-auth",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-test"}}
+			cr.Spec.OciRegistry.Auth = tt.auth
+			if name := GetOCIRegistryAuthSecretName(cr); name != tt.want {
+				t.Errorf("TestGetOCIRegistryAuthSecretName error: name mismatch, expected: %s got: %s", tt.want, name)
+			}
+		})
+	}
+}
+
+func TestGetDefaultRegistryConfigAuth(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{}
+	cr.Spec.OciRegistry.Auth = &registryv1alpha1.DevfileRegistrySpecOCIAuth{}
+
+	registryConfig := struct {
+		Auth map[string]map[string]string `json:"auth"`
+	}{}
+	if err := yaml.Unmarshal([]byte(GetDefaultRegistryConfig(cr)), &registryConfig); err != nil {
+		t.Fatalf("TestGetDefaultRegistryConfigAuth error: invalid registry config: %v", err)
+	}
+	want := map[string]map[string]string{
+		"htpasswd": {"realm": "devfile-registry", "path": "/auth/htpasswd"},
+	}
+	if !reflect.DeepEqual(registryConfig.Auth, want) {
+		t.Errorf("TestGetDefaultRegistryConfigAuth error: auth mismatch, expected: %v got: %v", want, registryConfig.Auth)
+	}
+
+	dep, err := GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+	if err != nil {
+		t.Fatalf("TestGetDefaultRegistryConfigAuth error: unexpected error generating the deployment: %v", err)
+	}
+	mounted := false
+	for _, mount := range dep.Spec.Template.Spec.Containers[1].VolumeMounts {
+		if mount.Name == OCIRegistryAuthVolumeName && mount.MountPath+"/"+OCIRegistryHtpasswdKey == want["htpasswd"]["path"] {
+			mounted = true
+		}
+	}
+	if !mounted {
+		t.Errorf("TestGetDefaultRegistryConfigAuth error: htpasswd file not mounted in the OCI registry container")
+	}
+	if probe := dep.Spec.Template.Spec.Containers[1].ReadinessProbe; probe.HTTPGet != nil || probe.TCPSocket == nil {
+		t.Errorf("TestGetDefaultRegistryConfigAuth error: OCI registry probe still requires a credential")
+	}
+}
+
+func TestGetOCIRegistryClientAuthVolume(t *testing.T) {
+	tests := []struct {
+		name       string
+		auth       *registryv1alpha1.DevfileRegistrySpecOCIAuth
+		wantSecret string
+	}{
+		{
+			name: "Case 1: Authentication disabled",
+		},
+		{
+			name:       "Case 2: Generated secret",
+			auth:       &registryv1alpha1.DevfileRegistrySpecOCIAuth{},
+			wantSecret: "devfileregistry-test-devfile-registry-oci-registry-auth",
+		},
+		{
+			name:       "Case 3: Htpasswd secret of the user",
+			auth:       &registryv1alpha1.DevfileRegistrySpecOCIAuth{HtpasswdSecret: "my-htpasswd"},
+			wantSecret: "devfileregistry-test-devfile-registry-oci-registry-auth",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-test"}}
+			cr.Spec.OciRegistry.Auth = tt.auth
+			dep, err := GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+			if err != nil {
+				t.Fatalf("TestGetOCIRegistryClientAuthVolume error: unexpected error generating the deployment: %v", err)
+			}
+
+			// The devfile index server reads the stacks from the OCI registry, so it is given the Docker config file
+			// of the generated credential, never the htpasswd secret of the user
+			indexContainer := dep.Spec.Template.Spec.Containers[0]
+			dockerConfigDir := ""
+			for _, env := range indexContainer.Env {
+				if env.Name == DockerConfigEnv {
+					dockerConfigDir = env.Value
+				}
+			}
+			mounted := false
+			for _, mount := range indexContainer.VolumeMounts {
+				if mount.Name == OCIRegistryClientAuthVolumeName && mount.MountPath == dockerConfigDir {
+					mounted = true
+				}
+			}
+			volume := GetOCIRegistryClientAuthVolume(cr)
+			if tt.wantSecret == "" {
+				if dockerConfigDir != "" || mounted || volume != nil {
+					t.Errorf("TestGetOCIRegistryClientAuthVolume error: credential passed to the devfile index with authentication disabled")
+				}
+				return
+			}
+			if !mounted {
+				t.Errorf("TestGetOCIRegistryClientAuthVolume error: Docker config not mounted at %s", dockerConfigDir)
+			}
+			if volume.Secret.SecretName != tt.wantSecret || volume.Secret.Items[0].Key != OCIRegistryDockerConfigKey {
+				t.Errorf("TestGetOCIRegistryClientAuthVolume error: secret mismatch, expected: %s got: %v", tt.wantSecret, volume.Secret)
+			}
+			if volume := GetOCIRegistryAuthVolume(cr); volume.Secret.SecretName != tt.wantSecret {
+				t.Errorf("TestGetOCIRegistryClientAuthVolume error: htpasswd secret mismatch, expected: %s got: %s", tt.wantSecret, volume.Secret.SecretName)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	routev1 "github.com/openshift/api/route/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+)
+
+const (
+	// ociRegistryAPIPath is the path of the OCI distribution API, the only one exposed for the OCI registry
+	ociRegistryAPIPath = "/v2"
+
+	// ociRegistryIngressBodySizeAnnotation lifts the request body size limit of the NGINX ingress controller, for
+	// the layers of the stacks to be pushed
+	ociRegistryIngressBodySizeAnnotation = "nginx.ingress.kubernetes.io/proxy-body-size"
+	// ociRegistryRouteTimeoutAnnotation raises the timeout of the OpenShift router, for the layers of the stacks to
+	// be pushed and pulled over slow connections
+	ociRegistryRouteTimeoutAnnotation = "haproxy.router.openshift.io/timeout"
+)
+
+// IsOCIRegistryExposed returns true if the OCI registry is exposed outside of the cluster with its own Route or
+// Ingress. Only the Route and Ingress exposure types allow it, and only with the authentication of the OCI registry
+// enabled, as it accepts pushes.
+// Default: false
+func IsOCIRegistryExposed(cr *registryv1alpha1.DevfileRegistry) bool {
+	enabled := cr.Spec.Exposure.OCIRegistry.Enabled
+	if enabled == nil || !*enabled || !IsOCIRegistryAuthEnabled(cr) {
+		return false
+	}
+	exposureType := GetExposureType(cr)
+	return exposureType == ExposureTypeRoute || exposureType == ExposureTypeIngress
+}
+
+// GetOCIRegistryHostname returns the hostname of the exposed OCI registry.
+// Default: the hostname of the registry with an -oci suffix in its ingress domain, or "" if no ingress domain is known
+func GetOCIRegistryHostname(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) string {
+	if cr.Spec.Exposure.OCIRegistry.Hostname != "" {
+		return cr.Spec.Exposure.OCIRegistry.Hostname
+	}
+	if GetIngressDomain(cr, defaults) == "" {
+		return ""
+	}
+	return GetHostname(cr) + "-oci." + GetIngressDomain(cr, defaults)
+}
+
+// GetOCIRegistryIngressAnnotations returns the annotations of the Ingress exposing the OCI registry, the user
+// defined Ingress annotations overriding the default ones
+func GetOCIRegistryIngressAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(map[string]string{ociRegistryIngressBodySizeAnnotation: "0"}, GetIngressAnnotations(cr))
+}
+
+// GetOCIRegistryRouteAnnotations returns the annotations of the Route exposing the OCI registry, the user defined
+// Route annotations overriding the default ones
+func GetOCIRegistryRouteAnnotations(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return mergeAnnotations(map[string]string{ociRegistryRouteTimeoutAnnotation: "10m"}, GetRouteAnnotations(cr))
+}
+
+// GenerateOCIRegistryIngress returns an Ingress exposing the /v2 API of the OCI registry on the given host
+func GenerateOCIRegistryIngress(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, host string, scheme *runtime.Scheme, labels map[string]string) *networkingv1.Ingress {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: generateObjectMeta(OCIRegistryIngressName(cr), cr.Namespace, labels),
+		Spec:       GetOCIRegistryIngressSpec(cr, defaults, host),
+	}
+
+	UpdateUserMetadata(ingress, GetUserLabels(cr, labels), GetOCIRegistryIngressAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, ingress, scheme)
+	return ingress
+}
+
+// GetOCIRegistryIngressSpec returns the spec of the Ingress exposing the /v2 API of the OCI registry on the given host
+func GetOCIRegistryIngressSpec(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, host string) networkingv1.IngressSpec {
+	pathTypePrefix := networkingv1.PathTypePrefix
+	ingressClass := GetK8sIngressClass(cr, defaults)
+	spec := networkingv1.IngressSpec{
+		IngressClassName: &ingressClass,
+		Rules: []networkingv1.IngressRule{
+			{
+				Host: host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path:     ociRegistryAPIPath,
+								PathType: &pathTypePrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: ServiceName(cr),
+										Port: networkingv1.ServiceBackendPort{
+											Number: int32(OCIServerPort),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if IsTLSEnabled(cr, defaults) && cr.Spec.TLS.SecretName != "" {
+		spec.TLS = []networkingv1.IngressTLS{
+			{
+				Hosts:      []string{host},
+				SecretName: cr.Spec.TLS.SecretName,
+			},
+		}
+	}
+	return spec
+}
+
+// GenerateOCIRegistryRoute returns a Route exposing the /v2 API of the OCI registry
+func GenerateOCIRegistryRoute(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, scheme *runtime.Scheme, labels map[string]string) *routev1.Route {
+	weight := int32(100)
+
+	route := &routev1.Route{
+		ObjectMeta: generateObjectMeta(OCIRegistryIngressName(cr), cr.Namespace, labels),
+		Spec: routev1.RouteSpec{
+			// Without a known domain, OpenShift generates the host from the default domain of the router
+			Host: GetOCIRegistryHostname(cr, defaults),
+			To: routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   ServiceName(cr),
+				Weight: &weight,
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(OCIServerPortName),
+			},
+			Path: ociRegistryAPIPath,
+			TLS:  GetOCIRegistryRouteTLS(cr, defaults),
+		},
+	}
+
+	UpdateUserMetadata(route, GetUserLabels(cr, labels), GetOCIRegistryRouteAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, route, scheme)
+	return route
+}
+
+// GetOCIRegistryRouteTLS returns the TLS configuration of the Route exposing the OCI registry, or nil if TLS is
+// disabled
+func GetOCIRegistryRouteTLS(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults) *routev1.TLSConfig {
+	if !IsTLSEnabled(cr, defaults) {
+		return nil
+	}
+	return &routev1.TLSConfig{
+		Termination:                   routev1.TLSTerminationEdge,
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+	}
+}
@@ -0,0 +1,172 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestIsOCIRegistryExposed(t *testing.T) {
+	enabled := true
+	tests := []struct {
+		name         string
+		exposureType string
+		enabled      *bool
+		auth         *registryv1alpha1.DevfileRegistrySpecOCIAuth
+		want         bool
+	}{
+		{
+			name: "Case 1: Not exposed by default",
+			want: false,
+		},
+		{
+			name:    "Case 2: Exposed with an Ingress",
+			enabled: &enabled,
+			auth:    &registryv1alpha1.DevfileRegistrySpecOCIAuth{},
+			want:    true,
+		},
+		{
+			name:         "Case 3: Exposed with a Route",
+			exposureType: ExposureTypeRoute,
+			enabled:      &enabled,
+			auth:         &registryv1alpha1.DevfileRegistrySpecOCIAuth{},
+			want:         true,
+		},
+		{
+			name:         "Case 4: Not exposed with the ClusterIP exposure type",
+			exposureType: ExposureTypeClusterIP,
+			enabled:      &enabled,
+			auth:         &registryv1alpha1.DevfileRegistrySpecOCIAuth{},
+			want:         false,
+		},
+		{
+			name:    "Case 5: Not exposed without authentication",
+			enabled: &enabled,
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+						Type:        tt.exposureType,
+						OCIRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistryExposure{Enabled: tt.enabled},
+					},
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{Auth: tt.auth},
+				},
+			}
+			if got := IsOCIRegistryExposed(cr); got != tt.want {
+				t.Errorf("TestIsOCIRegistryExposed error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetOCIRegistryHostname(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		domain   string
+		want     string
+	}{
+		{
+			name:     "Case 1: Hostname set",
+			hostname: "oci.example.com",
+			domain:   "my-domain",
+			want:     "oci.example.com",
+		},
+		{
+			name:   "Case 2: Hostname defaulted from the ingress domain",
+			domain: "my-domain",
+			want:   "test-name-devfile-registry-test-namespace-oci.my-domain",
+		},
+		{
+			name: "Case 3: No hostname nor ingress domain",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					K8s: registryv1alpha1.DevfileRegistrySpecK8sOnly{IngressDomain: tt.domain},
+					Exposure: registryv1alpha1.DevfileRegistrySpecExposure{
+						OCIRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistryExposure{Hostname: tt.hostname},
+					},
+				},
+			}
+			if got := GetOCIRegistryHostname(cr, config.Defaults{}); got != tt.want {
+				t.Errorf("TestGetOCIRegistryHostname error: expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerateOCIRegistryIngress(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = registryv1alpha1.AddToScheme(scheme)
+
+	tests := []struct {
+		name               string
+		ingressAnnotations map[string]string
+		wantBodySize       string
+	}{
+		{
+			name:         "Case 1: Unlimited body size by default",
+			wantBodySize: "0",
+		},
+		{
+			name:               "Case 2: Body size overridden by the user defined Ingress annotations",
+			ingressAnnotations: map[string]string{ociRegistryIngressBodySizeAnnotation: "512m"},
+			wantBodySize:       "512m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Annotations: registryv1alpha1.DevfileRegistrySpecAnnotations{Ingress: tt.ingressAnnotations},
+				},
+			}
+			ingress := GenerateOCIRegistryIngress(cr, config.Defaults{}, "oci.example.com", scheme, nil)
+
+			if ingress.Name != OCIRegistryIngressName(cr) {
+				t.Errorf("TestGenerateOCIRegistryIngress error: unexpected name %s", ingress.Name)
+			}
+			if got := ingress.Annotations[ociRegistryIngressBodySizeAnnotation]; got != tt.wantBodySize {
+				t.Errorf("TestGenerateOCIRegistryIngress error: body size expected: %v got: %v", tt.wantBodySize, got)
+			}
+			rule := ingress.Spec.Rules[0]
+			path := rule.HTTP.Paths[0]
+			if rule.Host != "oci.example.com" || path.Path != "/v2" || *path.PathType != networkingv1.PathTypePrefix {
+				t.Errorf("TestGenerateOCIRegistryIngress error: unexpected rule %v", rule)
+			}
+			if path.Backend.Service.Port.Number != OCIServerPort {
+				t.Errorf("TestGenerateOCIRegistryIngress error: unexpected backend port %d", path.Backend.Service.Port.Number)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+const (
+	// Default region of the S3 bucket used as the OCI registry storage
+	DefaultOCIRegistryS3Region = "us-east-1"
+
+	// Keys of the S3 credentials in the secret referenced by the DevfileRegistry CR
+	OCIRegistryS3AccessKeyIDKey     = "AWS_ACCESS_KEY_ID"
+	OCIRegistryS3SecretAccessKeyKey = "AWS_SECRET_ACCESS_KEY"
+
+	// Directory of the registry volume used as the OCI registry storage when no other backend is set
+	ociRegistryRootDirectory = "/var/lib/registry"
+)
+
+// IsOCIRegistryS3StorageEnabled returns true if the OCI registry stores its content in an S3-compatible bucket
+func IsOCIRegistryS3StorageEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return cr.Spec.OciRegistry.Storage.S3 != nil
+}
+
+// GetOCIRegistryS3Region returns the region of the S3 bucket used as the OCI registry storage
+// Default: "us-east-1"
+func GetOCIRegistryS3Region(cr *registryv1alpha1.DevfileRegistry) string {
+	if s3 := cr.Spec.OciRegistry.Storage.S3; s3 != nil && s3.Region != "" {
+		return s3.Region
+	}
+	return DefaultOCIRegistryS3Region
+}
+
+// isOCIRegistryS3Secure returns true if the OCI registry reaches the S3 bucket over HTTPS
+// Default: true
+func isOCIRegistryS3Secure(cr *registryv1alpha1.DevfileRegistry) bool {
+	if s3 := cr.Spec.OciRegistry.Storage.S3; s3 != nil && s3.Secure != nil {
+		return *s3.Secure
+	}
+	return true
+}
+
+// GetOCIRegistryStorageEnv returns the environment variables passing the storage credentials to the OCI registry
+// container. The credentials are read from the secret referenced by the DevfileRegistry CR, so that they never
+// end up in the registry config map.
+// Default: nil
+func GetOCIRegistryStorageEnv(cr *registryv1alpha1.DevfileRegistry) []corev1.EnvVar {
+	s3 := cr.Spec.OciRegistry.Storage.S3
+	if s3 == nil || s3.CredentialsSecret == "" {
+		return nil
+	}
+	return []corev1.EnvVar{
+		secretKeyEnvVar("REGISTRY_STORAGE_S3_ACCESSKEY", s3.CredentialsSecret, OCIRegistryS3AccessKeyIDKey),
+		secretKeyEnvVar("REGISTRY_STORAGE_S3_SECRETKEY", s3.CredentialsSecret, OCIRegistryS3SecretAccessKeyKey),
+	}
+}
+
+// getOCIRegistryStorageConfig returns the storage driver section of the OCI registry configuration
+func getOCIRegistryStorageConfig(cr *registryv1alpha1.DevfileRegistry) string {
+	s3 := cr.Spec.OciRegistry.Storage.S3
+	if s3 == nil {
+		return fmt.Sprintf(`
+  filesystem:
+    rootdirectory: %s`, ociRegistryRootDirectory)
+	}
+
+	storageConfig := fmt.Sprintf(`
+  s3:
+    region: %q
+    bucket: %q
+    secure: %t`, GetOCIRegistryS3Region(cr), s3.Bucket, isOCIRegistryS3Secure(cr))
+	if s3.RegionEndpoint != "" {
+		storageConfig += fmt.Sprintf(`
+    regionendpoint: %q`, s3.RegionEndpoint)
+	}
+	if s3.RootDirectory != "" {
+		storageConfig += fmt.Sprintf(`
+    rootdirectory: %q`, s3.RootDirectory)
+	}
+	return storageConfig
+}
+
+func secretKeyEnvVar(name string, secretName string, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretName,
+				},
+				Key: key,
+			},
+		},
+	}
+}
@@ -0,0 +1,163 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"gopkg.in/yaml.v2"
+)
+
+func TestGetRegistryConfigStorage(t *testing.T) {
+	secure := false
+
+	tests := []struct {
+		name string
+		cr   registryv1alpha1.DevfileRegistry
+		want map[string]map[string]interface{}
+	}{
+		{
+			name: "Case 1: Default filesystem storage",
+			cr:   registryv1alpha1.DevfileRegistry{},
+			want: map[string]map[string]interface{}{
+				"cache":      {"blobdescriptor": "inmemory"},
+				"filesystem": {"rootdirectory": "/var/lib/registry"},
+			},
+		},
+		{
+			name: "Case 2: S3 storage with defaults",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+						Storage: registryv1alpha1.DevfileRegistrySpecOCIStorage{
+							S3: &registryv1alpha1.DevfileRegistrySpecS3Storage{
+								Bucket: "devfile-registry",
+							},
+						},
+					},
+				},
+			},
+			want: map[string]map[string]interface{}{
+				"cache": {"blobdescriptor": "inmemory"},
+				"s3":    {"region": "us-east-1", "bucket": "devfile-registry", "secure": true},
+			},
+		},
+		{
+			name: "Case 3: S3-compatible storage",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+						Storage: registryv1alpha1.DevfileRegistrySpecOCIStorage{
+							S3: &registryv1alpha1.DevfileRegistrySpecS3Storage{
+								Bucket:         "devfile-registry",
+								Region:         "eu-west-1",
+								RegionEndpoint: "http://minio.minio.svc:9000",
+								RootDirectory:  "/stacks",
+								Secure:         &secure,
+							},
+						},
+					},
+				},
+			},
+			want: map[string]map[string]interface{}{
+				"cache": {"blobdescriptor": "inmemory"},
+				"s3": {
+					"region":         "eu-west-1",
+					"bucket":         "devfile-registry",
+					"secure":         false,
+					"regionendpoint": "http://minio.minio.svc:9000",
+					"rootdirectory":  "/stacks",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := struct {
+				Storage map[string]map[string]interface{} `yaml:"storage"`
+			}{}
+			if err := yaml.Unmarshal([]byte(GetDefaultRegistryConfig(&tt.cr)), &config); err != nil {
+				t.Fatalf("TestGetRegistryConfigStorage error: invalid registry config: %v", err)
+			}
+			if !reflect.DeepEqual(config.Storage, tt.want) {
+				t.Errorf("TestGetRegistryConfigStorage error: storage mismatch, expected: %v got: %v", tt.want, config.Storage)
+			}
+		})
+	}
+}
+
+func TestGetOCIRegistryStorageEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   registryv1alpha1.DevfileRegistry
+		want []string
+	}{
+		{
+			name: "Case 1: Default filesystem storage",
+			cr:   registryv1alpha1.DevfileRegistry{},
+			want: nil,
+		},
+		{
+			name: "Case 2: S3 storage without credentials secret",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+						Storage: registryv1alpha1.DevfileRegistrySpecOCIStorage{
+							S3: &registryv1alpha1.DevfileRegistrySpecS3Storage{Bucket: "devfile-registry"},
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "Case 3: S3 storage with credentials secret",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					OciRegistry: registryv1alpha1.DevfileRegistrySpecOCIRegistry{
+						Storage: registryv1alpha1.DevfileRegistrySpecOCIStorage{
+							S3: &registryv1alpha1.DevfileRegistrySpecS3Storage{
+								Bucket:            "devfile-registry",
+								CredentialsSecret: "minio-credentials",
+							},
+						},
+					},
+				},
+			},
+			want: []string{"REGISTRY_STORAGE_S3_ACCESSKEY", "REGISTRY_STORAGE_S3_SECRETKEY"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := GetOCIRegistryStorageEnv(&tt.cr)
+			var names []string
+			for _, envVar := range env {
+				names = append(names, envVar.Name)
+				if envVar.Value != "" || envVar.ValueFrom == nil || envVar.ValueFrom.SecretKeyRef == nil {
+					t.Errorf("TestGetOCIRegistryStorageEnv error: %s is not read from a secret", envVar.Name)
+				} else if envVar.ValueFrom.SecretKeyRef.Name != tt.cr.Spec.OciRegistry.Storage.S3.CredentialsSecret {
+					t.Errorf("TestGetOCIRegistryStorageEnv error: %s is read from secret %s", envVar.Name, envVar.ValueFrom.SecretKeyRef.Name)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("TestGetOCIRegistryStorageEnv error: env mismatch, expected: %v got: %v", tt.want, names)
+			}
+		})
+	}
+}
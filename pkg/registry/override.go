@@ -0,0 +1,71 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+// PodTemplateOverrideHashAnnotation is set on the registry deployment to the hash of the pod template override
+// applied to it, so that a changed or removed override can be detected
+const PodTemplateOverrideHashAnnotation = "registry.devfile.io/pod-template-override-hash"
+
+// GetPodTemplateOverrideHash returns the hash of the pod template override of the DevfileRegistry,
+// or an empty string if no override is set
+func GetPodTemplateOverrideHash(cr *registryv1alpha1.DevfileRegistry) string {
+	if cr.Spec.PodTemplateOverride == nil || len(cr.Spec.PodTemplateOverride.Raw) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(cr.Spec.PodTemplateOverride.Raw))
+}
+
+// ApplyPodTemplateOverride applies the pod template override of the DevfileRegistry on top of the given pod template
+// as a strategic merge patch. The selector labels are set back afterwards, as the deployment selector cannot change.
+func ApplyPodTemplateOverride(cr *registryv1alpha1.DevfileRegistry, template *corev1.PodTemplateSpec, selectorLabels map[string]string) error {
+	if cr.Spec.PodTemplateOverride == nil || len(cr.Spec.PodTemplateOverride.Raw) == 0 {
+		return nil
+	}
+
+	original, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, cr.Spec.PodTemplateOverride.Raw, corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf("failed to apply the pod template override: %w", err)
+	}
+	result := corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return fmt.Errorf("failed to apply the pod template override: %w", err)
+	}
+
+	for key, value := range selectorLabels {
+		if result.Labels == nil {
+			result.Labels = map[string]string{}
+		}
+		result.Labels[key] = value
+	}
+	*template = result
+	return nil
+}
@@ -0,0 +1,126 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestApplyPodTemplateOverride(t *testing.T) {
+	override := `{
+		"metadata": {
+			"labels": {"app": "other", "team": "devfile"},
+			"annotations": {"sidecar.istio.io/inject": "false"}
+		},
+		"spec": {
+			"containers": [
+				{"name": "devfile-registry", "env": [{"name": "HTTP_PROXY", "value": "http://proxy:3128"}]},
+				{"name": "sidecar", "image": "quay.io/test/sidecar:latest"}
+			],
+			"volumes": [
+				{"name": "extra", "emptyDir": {}}
+			]
+		}
+	}`
+	cr := &registryv1alpha1.DevfileRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfileregistry-test"},
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			PodTemplateOverride: &apiextensionsv1.JSON{Raw: []byte(override)},
+		},
+	}
+	labels := LabelsForDevfileRegistry(cr)
+
+	dep, err := GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), labels)
+	if err != nil {
+		t.Fatalf("TestApplyPodTemplateOverride error: unexpected error generating the deployment: %v", err)
+	}
+	template := dep.Spec.Template
+
+	if dep.Annotations[PodTemplateOverrideHashAnnotation] != GetPodTemplateOverrideHash(cr) {
+		t.Errorf("TestApplyPodTemplateOverride error: hash annotation mismatch, expected: %s got: %s",
+			GetPodTemplateOverrideHash(cr), dep.Annotations[PodTemplateOverrideHashAnnotation])
+	}
+	if template.Labels["app"] != labels["app"] || template.Labels["team"] != "devfile" {
+		t.Errorf("TestApplyPodTemplateOverride error: unexpected pod labels %v", template.Labels)
+	}
+	if template.Annotations["sidecar.istio.io/inject"] != "false" {
+		t.Errorf("TestApplyPodTemplateOverride error: unexpected pod annotations %v", template.Annotations)
+	}
+
+	containers := map[string]corev1.Container{}
+	for _, container := range template.Spec.Containers {
+		containers[container.Name] = container
+	}
+	for _, name := range []string{"devfile-registry", "oci-registry", "registry-viewer", "sidecar"} {
+		if _, found := containers[name]; !found || len(containers) != 4 {
+			t.Errorf("TestApplyPodTemplateOverride error: container %s not found in %v", name, template.Spec.Containers)
+		}
+	}
+
+	indexContainer := containers["devfile-registry"]
+	if indexContainer.Image != GetDevfileIndexImage(cr, config.Defaults{}) {
+		t.Errorf("TestApplyPodTemplateOverride error: index image mismatch, expected: %s got: %s", GetDevfileIndexImage(cr, config.Defaults{}), indexContainer.Image)
+	}
+	envVars := map[string]string{}
+	for _, env := range indexContainer.Env {
+		envVars[env.Name] = env.Value
+	}
+	if len(envVars) != 3 || envVars["HTTP_PROXY"] != "http://proxy:3128" {
+		t.Errorf("TestApplyPodTemplateOverride error: unexpected env %v", indexContainer.Env)
+	}
+
+	volumeNames := []string{}
+	for _, volume := range template.Spec.Volumes {
+		volumeNames = append(volumeNames, volume.Name)
+	}
+	if len(volumeNames) != 3 {
+		t.Errorf("TestApplyPodTemplateOverride error: unexpected volumes %v", volumeNames)
+	}
+
+	// Applying the override again must not change the pod template
+	if err := ApplyPodTemplateOverride(cr, &template, labels); err != nil {
+		t.Errorf("TestApplyPodTemplateOverride error: unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(template, dep.Spec.Template) {
+		t.Errorf("TestApplyPodTemplateOverride error: override is not idempotent")
+	}
+}
+
+func TestApplyPodTemplateOverrideInvalid(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{
+		Spec: registryv1alpha1.DevfileRegistrySpec{
+			PodTemplateOverride: &apiextensionsv1.JSON{Raw: []byte(`{"spec":{"containers":{"name":"sidecar"}}}`)},
+		},
+	}
+	template := corev1.PodTemplateSpec{}
+	if err := ApplyPodTemplateOverride(cr, &template, nil); err == nil {
+		t.Errorf("TestApplyPodTemplateOverrideInvalid error: expected an error")
+	}
+
+	// The deployment is not generated without its override
+	if dep, err := GenerateDeployment(cr, config.Defaults{}, runtime.NewScheme(), LabelsForDevfileRegistry(cr)); err == nil || dep != nil {
+		t.Errorf("TestApplyPodTemplateOverrideInvalid error: expected an error generating the deployment, got %v", dep)
+	}
+}
@@ -0,0 +1,49 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+// GeneratePodDisruptionBudget returns a PodDisruptionBudget keeping the registry available during voluntary
+// disruptions, such as node drains, when running in high-availability mode
+func GeneratePodDisruptionBudget(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(DefaultPodDisruptionBudgetUnavailable)
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: generateObjectMeta(PodDisruptionBudgetName(cr), cr.Namespace, labels),
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+		},
+	}
+
+	UpdateUserMetadata(pdb, GetUserLabels(cr, labels), GetCommonAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, pdb, scheme)
+	return pdb
+}
@@ -0,0 +1,170 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+const (
+	devfileIndexProbePath   = "/health"
+	ociRegistryProbePath    = "/v2"
+	registryViewerProbePath = "/viewer"
+
+	// Number of failed checks allowed by the default startup probes, giving the containers up to
+	// 5 minutes to start before they are restarted
+	defaultStartupProbeFailureThreshold = 30
+)
+
+// GetDevfileIndexLivenessProbe returns the liveness probe of the devfile index container
+// Default: GET /health on port 8080, after 15s, every 10s, with a 3s timeout
+func GetDevfileIndexLivenessProbe(cr *registryv1alpha1.DevfileRegistry) *corev1.Probe {
+	return getDevfileRegistrySpecContainerProbe(cr.Spec.DevfileIndex.LivenessProbe,
+		defaultHTTPProbe(devfileIndexProbePath, DevfileIndexPort, 15, 10, 3, 3))
+}
+
+// GetDevfileIndexReadinessProbe returns the readiness probe of the devfile index container
+// Default: GET /health on port 8080, after 15s, every 10s, with a 3s timeout
+func GetDevfileIndexReadinessProbe(cr *registryv1alpha1.DevfileRegistry) *corev1.Probe {
+	return getDevfileRegistrySpecContainerProbe(cr.Spec.DevfileIndex.ReadinessProbe,
+		defaultHTTPProbe(devfileIndexProbePath, DevfileIndexPort, 15, 10, 3, 3))
+}
+
+// GetDevfileIndexStartupProbe returns the startup probe of the devfile index container
+// Default: GET /health on port 8080, every 10s, with a 3s timeout, for up to 5 minutes
+func GetDevfileIndexStartupProbe(cr *registryv1alpha1.DevfileRegistry) *corev1.Probe {
+	return getDevfileRegistrySpecContainerProbe(cr.Spec.DevfileIndex.StartupProbe,
+		defaultHTTPProbe(devfileIndexProbePath, DevfileIndexPort, 0, 10, 3, defaultStartupProbeFailureThreshold))
+}
+
+// GetOCIRegistryLivenessProbe returns the liveness probe of the OCI registry container
+// Default: GET /v2 on port 5000, after 30s, every 10s, with a 3s timeout. A TCP check if authentication is enabled.
+func GetOCIRegistryLivenessProbe(cr *registryv1alpha1.DevfileRegistry) *corev1.Probe {
+	return getDevfileRegistrySpecContainerProbe(cr.Spec.OciRegistry.LivenessProbe,
+		defaultOCIRegistryProbe(cr, 30, 10, 3, 3))
+}
+
+// GetOCIRegistryReadinessProbe returns the readiness probe of the OCI registry container
+// Default: GET /v2 on port 5000, after 3s, every 10s, with a 3s timeout. A TCP check if authentication is enabled.
+func GetOCIRegistryReadinessProbe(cr *registryv1alpha1.DevfileRegistry) *corev1.Probe {
+	return getDevfileRegistrySpecContainerProbe(cr.Spec.OciRegistry.ReadinessProbe,
+		defaultOCIRegistryProbe(cr, 3, 10, 3, 3))
+}
+
+// GetOCIRegistryStartupProbe returns the startup probe of the OCI registry container
+// Default: GET /v2 on port 5000, every 10s, with a 3s timeout, for up to 5 minutes. A TCP check if authentication is enabled.
+func GetOCIRegistryStartupProbe(cr *registryv1alpha1.DevfileRegistry) *corev1.Probe {
+	return getDevfileRegistrySpecContainerProbe(cr.Spec.OciRegistry.StartupProbe,
+		defaultOCIRegistryProbe(cr, 0, 10, 3, defaultStartupProbeFailureThreshold))
+}
+
+// GetRegistryViewerLivenessProbe returns the liveness probe of the registry viewer container
+// Default: GET /viewer on port 3000, after 15s, every 10s, with a 20s timeout
+func GetRegistryViewerLivenessProbe(cr *registryv1alpha1.DevfileRegistry) *corev1.Probe {
+	return getDevfileRegistrySpecContainerProbe(cr.Spec.RegistryViewer.LivenessProbe,
+		defaultHTTPProbe(registryViewerProbePath, RegistryViewerPort, 15, 10, 20, 3))
+}
+
+// GetRegistryViewerReadinessProbe returns the readiness probe of the registry viewer container
+// Default: GET /viewer on port 3000, after 15s, every 10s, with a 20s timeout
+func GetRegistryViewerReadinessProbe(cr *registryv1alpha1.DevfileRegistry) *corev1.Probe {
+	return getDevfileRegistrySpecContainerProbe(cr.Spec.RegistryViewer.ReadinessProbe,
+		defaultHTTPProbe(registryViewerProbePath, RegistryViewerPort, 15, 10, 20, 3))
+}
+
+// GetRegistryViewerStartupProbe returns the startup probe of the registry viewer container
+// Default: GET /viewer on port 3000, after 30s, every 10s, with a 20s timeout, for up to 5 minutes
+func GetRegistryViewerStartupProbe(cr *registryv1alpha1.DevfileRegistry) *corev1.Probe {
+	return getDevfileRegistrySpecContainerProbe(cr.Spec.RegistryViewer.StartupProbe,
+		defaultHTTPProbe(registryViewerProbePath, RegistryViewerPort, 30, 10, 20, defaultStartupProbeFailureThreshold))
+}
+
+// defaultHTTPProbe returns an HTTP probe with every field the API server would otherwise default set,
+// so that it can be compared with the probes of an existing deployment
+func defaultHTTPProbe(path string, port int, initialDelaySeconds int32, periodSeconds int32, timeoutSeconds int32, failureThreshold int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   path,
+				Port:   intstr.FromInt(port),
+				Scheme: corev1.URISchemeHTTP,
+			},
+		},
+		InitialDelaySeconds: initialDelaySeconds,
+		PeriodSeconds:       periodSeconds,
+		TimeoutSeconds:      timeoutSeconds,
+		SuccessThreshold:    1,
+		FailureThreshold:    failureThreshold,
+	}
+}
+
+// defaultOCIRegistryProbe returns the default probe of the OCI registry container. As /v2 requires a credential once
+// authentication is enabled, the probe then only checks that the OCI registry accepts connections.
+func defaultOCIRegistryProbe(cr *registryv1alpha1.DevfileRegistry, initialDelaySeconds int32, periodSeconds int32, timeoutSeconds int32, failureThreshold int32) *corev1.Probe {
+	probe := defaultHTTPProbe(ociRegistryProbePath, OCIServerPort, initialDelaySeconds, periodSeconds, timeoutSeconds, failureThreshold)
+	if IsOCIRegistryAuthEnabled(cr) {
+		probe.ProbeHandler = corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(OCIServerPort),
+			},
+		}
+	}
+	return probe
+}
+
+// getDevfileRegistrySpecContainerProbe overlays the probe set on a container block on top of the default probe.
+// The default handler is kept unless the probe sets one, and unset fields keep their default value.
+func getDevfileRegistrySpecContainerProbe(probe *corev1.Probe, defaultProbe *corev1.Probe) *corev1.Probe {
+	if probe == nil {
+		return defaultProbe
+	}
+
+	result := defaultProbe.DeepCopy()
+	if probe.Exec != nil || probe.HTTPGet != nil || probe.TCPSocket != nil || probe.GRPC != nil {
+		result.ProbeHandler = *probe.ProbeHandler.DeepCopy()
+		if result.HTTPGet != nil {
+			if result.HTTPGet.Path == "" {
+				result.HTTPGet.Path = "/"
+			}
+			if result.HTTPGet.Scheme == "" {
+				result.HTTPGet.Scheme = corev1.URISchemeHTTP
+			}
+		}
+	}
+	if probe.InitialDelaySeconds != 0 {
+		result.InitialDelaySeconds = probe.InitialDelaySeconds
+	}
+	if probe.PeriodSeconds != 0 {
+		result.PeriodSeconds = probe.PeriodSeconds
+	}
+	if probe.TimeoutSeconds != 0 {
+		result.TimeoutSeconds = probe.TimeoutSeconds
+	}
+	if probe.SuccessThreshold != 0 {
+		result.SuccessThreshold = probe.SuccessThreshold
+	}
+	if probe.FailureThreshold != 0 {
+		result.FailureThreshold = probe.FailureThreshold
+	}
+	if probe.TerminationGracePeriodSeconds != nil {
+		result.TerminationGracePeriodSeconds = probe.TerminationGracePeriodSeconds
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestGetDevfileIndexStartupProbe(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   registryv1alpha1.DevfileRegistry
+		want *corev1.Probe
+	}{
+		{
+			name: "Case 1: Default startup probe",
+			cr:   registryv1alpha1.DevfileRegistry{},
+			want: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path:   "/health",
+						Port:   intstr.FromInt(DevfileIndexPort),
+						Scheme: corev1.URISchemeHTTP,
+					},
+				},
+				PeriodSeconds:    10,
+				TimeoutSeconds:   3,
+				SuccessThreshold: 1,
+				FailureThreshold: 30,
+			},
+		},
+		{
+			name: "Case 2: Startup probe timings set",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					DevfileIndex: registryv1alpha1.DevfileRegistrySpecContainer{
+						StartupProbe: &corev1.Probe{
+							InitialDelaySeconds: 5,
+							FailureThreshold:    60,
+						},
+					},
+				},
+			},
+			want: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path:   "/health",
+						Port:   intstr.FromInt(DevfileIndexPort),
+						Scheme: corev1.URISchemeHTTP,
+					},
+				},
+				InitialDelaySeconds: 5,
+				PeriodSeconds:       10,
+				TimeoutSeconds:      3,
+				SuccessThreshold:    1,
+				FailureThreshold:    60,
+			},
+		},
+		{
+			name: "Case 3: Startup probe handler set",
+			cr: registryv1alpha1.DevfileRegistry{
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					DevfileIndex: registryv1alpha1.DevfileRegistrySpecContainer{
+						StartupProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: &corev1.HTTPGetAction{
+									Port: intstr.FromInt(DevfileIndexPort),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path:   "/",
+						Port:   intstr.FromInt(DevfileIndexPort),
+						Scheme: corev1.URISchemeHTTP,
+					},
+				},
+				PeriodSeconds:    10,
+				TimeoutSeconds:   3,
+				SuccessThreshold: 1,
+				FailureThreshold: 30,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := GetDevfileIndexStartupProbe(&tt.cr)
+			if !reflect.DeepEqual(probe, tt.want) {
+				t.Errorf("TestGetDevfileIndexStartupProbe error: probe mismatch, expected: %v got: %v", tt.want, probe)
+			}
+		})
+	}
+}
+
+func TestGetContainerProbesPorts(t *testing.T) {
+	cr := &registryv1alpha1.DevfileRegistry{}
+
+	tests := []struct {
+		name   string
+		probes []*corev1.Probe
+		port   int
+	}{
+		{
+			name:   "Case 1: Devfile index probes",
+			probes: []*corev1.Probe{GetDevfileIndexLivenessProbe(cr), GetDevfileIndexReadinessProbe(cr), GetDevfileIndexStartupProbe(cr)},
+			port:   DevfileIndexPort,
+		},
+		{
+			name:   "Case 2: OCI registry probes",
+			probes: []*corev1.Probe{GetOCIRegistryLivenessProbe(cr), GetOCIRegistryReadinessProbe(cr), GetOCIRegistryStartupProbe(cr)},
+			port:   OCIServerPort,
+		},
+		{
+			name:   "Case 3: Registry viewer probes",
+			probes: []*corev1.Probe{GetRegistryViewerLivenessProbe(cr), GetRegistryViewerReadinessProbe(cr), GetRegistryViewerStartupProbe(cr)},
+			port:   RegistryViewerPort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, probe := range tt.probes {
+				if probe.HTTPGet.Port.IntValue() != tt.port {
+					t.Errorf("TestGetContainerProbesPorts error: port mismatch, expected: %d got: %d", tt.port, probe.HTTPGet.Port.IntValue())
+				}
+			}
+		})
+	}
+}
@@ -23,10 +23,11 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/config"
 )
 
 // GenerateRoute returns a route exposing the devfile registry index
-func GenerateRoute(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *routev1.Route {
+func GenerateRoute(cr *registryv1alpha1.DevfileRegistry, defaults config.Defaults, scheme *runtime.Scheme, labels map[string]string) *routev1.Route {
 	weight := int32(100)
 
 	route := &routev1.Route{
@@ -44,13 +45,20 @@ func GenerateRoute(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme,
 		},
 	}
 
-	if IsTLSEnabled(cr) {
+	// Without a known domain, OpenShift generates the host from the default domain of the router
+	if GetIngressDomain(cr, defaults) != "" {
+		route.Spec.Host = GetDevfileRegistryIngress(cr, defaults)
+	}
+
+	if IsTLSEnabled(cr, defaults) {
 		route.Spec.TLS = &routev1.TLSConfig{
 			Termination:                   routev1.TLSTerminationEdge,
 			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
 		}
 	}
 
+	UpdateUserMetadata(route, GetUserLabels(cr, labels), GetRouteAnnotations(cr))
+
 	// Set DevfileRegistry instance as the owner and controller
 	_ = ctrl.SetControllerReference(cr, route, scheme)
 	return route
@@ -0,0 +1,77 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+// GetNodeSelector returns the node selector of the registry pods.
+// Default: nil
+func GetNodeSelector(cr *registryv1alpha1.DevfileRegistry) map[string]string {
+	return cr.Spec.Scheduling.NodeSelector
+}
+
+// GetTolerations returns the tolerations of the registry pods.
+// Default: nil
+func GetTolerations(cr *registryv1alpha1.DevfileRegistry) []corev1.Toleration {
+	return cr.Spec.Scheduling.Tolerations
+}
+
+// GetAffinity returns the affinity of the registry pods. Unless the DevfileRegistry CR sets its own pod anti-affinity,
+// pods in high-availability mode prefer to be scheduled on different nodes, so that a single node going down does
+// not take the registry offline.
+// Default: nil
+func GetAffinity(cr *registryv1alpha1.DevfileRegistry) *corev1.Affinity {
+	affinity := cr.Spec.Scheduling.Affinity.DeepCopy()
+	if !IsHighAvailabilityEnabled(cr) || (affinity != nil && affinity.PodAntiAffinity != nil) {
+		return affinity
+	}
+
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+			{
+				Weight: DefaultPodAntiAffinityWeight,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: LabelsForDevfileRegistry(cr),
+					},
+					TopologyKey: corev1.LabelHostname,
+				},
+			},
+		},
+	}
+	return affinity
+}
+
+// GetTopologySpreadConstraints returns the topology spread constraints of the registry pods.
+// Default: nil
+func GetTopologySpreadConstraints(cr *registryv1alpha1.DevfileRegistry) []corev1.TopologySpreadConstraint {
+	return cr.Spec.Scheduling.TopologySpreadConstraints
+}
+
+// GetPriorityClassName returns the priority class name of the registry pods.
+// Default: ""
+func GetPriorityClassName(cr *registryv1alpha1.DevfileRegistry) string {
+	return cr.Spec.Scheduling.PriorityClassName
+}
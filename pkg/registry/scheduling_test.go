@@ -0,0 +1,123 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetAffinity(t *testing.T) {
+	crName := "devfileregistry-test"
+	replicas := int32(2)
+	nodeAffinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{
+							Key:      "node-role.kubernetes.io/infra",
+							Operator: corev1.NodeSelectorOpExists,
+						},
+					},
+				},
+			},
+		},
+	}
+	podAntiAffinity := &corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+			{
+				TopologyKey: corev1.LabelTopologyZone,
+			},
+		},
+	}
+	defaultPodAntiAffinity := &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+			{
+				Weight: DefaultPodAntiAffinityWeight,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": DefaultAppName, "devfileregistry_cr": crName},
+					},
+					TopologyKey: corev1.LabelHostname,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		cr   registryv1alpha1.DevfileRegistry
+		want *corev1.Affinity
+	}{
+		{
+			name: "Case 1: Affinity not set with a single replica",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: crName},
+			},
+			want: nil,
+		},
+		{
+			name: "Case 2: Affinity not set with multiple replicas",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: crName},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Replicas: &replicas,
+				},
+			},
+			want: &corev1.Affinity{PodAntiAffinity: defaultPodAntiAffinity},
+		},
+		{
+			name: "Case 3: Node affinity set with multiple replicas",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: crName},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Replicas: &replicas,
+					Scheduling: registryv1alpha1.DevfileRegistrySpecScheduling{
+						Affinity: &corev1.Affinity{NodeAffinity: nodeAffinity},
+					},
+				},
+			},
+			want: &corev1.Affinity{NodeAffinity: nodeAffinity, PodAntiAffinity: defaultPodAntiAffinity},
+		},
+		{
+			name: "Case 4: Pod anti-affinity set with multiple replicas",
+			cr: registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: crName},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Replicas: &replicas,
+					Scheduling: registryv1alpha1.DevfileRegistrySpecScheduling{
+						Affinity: &corev1.Affinity{PodAntiAffinity: podAntiAffinity},
+					},
+				},
+			},
+			want: &corev1.Affinity{PodAntiAffinity: podAntiAffinity},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			affinity := GetAffinity(&tt.cr)
+			if !reflect.DeepEqual(affinity, tt.want) {
+				t.Errorf("TestGetAffinity error: affinity mismatch, expected: %v got: %v", tt.want, affinity)
+			}
+		})
+	}
+}
@@ -24,30 +24,80 @@ import (
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 )
 
-// GenerateDevfileRegistryService returns a devfileregistry Service object
+// GenerateService returns a devfileregistry Service object
 func GenerateService(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.Service {
 	svc := &corev1.Service{
 		ObjectMeta: generateObjectMeta(ServiceName(cr), cr.Namespace, labels),
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
-				{
-					Name: DevfileIndexPortName,
-					Port: DevfileIndexPort,
-				},
-				{
-					Name: DevfileIndexMetricsPortName,
-					Port: DevfileIndexMetricsPort,
-				},
-				{
-					Name: OCIMetricsPortName,
-					Port: OCIMetricsPort,
-				},
-			},
+			Ports:    GetServicePorts(cr),
 			Selector: labels,
+			Type:     GetServiceType(cr),
 		},
 	}
 
+	UpdateUserMetadata(svc, GetUserLabels(cr, labels), GetServiceAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, svc, scheme)
+	return svc
+}
+
+// GenerateInternalService returns the ClusterIP Service of the OCI registry and metrics ports, which are kept off the
+// registry service when it is exposed outside of the cluster
+func GenerateInternalService(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: generateObjectMeta(InternalServiceName(cr), cr.Namespace, labels),
+		Spec: corev1.ServiceSpec{
+			Ports:    GetInternalServicePorts(),
+			Selector: labels,
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+
+	UpdateUserMetadata(svc, GetUserLabels(cr, labels), nil)
+
 	// Set DevfileRegistry instance as the owner and controller
 	_ = ctrl.SetControllerReference(cr, svc, scheme)
 	return svc
 }
+
+// GetServicePorts returns the ports of the devfile registry service: the ones of the devfile index and the registry
+// viewer when not headless. The ports of the OCI registry and the metrics are only added when the service is not
+// reachable from outside of the cluster, as the OCI registry accepts pushes; the internal service holds them otherwise.
+func GetServicePorts(cr *registryv1alpha1.DevfileRegistry) []corev1.ServicePort {
+	ports := []corev1.ServicePort{
+		{
+			Name: DevfileIndexPortName,
+			Port: DevfileIndexPort,
+		},
+	}
+	if !IsServiceExternal(cr) {
+		ports = append(ports, GetInternalServicePorts()...)
+	}
+	if !IsHeadlessEnabled(cr) {
+		ports = append(ports, corev1.ServicePort{
+			Name: RegistryViewerPortName,
+			Port: RegistryViewerPort,
+		})
+	}
+	return ports
+}
+
+// GetInternalServicePorts returns the ports only reachable inside of the cluster: the ones of the OCI registry and
+// the metrics
+func GetInternalServicePorts() []corev1.ServicePort {
+	return []corev1.ServicePort{
+		{
+			Name: DevfileIndexMetricsPortName,
+			Port: DevfileIndexMetricsPort,
+		},
+		{
+			Name: OCIMetricsPortName,
+			Port: OCIMetricsPort,
+		},
+		{
+			Name: OCIServerPortName,
+			Port: OCIServerPort,
+		},
+	}
+}
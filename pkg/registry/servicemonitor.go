@@ -0,0 +1,92 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+// metricsPath is the path the devfile index and the OCI registry serve their metrics on
+const metricsPath = "/metrics"
+
+// IsMonitoringEnabled returns true if a ServiceMonitor scraping the registry metrics is generated.
+// Default: false
+func IsMonitoringEnabled(cr *registryv1alpha1.DevfileRegistry) bool {
+	return cr.Spec.Monitoring.Enabled != nil && *cr.Spec.Monitoring.Enabled
+}
+
+// GetServiceMonitorLabels returns the user defined labels of the ServiceMonitor, the monitoring labels overriding
+// the common ones, without the ones conflicting with the given selector labels
+func GetServiceMonitorLabels(cr *registryv1alpha1.DevfileRegistry, selectorLabels map[string]string) map[string]string {
+	labels := GetUserLabels(cr, selectorLabels)
+	for key, value := range cr.Spec.Monitoring.Labels {
+		if _, isSelectorLabel := selectorLabels[key]; !isSelectorLabel {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// GenerateServiceMonitor returns a ServiceMonitor scraping the metrics of the devfile index and the OCI registry
+// through the registry service
+func GenerateServiceMonitor(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *monitoringv1.ServiceMonitor {
+	serviceMonitor := &monitoringv1.ServiceMonitor{
+		ObjectMeta: generateObjectMeta(ServiceMonitorName(cr), cr.Namespace, labels),
+		Spec:       GetServiceMonitorSpec(cr, labels),
+	}
+
+	UpdateUserMetadata(serviceMonitor, GetServiceMonitorLabels(cr, labels), GetCommonAnnotations(cr))
+
+	// Set DevfileRegistry instance as the owner and controller
+	_ = ctrl.SetControllerReference(cr, serviceMonitor, scheme)
+	return serviceMonitor
+}
+
+// GetServiceMonitorSpec returns the spec of the ServiceMonitor selecting the registry service with the given labels
+func GetServiceMonitorSpec(cr *registryv1alpha1.DevfileRegistry, labels map[string]string) monitoringv1.ServiceMonitorSpec {
+	var interval monitoringv1.Duration
+	if cr.Spec.Monitoring.Interval != nil {
+		interval = monitoringv1.Duration(model.Duration(cr.Spec.Monitoring.Interval.Duration).String())
+	}
+
+	selectorLabels := map[string]string{}
+	for key, value := range labels {
+		selectorLabels[key] = value
+	}
+
+	return monitoringv1.ServiceMonitorSpec{
+		Selector: metav1.LabelSelector{MatchLabels: selectorLabels},
+		Endpoints: []monitoringv1.Endpoint{
+			{
+				Port:     DevfileIndexMetricsPortName,
+				Path:     metricsPath,
+				Interval: interval,
+			},
+			{
+				Port:     OCIMetricsPortName,
+				Path:     metricsPath,
+				Interval: interval,
+			},
+		},
+	}
+}
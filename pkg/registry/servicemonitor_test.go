@@ -0,0 +1,87 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+	"time"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGenerateServiceMonitor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = registryv1alpha1.AddToScheme(scheme)
+	enabled := true
+
+	tests := []struct {
+		name         string
+		monitoring   registryv1alpha1.DevfileRegistrySpecMonitoring
+		wantInterval monitoringv1.Duration
+		wantLabels   map[string]string
+	}{
+		{
+			name:         "Case 1: Default scrape interval",
+			monitoring:   registryv1alpha1.DevfileRegistrySpecMonitoring{Enabled: &enabled},
+			wantInterval: "",
+			wantLabels:   map[string]string{"app": "devfile-registry"},
+		},
+		{
+			name: "Case 2: Scrape interval and labels",
+			monitoring: registryv1alpha1.DevfileRegistrySpecMonitoring{
+				Enabled:  &enabled,
+				Interval: &metav1.Duration{Duration: 90 * time.Second},
+				Labels:   map[string]string{"release": "prometheus", "app": "ignored"},
+			},
+			wantInterval: "1m30s",
+			wantLabels:   map[string]string{"app": "devfile-registry", "release": "prometheus"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace"},
+				Spec:       registryv1alpha1.DevfileRegistrySpec{Monitoring: tt.monitoring},
+			}
+			serviceMonitor := GenerateServiceMonitor(cr, scheme, map[string]string{"app": "devfile-registry"})
+
+			if !metav1.IsControlledBy(serviceMonitor, cr) {
+				t.Errorf("TestGenerateServiceMonitor error: ServiceMonitor not controlled by the DevfileRegistry")
+			}
+			for key, value := range tt.wantLabels {
+				if serviceMonitor.Labels[key] != value {
+					t.Errorf("TestGenerateServiceMonitor error: label %s expected: %v got: %v", key, value, serviceMonitor.Labels[key])
+				}
+			}
+			if serviceMonitor.Spec.Selector.MatchLabels["app"] != "devfile-registry" || len(serviceMonitor.Spec.Selector.MatchLabels) != 1 {
+				t.Errorf("TestGenerateServiceMonitor error: unexpected selector %v", serviceMonitor.Spec.Selector.MatchLabels)
+			}
+			wantPorts := []string{DevfileIndexMetricsPortName, OCIMetricsPortName}
+			if len(serviceMonitor.Spec.Endpoints) != len(wantPorts) {
+				t.Fatalf("TestGenerateServiceMonitor error: expected %d endpoints, got: %v", len(wantPorts), serviceMonitor.Spec.Endpoints)
+			}
+			for i, endpoint := range serviceMonitor.Spec.Endpoints {
+				if endpoint.Port != wantPorts[i] || endpoint.Path != "/metrics" || endpoint.Interval != tt.wantInterval {
+					t.Errorf("TestGenerateServiceMonitor error: unexpected endpoint %v", endpoint)
+				}
+			}
+		})
+	}
+}
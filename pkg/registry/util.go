@@ -21,8 +21,13 @@ import (
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 )
 
+// DevfileRegistryLabel is the label holding the name of the DevfileRegistry the generated resources belong to
+const DevfileRegistryLabel = "devfileregistry_cr"
+
 // truncateName truncates given name to default 63 characters,
 // trims "-" from suffix if last character
 func truncateName(name string) string {
@@ -55,8 +60,14 @@ func generateObjectMeta(name string, namespace string, labels map[string]string)
 // belonging to the given devfileregistry CR name.
 func LabelsForDevfileRegistry(cr *registryv1alpha1.DevfileRegistry) map[string]string {
 	if cr != nil {
-		return map[string]string{"app": getAppName(cr), "devfileregistry_cr": cr.Name}
+		return map[string]string{"app": getAppName(cr), DevfileRegistryLabel: cr.Name}
 	}
 
 	return map[string]string{"app": DefaultAppName}
 }
+
+// GeneratedResourcesSelector selects the resources generated for any DevfileRegistry
+func GeneratedResourcesSelector() labels.Selector {
+	requirement, _ := labels.NewRequirement(DevfileRegistryLabel, selection.Exists, nil)
+	return labels.NewSelector().Add(*requirement)
+}
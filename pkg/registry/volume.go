@@ -17,6 +17,9 @@
 package registry
 
 import (
+	"fmt"
+	"reflect"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,21 +28,51 @@ import (
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 )
 
-// GenerateDevfileRegistryPVC returns a PVC for providing storage on the OCI registry container
-func GeneratePVC(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) *corev1.PersistentVolumeClaim {
+// GeneratePVC returns a PVC for providing storage on the OCI registry container, or an error if the requested volume
+// size is not a valid quantity
+func GeneratePVC(cr *registryv1alpha1.DevfileRegistry, scheme *runtime.Scheme, labels map[string]string) (*corev1.PersistentVolumeClaim, error) {
+	size, err := resource.ParseQuantity(GetDevfileRegistryVolumeSize(cr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry volume size: %w", err)
+	}
+
 	pvc := &corev1.PersistentVolumeClaim{
 		ObjectMeta: generateObjectMeta(PVCName(cr), cr.Namespace, labels),
 		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			AccessModes:      getDevfileRegistryVolumeAccessModes(cr),
+			StorageClassName: getDevfileRegistryVolumeStorageClassName(cr),
 			Resources: corev1.VolumeResourceRequirements{
 				Requests: corev1.ResourceList{
-					corev1.ResourceStorage: resource.MustParse(getDevfileRegistryVolumeSize(cr)),
+					corev1.ResourceStorage: size,
 				},
 			},
 		},
 	}
 
+	// Provision the volume from the restore source, if any
+	if dataSource := getPVCDataSource(cr); dataSource != nil {
+		pvc.Spec.DataSource = dataSource
+		pvc.Annotations = map[string]string{RestoredFromAnnotation: GetRestoreSource(cr)}
+	}
+
+	UpdateUserMetadata(pvc, GetUserLabels(cr, labels), GetPVCAnnotations(cr))
+
 	// Set DevfileRegistry instance as the owner and controller
 	_ = ctrl.SetControllerReference(cr, pvc, scheme)
-	return pvc
+	return pvc, nil
+}
+
+// GetImmutablePVCChanges returns the settings of the persistent volume that differ from the ones of the existing PVC,
+// and cannot be applied to it as they are immutable
+func GetImmutablePVCChanges(cr *registryv1alpha1.DevfileRegistry, pvc *corev1.PersistentVolumeClaim) []string {
+	var changes []string
+	// Without a storage class, the default one of the cluster was set on the PVC
+	if storageClassName := getDevfileRegistryVolumeStorageClassName(cr); storageClassName != nil &&
+		(pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != *storageClassName) {
+		changes = append(changes, "storageClassName")
+	}
+	if !reflect.DeepEqual(getDevfileRegistryVolumeAccessModes(cr), pvc.Spec.AccessModes) {
+		changes = append(changes, "accessModes")
+	}
+	return changes
 }
@@ -0,0 +1,73 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGeneratePVC(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     string
+		wantSize string
+		wantErr  bool
+	}{
+		{
+			name:     "Case 1: Default volume size",
+			wantSize: DefaultDevfileRegistryVolumeSize,
+		},
+		{
+			name:     "Case 2: Custom volume size",
+			size:     "5Gi",
+			wantSize: "5Gi",
+		},
+		{
+			name:    "Case 3: Invalid volume size",
+			size:    "abc",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &registryv1alpha1.DevfileRegistry{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec: registryv1alpha1.DevfileRegistrySpec{
+					Storage: registryv1alpha1.DevfileRegistrySpecStorage{RegistryVolumeSize: tt.size},
+				},
+			}
+			pvc, err := GeneratePVC(cr, runtime.NewScheme(), LabelsForDevfileRegistry(cr))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("TestGeneratePVC error: expected an error for the volume size %q", tt.size)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TestGeneratePVC error: unexpected error %v", err)
+			}
+			if got := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; got.String() != tt.wantSize {
+				t.Errorf("TestGeneratePVC error: volume size %s, want %s", got.String(), tt.wantSize)
+			}
+		})
+	}
+}
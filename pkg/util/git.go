@@ -0,0 +1,136 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitCredentials are the credentials used to read a Git repository over HTTP(S)
+type GitCredentials struct {
+	Username string
+	Password string
+}
+
+var gitCommitPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// IsGitCommit returns true if the given revision is a full commit hash
+func IsGitCommit(revision string) bool {
+	return gitCommitPattern.MatchString(strings.ToLower(revision))
+}
+
+// ResolveGitRevision returns the commit the given branch, tag or commit of a Git repository points to, reading the
+// references advertised by the repository over the smart HTTP protocol. An empty revision resolves to the default
+// branch of the repository.
+func ResolveGitRevision(ctx context.Context, repoURL string, revision string, credentials *GitCredentials) (string, error) {
+	if IsGitCommit(revision) {
+		return strings.ToLower(revision), nil
+	}
+
+	refs, err := listGitRefs(ctx, repoURL, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := []string{"HEAD"}
+	if revision != "" && revision != "HEAD" {
+		// Prefer the commit of an annotated tag to the tag object itself
+		candidates = []string{revision, "refs/heads/" + revision, "refs/tags/" + revision + "^{}", "refs/tags/" + revision}
+	}
+	for _, ref := range candidates {
+		if commit, found := refs[ref]; found {
+			return commit, nil
+		}
+	}
+	return "", fmt.Errorf("revision %q not found in Git repository %s", revision, repoURL)
+}
+
+// listGitRefs returns the commits of the references advertised by a Git repository, by name
+func listGitRefs(ctx context.Context, repoURL string, credentials *GitCredentials) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(repoURL, "/")+"/info/refs?service=git-upload-pack", nil)
+	if err != nil {
+		return nil, err
+	}
+	if credentials != nil {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("authentication to Git repository %s failed: %s", repoURL, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to list the references of Git repository %s: %s", repoURL, resp.Status)
+	case resp.Header.Get("Content-Type") != "application/x-git-upload-pack-advertisement":
+		return nil, fmt.Errorf("the Git repository %s does not support the smart HTTP protocol", repoURL)
+	}
+
+	refs := map[string]string{}
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := readPktLine(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the references of Git repository %s: %w", repoURL, err)
+		}
+		// The first reference carries the capabilities of the server after a NUL byte
+		line, _, _ = strings.Cut(strings.TrimSuffix(line, "\n"), "\x00")
+		commit, ref, found := strings.Cut(line, " ")
+		if !found || !IsGitCommit(commit) {
+			// Service announcement and flush packets
+			continue
+		}
+		refs[ref] = commit
+	}
+	return refs, nil
+}
+
+// readPktLine reads a packet of the Git protocol, returning an empty line for flush packets
+func readPktLine(reader *bufio.Reader) (string, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return "", err
+	}
+	length, err := strconv.ParseUint(string(header), 16, 16)
+	if err != nil {
+		return "", fmt.Errorf("invalid packet length %q", header)
+	}
+	if length < 4 {
+		return "", nil
+	}
+	data := make([]byte, length-4)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
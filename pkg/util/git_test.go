@@ -0,0 +1,103 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	mainCommit = "1111111111111111111111111111111111111111"
+	tagObject  = "2222222222222222222222222222222222222222"
+	tagCommit  = "3333333333333333333333333333333333333333"
+)
+
+func pktLine(line string) string {
+	return fmt.Sprintf("%04x%s", len(line)+4, line)
+}
+
+// newGitServer returns a server advertising the references of a repository over the smart HTTP protocol,
+// requiring the given password if not empty
+func newGitServer(password string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stacks.git/info/refs" || r.URL.Query().Get("service") != "git-upload-pack" {
+			http.NotFound(w, r)
+			return
+		}
+		if _, p, _ := r.BasicAuth(); p != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		_, _ = w.Write([]byte(strings.Join([]string{
+			pktLine("# service=git-upload-pack\n"),
+			"0000",
+			pktLine(mainCommit + " HEAD\x00multi_ack symref=HEAD:refs/heads/main\n"),
+			pktLine(mainCommit + " refs/heads/main\n"),
+			pktLine(tagObject + " refs/tags/v1.0.0\n"),
+			pktLine(tagCommit + " refs/tags/v1.0.0^{}\n"),
+			"0000",
+		}, "")))
+	}))
+}
+
+func TestResolveGitRevision(t *testing.T) {
+	server := newGitServer("")
+	defer server.Close()
+	privateServer := newGitServer("token")
+	defer privateServer.Close()
+
+	tests := []struct {
+		name        string
+		url         string
+		revision    string
+		credentials *GitCredentials
+		want        string
+		wantErr     bool
+	}{
+		{name: "Case 1: Default branch", url: server.URL + "/stacks.git", want: mainCommit},
+		{name: "Case 2: Branch", url: server.URL + "/stacks.git/", revision: "main", want: mainCommit},
+		{name: "Case 3: Annotated tag resolves to its commit", url: server.URL + "/stacks.git", revision: "v1.0.0", want: tagCommit},
+		{name: "Case 4: Full reference", url: server.URL + "/stacks.git", revision: "refs/heads/main", want: mainCommit},
+		{name: "Case 5: Commit is not looked up", url: "http://invalid.example", revision: strings.ToUpper(tagCommit), want: tagCommit},
+		{name: "Case 6: Missing revision", url: server.URL + "/stacks.git", revision: "dev", wantErr: true},
+		{name: "Case 7: Missing repository", url: server.URL + "/missing.git", wantErr: true},
+		{name: "Case 8: Missing credentials", url: privateServer.URL + "/stacks.git", wantErr: true},
+		{
+			name:        "Case 9: Credentials",
+			url:         privateServer.URL + "/stacks.git",
+			credentials: &GitCredentials{Username: "git", Password: "token"},
+			want:        mainCommit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveGitRevision(context.Background(), tt.url, tt.revision, tt.credentials)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TestResolveGitRevision error: unexpected error %v, expected error: %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("TestResolveGitRevision error: commit mismatch, expected: %q got: %q", tt.want, got)
+			}
+		})
+	}
+}
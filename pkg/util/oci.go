@@ -0,0 +1,72 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"oras.land/oras-go/pkg/oras"
+)
+
+// OCIRegistry is an OCI registry artifacts are copied from or to
+type OCIRegistry struct {
+	// Host, and port, of the registry
+	Host string
+	// PlainHTTP is true if the registry is served over HTTP rather than HTTPS
+	PlainHTTP bool
+	// SkipTLSVerify is true if the certificate of the registry is not verified
+	SkipTLSVerify bool
+	// Username and Password authenticate to the registry, if set
+	Username string
+	Password string
+}
+
+// resolver returns the resolver of the references of the artifacts stored in the registry
+func (r OCIRegistry) resolver() remotes.Resolver {
+	client := &http.Client{
+		Timeout: 5 * time.Minute,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			/* #nosec G402 -- skipping the verification is requested for registries with self-signed certificates */
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: r.SkipTLSVerify},
+		},
+	}
+	options := docker.ResolverOptions{PlainHTTP: r.PlainHTTP, Client: client}
+	if r.Username != "" {
+		options.Credentials = func(string) (string, string, error) {
+			return r.Username, r.Password, nil
+		}
+	}
+	return docker.NewResolver(options)
+}
+
+// CopyOCIArtifact copies the layers of an artifact with the allowed media types from a registry to another,
+// keeping its repository and tag
+func CopyOCIArtifact(ctx context.Context, from OCIRegistry, to OCIRegistry, ref string, allowedMediaTypes []string) error {
+	_, err := oras.Copy(ctx, from.resolver(), from.Host+"/"+ref, to.resolver(), to.Host+"/"+ref,
+		oras.WithAllowedMediaTypes(allowedMediaTypes))
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from %s to %s: %w", ref, from.Host, to.Host, err)
+	}
+	return nil
+}
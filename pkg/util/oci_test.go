@@ -0,0 +1,199 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	devfileMediaType  = "application/vnd.devfileio.devfile.layer.v1"
+	manifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// fakeOCIRegistry is an in-memory OCI registry serving the subset of the distribution API used to copy artifacts
+type fakeOCIRegistry struct {
+	sync.Mutex
+	username string
+	password string
+	blobs    map[string][]byte
+	types    map[string]string
+	tags     map[string]string
+}
+
+func newFakeOCIRegistry(username string, password string) *fakeOCIRegistry {
+	return &fakeOCIRegistry{
+		username: username,
+		password: password,
+		blobs:    map[string][]byte{},
+		types:    map[string]string{},
+		tags:     map[string]string{},
+	}
+}
+
+// put stores the given content and returns its digest
+func (f *fakeOCIRegistry) put(content []byte, mediaType string) string {
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+	f.blobs[digest] = content
+	f.types[digest] = mediaType
+	return digest
+}
+
+func (f *fakeOCIRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	if username, password, _ := r.BasicAuth(); f.username != "" && (username != f.username || password != f.password) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+
+	switch repo, ref, isManifest := strings.Cut(path, "/manifests/"); {
+	case r.URL.Path == "/v2/":
+		w.WriteHeader(http.StatusOK)
+	case isManifest && r.Method == http.MethodPut:
+		content, _ := io.ReadAll(r.Body)
+		digest := f.put(content, r.Header.Get("Content-Type"))
+		f.tags[repo+":"+ref] = digest
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusCreated)
+	case isManifest:
+		digest := ref
+		if !strings.HasPrefix(ref, "sha256:") {
+			digest = f.tags[repo+":"+ref]
+		}
+		f.serveBlob(w, r, digest)
+	case strings.Contains(path, "/blobs/uploads/") && r.Method == http.MethodPost:
+		repo, _, _ := strings.Cut(path, "/blobs/uploads/")
+		w.Header().Set("Location", "/v2/"+repo+"/blobs/uploads/upload")
+		w.WriteHeader(http.StatusAccepted)
+	case strings.Contains(path, "/blobs/uploads/") && r.Method == http.MethodPut:
+		content, _ := io.ReadAll(r.Body)
+		digest := f.put(content, "")
+		if digest != r.URL.Query().Get("digest") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/blobs/"):
+		_, digest, _ := strings.Cut(path, "/blobs/")
+		f.serveBlob(w, r, digest)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeOCIRegistry) serveBlob(w http.ResponseWriter, r *http.Request, digest string) {
+	content, found := f.blobs[digest]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if f.types[digest] != "" {
+		w.Header().Set("Content-Type", f.types[digest])
+	}
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(content)
+	}
+}
+
+func TestCopyOCIArtifact(t *testing.T) {
+	upstream := newFakeOCIRegistry("", "")
+	devfile := []byte("schemaVersion: 2.2.0\n")
+	devfileDigest := upstream.put(devfile, "")
+	config := []byte("{}")
+	configDigest := upstream.put(config, "")
+	manifest, _ := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     manifestMediaType,
+		"config":        map[string]interface{}{"mediaType": "application/vnd.devfileio.devfile.config.v2+json", "digest": configDigest, "size": len(config)},
+		"layers": []map[string]interface{}{
+			{"mediaType": devfileMediaType, "digest": devfileDigest, "size": len(devfile)},
+		},
+	})
+	upstream.tags["devfile-catalog/go:1.0.0"] = upstream.put(manifest, manifestMediaType)
+	upstreamServer := httptest.NewServer(upstream)
+	defer upstreamServer.Close()
+
+	local := newFakeOCIRegistry("devfile-registry", "secret")
+	localServer := httptest.NewServer(local)
+	defer localServer.Close()
+
+	from := OCIRegistry{Host: strings.TrimPrefix(upstreamServer.URL, "http://"), PlainHTTP: true}
+	to := OCIRegistry{Host: strings.TrimPrefix(localServer.URL, "http://"), PlainHTTP: true}
+
+	tests := []struct {
+		name     string
+		ref      string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "Case 1: Wrong credentials",
+			ref:      "devfile-catalog/go:1.0.0",
+			password: "wrong",
+			wantErr:  true,
+		},
+		{
+			name:    "Case 2: Missing artifact",
+			ref:     "devfile-catalog/go:2.0.0",
+			wantErr: true,
+		},
+		{
+			name: "Case 3: Artifact copied",
+			ref:  "devfile-catalog/go:1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to.Username = "devfile-registry"
+			to.Password = "secret"
+			if tt.password != "" {
+				to.Password = tt.password
+			}
+			err := CopyOCIArtifact(context.TODO(), from, to, tt.ref, []string{devfileMediaType})
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("TestCopyOCIArtifact error: unexpected error %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if _, found := local.tags[tt.ref]; !found {
+				t.Errorf("TestCopyOCIArtifact error: manifest of %s not copied", tt.ref)
+			}
+			if string(local.blobs[devfileDigest]) != string(devfile) {
+				t.Errorf("TestCopyOCIArtifact error: devfile layer of %s not copied", tt.ref)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		wantErr  bool
+	}{
+		{name: "Case 1: Every minute", schedule: "* * * * *"},
+		{name: "Case 2: Lists, ranges and steps", schedule: "0,30 */2 1-15 1-12/3 1-5"},
+		{name: "Case 3: Macro", schedule: "@daily"},
+		{name: "Case 4: Sunday as 7", schedule: "0 3 * * 5-7"},
+		{name: "Case 5: Missing field", schedule: "0 3 * *", wantErr: true},
+		{name: "Case 6: Value out of range", schedule: "60 3 * * *", wantErr: true},
+		{name: "Case 7: Invalid step", schedule: "*/0 * * * *", wantErr: true},
+		{name: "Case 8: Reversed range", schedule: "0 3 * * 5-1", wantErr: true},
+		{name: "Case 9: Named day", schedule: "0 3 * * MON", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchedule(tt.schedule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TestParseSchedule error: unexpected error %v, expected error: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 1, 31, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule string
+		want     time.Time
+	}{
+		{
+			name:     "Case 1: Every minute",
+			schedule: "* * * * *",
+			want:     time.Date(2024, 1, 31, 10, 18, 0, 0, time.UTC),
+		},
+		{
+			name:     "Case 2: Every day at 3am",
+			schedule: "0 3 * * *",
+			want:     time.Date(2024, 2, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Case 3: Every 15 minutes",
+			schedule: "*/15 * * * *",
+			want:     time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Case 4: Every Sunday",
+			schedule: "0 0 * * 7",
+			want:     time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Case 5: Day of month or day of week",
+			schedule: "0 0 15 * 5",
+			want:     time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Case 6: Leap day",
+			schedule: "0 0 29 2 *",
+			want:     time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Case 7: Never",
+			schedule: "0 0 30 2 *",
+			want:     time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.schedule)
+			if err != nil {
+				t.Fatalf("TestScheduleNext error: %v", err)
+			}
+			if got := schedule.Next(now); !got.Equal(tt.want) {
+				t.Errorf("TestScheduleNext error: next time mismatch, expected: %v got: %v", tt.want, got)
+			}
+		})
+	}
+}
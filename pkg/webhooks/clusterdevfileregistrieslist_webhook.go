@@ -0,0 +1,103 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+// log is for logging in this package.
+var clusterdevfileregistrieslistlog = logf.Log.WithName("clusterdevfileregistrieslist-resource")
+
+const multiCRError = "a ClusterDevfileRegistriesList instance already exists, only one instance can exist in a cluster"
+
+// SetupClusterDevfileRegistriesListWebhookWithManager registers the defaulting and validating webhooks of the ClusterDevfileRegistriesList
+func SetupClusterDevfileRegistriesListWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&registryv1alpha1.ClusterDevfileRegistriesList{}).
+		WithValidator(&ClusterDevfileRegistriesListValidator{Reader: mgr.GetAPIReader()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-registry-devfile-io-v1alpha1-clusterdevfileregistrieslist,mutating=false,failurePolicy=fail,sideEffects=None,groups=registry.devfile.io,resources=clusterdevfileregistrieslists,verbs=create;update,versions=v1alpha1,name=vclusterdevfileregistrieslist.kb.io,admissionReviewVersions=v1
+
+// ClusterDevfileRegistriesListValidator validates the ClusterDevfileRegistriesList resources
+type ClusterDevfileRegistriesListValidator struct {
+	// Reader lists the existing ClusterDevfileRegistriesList resources and reads the RegistryOperatorConfig
+	Reader client.Reader
+}
+
+var _ webhook.CustomValidator = &ClusterDevfileRegistriesListValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *ClusterDevfileRegistriesListValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*registryv1alpha1.ClusterDevfileRegistriesList)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterDevfileRegistriesList but got a %T", obj)
+	}
+	clusterdevfileregistrieslistlog.Info("validate create", "name", cr.Name)
+
+	//limit CR creation to one per cluster
+	clusterDevfileRegistriesList := &registryv1alpha1.ClusterDevfileRegistriesListList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(corev1.NamespaceAll),
+	}
+
+	if err := v.Reader.List(ctx, clusterDevfileRegistriesList, listOpts...); err != nil {
+		return nil, fmt.Errorf("error listing clusterDevfileRegistriesList custom resources: %v", err)
+	}
+
+	if len(clusterDevfileRegistriesList.Items) == 1 {
+		return nil, fmt.Errorf(multiCRError)
+	}
+
+	if err := registryv1alpha1.ValidateURLs(cr.Spec.DevfileRegistries); err != nil {
+		return nil, err
+	}
+
+	return nil, registryv1alpha1.IsNamespaceValid(ctx, v.Reader, cr.Namespace)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *ClusterDevfileRegistriesListValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*registryv1alpha1.ClusterDevfileRegistriesList)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterDevfileRegistriesList but got a %T", newObj)
+	}
+	clusterdevfileregistrieslistlog.Info("validate update", "name", cr.Name)
+	//re-validate the entire list to ensure existing URLs have not gone stale
+	return nil, registryv1alpha1.ValidateURLs(cr.Spec.DevfileRegistries)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *ClusterDevfileRegistriesListValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	if cr, ok := obj.(*registryv1alpha1.ClusterDevfileRegistriesList); ok {
+		clusterdevfileregistrieslistlog.Info("validate delete", "name", cr.Name)
+	}
+	return nil, nil
+}
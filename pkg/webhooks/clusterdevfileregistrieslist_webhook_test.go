@@ -14,12 +14,13 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package v1alpha1
+package webhooks
 
 import (
 	"context"
 	"fmt"
 
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 	. "github.com/devfile/registry-operator/pkg/test"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -49,7 +50,7 @@ var _ = Describe("ClusterDevfileRegistriesList validation webhook", func() {
 		It("Should fail to update and issue an invalid registry URL error message", func() {
 			drlLookupKey := types.NamespacedName{Name: devfileRegistriesListName, Namespace: devfileRegistriesNamespace}
 			err := appendToDevfileRegistriesService(drlLookupKey, "registryName", "registryURL", ClusterListType)
-			Expect(err.Error()).Should(ContainSubstring(fmt.Sprintf(InvalidRegistry, "registryURL")))
+			Expect(err.Error()).Should(ContainSubstring(fmt.Sprintf(registryv1alpha1.InvalidRegistry, "registryURL")))
 		})
 	})
 
@@ -96,9 +97,9 @@ var _ = Describe("ClusterDevfileRegistriesList validation webhook", func() {
 })
 
 // getClusterDevfileRegistriesListCR returns a minimally populated DevfileRegistriesList object for testing
-func getClusterDevfileRegistriesListCR(name string, namespace string, registryName string, registryURL string) *ClusterDevfileRegistriesList {
+func getClusterDevfileRegistriesListCR(name string, namespace string, registryName string, registryURL string) *registryv1alpha1.ClusterDevfileRegistriesList {
 
-	return &ClusterDevfileRegistriesList{
+	return &registryv1alpha1.ClusterDevfileRegistriesList{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: ApiVersion,
 			Kind:       "ClusterDevfileRegistriesList",
@@ -107,8 +108,8 @@ func getClusterDevfileRegistriesListCR(name string, namespace string, registryNa
 			Name:      name,
 			Namespace: namespace,
 		},
-		Spec: DevfileRegistriesListSpec{
-			DevfileRegistries: []DevfileRegistryService{
+		Spec: registryv1alpha1.DevfileRegistriesListSpec{
+			DevfileRegistries: []registryv1alpha1.DevfileRegistryService{
 				{
 					Name: registryName,
 					URL:  registryURL,
@@ -0,0 +1,100 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+// log is for logging in this package.
+var devfileregistrieslistlog = logf.Log.WithName("devfileregistrieslist-resource")
+
+// SetupDevfileRegistriesListWebhookWithManager registers the defaulting and validating webhooks of the DevfileRegistriesList
+func SetupDevfileRegistriesListWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&registryv1alpha1.DevfileRegistriesList{}).
+		WithValidator(&DevfileRegistriesListValidator{Reader: mgr.GetAPIReader()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-registry-devfile-io-v1alpha1-devfileregistrieslist,mutating=false,failurePolicy=fail,sideEffects=None,groups=registry.devfile.io,resources=devfileregistrieslists,verbs=create;update,versions=v1alpha1,name=vdevfileregistrieslist.kb.io,admissionReviewVersions=v1
+
+// DevfileRegistriesListValidator validates the DevfileRegistriesList resources
+type DevfileRegistriesListValidator struct {
+	// Reader lists the existing DevfileRegistriesList resources and reads the RegistryOperatorConfig
+	Reader client.Reader
+}
+
+var _ webhook.CustomValidator = &DevfileRegistriesListValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DevfileRegistriesListValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*registryv1alpha1.DevfileRegistriesList)
+	if !ok {
+		return nil, fmt.Errorf("expected a DevfileRegistriesList but got a %T", obj)
+	}
+	devfileregistrieslistlog.Info("validate create", "name", cr.Name)
+
+	//limit CR creation to one per namespace
+	devfileRegistriesList := &registryv1alpha1.DevfileRegistriesListList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(cr.GetNamespace()),
+	}
+
+	if err := v.Reader.List(ctx, devfileRegistriesList, listOpts...); err != nil {
+		return nil, fmt.Errorf("error listing devfileRegistriesList custom resources: %v", err)
+	}
+
+	if len(devfileRegistriesList.Items) == 1 {
+		return nil, fmt.Errorf("a DevfileRegistriesList instance already exists. Only one instance can exist on a namespace")
+	}
+
+	if err := registryv1alpha1.ValidateURLs(cr.Spec.DevfileRegistries); err != nil {
+		return nil, err
+	}
+
+	return nil, registryv1alpha1.IsNamespaceValid(ctx, v.Reader, cr.Namespace)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DevfileRegistriesListValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*registryv1alpha1.DevfileRegistriesList)
+	if !ok {
+		return nil, fmt.Errorf("expected a DevfileRegistriesList but got a %T", newObj)
+	}
+	devfileregistrieslistlog.Info("validate update", "name", cr.Name)
+	//re-validate the entire list to ensure existing URLs have not gone stale
+	return nil, registryv1alpha1.ValidateURLs(cr.Spec.DevfileRegistries)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DevfileRegistriesListValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	if cr, ok := obj.(*registryv1alpha1.DevfileRegistriesList); ok {
+		devfileregistrieslistlog.Info("validate delete", "name", cr.Name)
+	}
+	return nil, nil
+}
@@ -14,12 +14,13 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package v1alpha1
+package webhooks
 
 import (
 	"context"
 	"fmt"
 
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 	. "github.com/devfile/registry-operator/pkg/test"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -49,7 +50,7 @@ var _ = Describe("DevfileRegistriesList validation webhook", func() {
 		It("Should fail to update and issue an invalid registry URL error message", func() {
 			drlLookupKey := types.NamespacedName{Name: devfileRegistriesListName, Namespace: devfileRegistriesNamespace}
 			err := appendToDevfileRegistriesService(drlLookupKey, "registryName", "registryURL", NamespaceListType)
-			Expect(err.Error()).Should(ContainSubstring(fmt.Sprintf(InvalidRegistry, "registryURL")))
+			Expect(err.Error()).Should(ContainSubstring(fmt.Sprintf(registryv1alpha1.InvalidRegistry, "registryURL")))
 		})
 	})
 
@@ -95,9 +96,9 @@ var _ = Describe("DevfileRegistriesList validation webhook", func() {
 })
 
 // getDevfileRegistriesListCR returns a minimally populated DevfileRegistriesList object for testing
-func getDevfileRegistriesListCR(name string, namespace string, registryName string, registryURL string) *DevfileRegistriesList {
+func getDevfileRegistriesListCR(name string, namespace string, registryName string, registryURL string) *registryv1alpha1.DevfileRegistriesList {
 
-	return &DevfileRegistriesList{
+	return &registryv1alpha1.DevfileRegistriesList{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: ApiVersion,
 			Kind:       "DevfileRegistriesList",
@@ -106,8 +107,8 @@ func getDevfileRegistriesListCR(name string, namespace string, registryName stri
 			Name:      name,
 			Namespace: namespace,
 		},
-		Spec: DevfileRegistriesListSpec{
-			DevfileRegistries: []DevfileRegistryService{
+		Spec: registryv1alpha1.DevfileRegistriesListSpec{
+			DevfileRegistries: []registryv1alpha1.DevfileRegistryService{
 				{
 					Name: registryName,
 					URL:  registryURL,
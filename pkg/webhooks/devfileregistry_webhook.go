@@ -0,0 +1,154 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/registry"
+)
+
+// log is for logging in this package.
+var devfileregistrylog = logf.Log.WithName("devfileregistry-resource")
+
+// SetupDevfileRegistryWebhookWithManager registers the defaulting and validating webhooks of the DevfileRegistry
+func SetupDevfileRegistryWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&registryv1alpha1.DevfileRegistry{}).
+		WithValidator(&DevfileRegistryValidator{Reader: mgr.GetAPIReader()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-registry-devfile-io-v1alpha1-devfileregistry,mutating=false,failurePolicy=fail,sideEffects=None,groups=registry.devfile.io,resources=devfileregistries,verbs=create;update,versions=v1alpha1,name=vdevfileregistry.kb.io,admissionReviewVersions=v1
+
+// DevfileRegistryValidator validates the DevfileRegistry resources
+type DevfileRegistryValidator struct {
+	// Reader reads the config maps referenced by the DevfileRegistry resources and the RegistryOperatorConfig.
+	// Config maps are not validated if it is unset.
+	Reader client.Reader
+}
+
+var _ webhook.CustomValidator = &DevfileRegistryValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DevfileRegistryValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*registryv1alpha1.DevfileRegistry)
+	if !ok {
+		return nil, fmt.Errorf("expected a DevfileRegistry but got a %T", obj)
+	}
+	devfileregistrylog.Info("validate create", "name", cr.Name)
+	var errors error
+	if err := registryv1alpha1.IsNamespaceValid(ctx, v.Reader, cr.Namespace); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	warnings, err := v.validate(ctx, cr)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	return warnings, errors
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DevfileRegistryValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*registryv1alpha1.DevfileRegistry)
+	if !ok {
+		return nil, fmt.Errorf("expected a DevfileRegistry but got a %T", newObj)
+	}
+	devfileregistrylog.Info("validate update", "name", cr.Name)
+	return v.validate(ctx, cr)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DevfileRegistryValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	if cr, ok := obj.(*registryv1alpha1.DevfileRegistry); ok {
+		devfileregistrylog.Info("validate delete", "name", cr.Name)
+	}
+	return nil, nil
+}
+
+// validate runs the validations common to the creation and the update of a DevfileRegistry
+func (v *DevfileRegistryValidator) validate(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (admission.Warnings, error) {
+	var errors error
+	if err := registryv1alpha1.IsPodTemplateOverrideValid(cr.Spec.PodTemplateOverride); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	if err := registryv1alpha1.IsBackupScheduleValid(cr.Spec); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	if err := registryv1alpha1.IsVolumeSizeValid(cr.Spec); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	if err := registryv1alpha1.IsGitSourceValid(cr.Spec); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	if err := registryv1alpha1.IsMirrorSourceValid(cr.Spec); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	if err := registryv1alpha1.IsOCIRegistryExposureValid(cr.Spec); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	warnings, err := v.validateRegistryConfig(ctx, cr)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	warnings = append(warnings, registryv1alpha1.StorageWarnings(cr.Spec)...)
+	warnings = append(warnings, registryv1alpha1.GarbageCollectionWarnings(cr.Spec)...)
+	warnings = append(warnings, registryv1alpha1.MirrorWarnings(cr.Spec)...)
+	return append(registryv1alpha1.ReplicasWarnings(cr.Spec), warnings...), errors
+}
+
+// validateRegistryConfig validates the OCI registry configuration the DevfileRegistry is deployed with, that is the
+// configuration held by the referenced config map merged over the one generated by the operator. A config map that
+// does not exist yet only raises a warning.
+func (v *DevfileRegistryValidator) validateRegistryConfig(ctx context.Context, cr *registryv1alpha1.DevfileRegistry) (admission.Warnings, error) {
+	ref := cr.Spec.OciRegistry.Config
+	if ref == nil || v.Reader == nil {
+		return nil, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := v.Reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: cr.Namespace}, cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	config, found := cm.Data[ref.Key]
+	if err != nil || !found {
+		if ref.Optional != nil && *ref.Optional {
+			return nil, nil
+		}
+		return admission.Warnings{fmt.Sprintf(registryv1alpha1.MissingConfig, ref.Name, ref.Key)}, nil
+	}
+
+	registryConfig, err := registry.MergeRegistryConfig(registry.GetDefaultRegistryConfig(cr), config)
+	if err != nil {
+		return nil, fmt.Errorf(registryv1alpha1.InvalidConfig, err)
+	}
+	return nil, registryv1alpha1.IsRegistryConfigValid(registryConfig)
+}
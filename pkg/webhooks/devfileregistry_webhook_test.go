@@ -0,0 +1,132 @@
+//
+//
+// Copyright Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	. "github.com/devfile/registry-operator/pkg/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("DevfileRegistry validation webhook", func() {
+	Context("Create DevfileRegistry CR with valid values", func() {
+		It("Should create a new CR in a non-default namespace called 'main'", func() {
+			ctx := context.Background()
+			Expect(k8sClient.Create(ctx, getDevfileRegistryCR("devfileregistry", devfileRegistriesNamespace))).Should(Succeed())
+		})
+	})
+
+	Context("Create DevfileRegistry CR in forbidden default namespace", func() {
+		It("Should fail to create a new CR in the default namespace", func() {
+			ctx := context.Background()
+			Expect(k8sClient.Create(ctx, getDevfileRegistryCR("default-devfileregistry", "default"))).ShouldNot(Succeed())
+		})
+	})
+})
+
+func getDevfileRegistryCR(name string, namespace string) *registryv1alpha1.DevfileRegistry {
+	return &registryv1alpha1.DevfileRegistry{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ApiVersion,
+			Kind:       "DevfileRegistry",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+func TestDevfileRegistryValidatorValidateRegistryConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = registryv1alpha1.AddToScheme(scheme)
+
+	tests := []struct {
+		name         string
+		config       string
+		auth         *registryv1alpha1.DevfileRegistrySpecOCIAuth
+		wantWarnings bool
+		wantErr      bool
+	}{
+		{
+			name:   "Case 1: Config changing the log level",
+			config: "log:\n  level: debug\n",
+		},
+		{
+			name:    "Case 2: Config changing the OCI registry port",
+			config:  "http:\n  addr: :8000\n",
+			wantErr: true,
+		},
+		{
+			name:   "Case 3: Config with an auth method and the OCI registry authentication disabled",
+			config: "auth:\n  token:\n    realm: https://auth.example.com\n",
+		},
+		{
+			name:    "Case 4: Config with an auth method merged with the generated htpasswd authentication",
+			config:  "auth:\n  token:\n    realm: https://auth.example.com\n",
+			auth:    &registryv1alpha1.DevfileRegistrySpecOCIAuth{},
+			wantErr: true,
+		},
+		{
+			name:   "Case 5: Config replacing the generated storage driver",
+			config: "storage:\n  inmemory: {}\n",
+		},
+		{
+			name:         "Case 6: Missing config map",
+			wantWarnings: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := getDevfileRegistryCR("devfileregistry", devfileRegistriesNamespace)
+			cr.Spec.OciRegistry.Config = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "registry-config"},
+				Key:                  "config.yml",
+			}
+			cr.Spec.OciRegistry.Auth = tt.auth
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.config != "" {
+				builder.WithObjects(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "registry-config", Namespace: devfileRegistriesNamespace},
+					Data:       map[string]string{"config.yml": tt.config},
+				})
+			}
+			validator := &DevfileRegistryValidator{Reader: builder.Build()}
+
+			warnings, err := validator.validateRegistryConfig(context.Background(), cr)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantWarnings, len(warnings) > 0, "unexpected warnings %v", warnings)
+		})
+	}
+}
@@ -14,7 +14,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package v1alpha1
+package webhooks
 
 import (
 	"context"
@@ -25,6 +25,7 @@ import (
 	"testing"
 	"time"
 
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 	. "github.com/devfile/registry-operator/pkg/test"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -99,7 +100,7 @@ var _ = BeforeSuite(func() {
 	Expect(cfg).NotTo(BeNil())
 
 	scheme := runtime.NewScheme()
-	err = AddToScheme(scheme)
+	err = registryv1alpha1.AddToScheme(scheme)
 	Expect(err).NotTo(HaveOccurred())
 
 	err = admissionv1beta1.AddToScheme(scheme)
@@ -134,13 +135,13 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = (&DevfileRegistry{}).SetupWebhookWithManager(mgr)
+	err = SetupDevfileRegistryWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = (&DevfileRegistriesList{}).SetupWebhookWithManager(mgr)
+	err = SetupDevfileRegistriesListWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = (&ClusterDevfileRegistriesList{}).SetupWebhookWithManager(mgr)
+	err = SetupClusterDevfileRegistriesListWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
 	//+kubebuilder:scaffold:webhook
@@ -180,8 +181,8 @@ var _ = AfterSuite(func() {
 // deleteCRList removes the cluster or namespace CR list from the cluster
 func deleteCRList(drlLookupKey types.NamespacedName, f ListType) {
 
-	cl := &ClusterDevfileRegistriesList{}
-	nl := &DevfileRegistriesList{}
+	cl := &registryv1alpha1.ClusterDevfileRegistriesList{}
+	nl := &registryv1alpha1.DevfileRegistriesList{}
 	// Delete
 	Eventually(func() error {
 		if f == NamespaceListType {
@@ -207,9 +208,9 @@ func deleteCRList(drlLookupKey types.NamespacedName, f ListType) {
 // deleteFromDevfileRegistriesListCR validates that a  DevfileRegistryService object can be deleted from the list as an update to the CR
 func deleteFromDevfileRegistriesService(lookupKey types.NamespacedName, rName string, lType ListType) error {
 	ctx := context.Background()
-	nl := &DevfileRegistriesList{}
-	cl := &ClusterDevfileRegistriesList{}
-	var registriesList []DevfileRegistryService
+	nl := &registryv1alpha1.DevfileRegistriesList{}
+	cl := &registryv1alpha1.ClusterDevfileRegistriesList{}
+	var registriesList []registryv1alpha1.DevfileRegistryService
 	var err error
 
 	if lType == NamespaceListType {
@@ -227,8 +228,8 @@ func deleteFromDevfileRegistriesService(lookupKey types.NamespacedName, rName st
 	}
 
 	//update list in existing CR
-	var newList []DevfileRegistryService
-	newList = make([]DevfileRegistryService, 0, len(registriesList))
+	var newList []registryv1alpha1.DevfileRegistryService
+	newList = make([]registryv1alpha1.DevfileRegistryService, 0, len(registriesList))
 
 	for i := range registriesList {
 		if registriesList[i].Name != rName {
@@ -250,8 +251,8 @@ func deleteFromDevfileRegistriesService(lookupKey types.NamespacedName, rName st
 // appendToDevfileRegistriesService validates that a new DevfileRegistryService object can be added to update an existing CR
 func appendToDevfileRegistriesService(lookupKey types.NamespacedName, rName string, rUrl string, lType ListType) error {
 	ctx := context.Background()
-	cl := &ClusterDevfileRegistriesList{}
-	nl := &DevfileRegistriesList{}
+	cl := &registryv1alpha1.ClusterDevfileRegistriesList{}
+	nl := &registryv1alpha1.DevfileRegistriesList{}
 
 	var err error
 	if lType == NamespaceListType {
@@ -261,7 +262,7 @@ func appendToDevfileRegistriesService(lookupKey types.NamespacedName, rName stri
 		}
 		//update list in existing CR
 		registriesList := nl.Spec.DevfileRegistries
-		registriesList = append(registriesList, DevfileRegistryService{Name: rName, URL: rUrl})
+		registriesList = append(registriesList, registryv1alpha1.DevfileRegistryService{Name: rName, URL: rUrl})
 		nl.Spec.DevfileRegistries = registriesList
 		err = k8sClient.Update(ctx, nl)
 	} else {
@@ -271,7 +272,7 @@ func appendToDevfileRegistriesService(lookupKey types.NamespacedName, rName stri
 		}
 		//update list in existing CR
 		registriesList := cl.Spec.DevfileRegistries
-		registriesList = append(registriesList, DevfileRegistryService{Name: rName, URL: rUrl})
+		registriesList = append(registriesList, registryv1alpha1.DevfileRegistryService{Name: rName, URL: rUrl})
 		cl.Spec.DevfileRegistries = registriesList
 		err = k8sClient.Update(ctx, cl)
 	}